@@ -0,0 +1,81 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// LockReleaseTotals are the aggregate amounts of a denomination that have been locked and
+// released by the bridge.
+type LockReleaseTotals struct {
+	// Locked is the total amount locked on this side to be bridged to the remote side.
+	Locked quantity.Quantity `json:"locked"`
+
+	// Locks is the number of locks.
+	Locks uint64 `json:"locks"`
+
+	// Released is the total amount released on this side after being bridged from the remote
+	// side.
+	Released quantity.Quantity `json:"released"`
+
+	// Releases is the number of releases.
+	Releases uint64 `json:"releases"`
+}
+
+// Implements V1.
+func (a *v1) Accounting(ctx context.Context, round uint64) (map[types.Denomination]LockReleaseTotals, error) {
+	if round == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+	genesis, err := a.rc.GetGenesisBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis block: %w", err)
+	}
+
+	totals := make(map[types.Denomination]LockReleaseTotals)
+	add := func(amount *types.BaseUnits, lock bool) error {
+		t := totals[amount.Denomination]
+		if lock {
+			if err := t.Locked.Add(&amount.Amount); err != nil {
+				return fmt.Errorf("failed to add locked amount: %w", err)
+			}
+			t.Locks++
+		} else {
+			if err := t.Released.Add(&amount.Amount); err != nil {
+				return fmt.Errorf("failed to add released amount: %w", err)
+			}
+			t.Releases++
+		}
+		totals[amount.Denomination] = t
+		return nil
+	}
+
+	err = a.ReplayEvents(ctx, genesis.Header.Round, round, func(_ uint64, events []*Event) error {
+		for _, ev := range events {
+			var err error
+			switch {
+			case ev.Lock != nil:
+				err = add(&ev.Lock.Amount, true)
+			case ev.Release != nil:
+				err = add(&ev.Release.Amount, false)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestAccounting(t *testing.T) {
+	ctx := context.Background()
+
+	// Amounts beyond the range of uint64 are summed up exactly.
+	var large quantity.Quantity
+	if err := large.FromBigInt(new(big.Int).Lsh(big.NewInt(1), 70)); err != nil {
+		t.Fatalf("failed to create large amount: %s", err)
+	}
+	lock := func(id uint64, amount types.BaseUnits) *coreClient.Event {
+		return &coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: id, Amount: amount})}
+	}
+	release := func(id uint64, amount types.BaseUnits) *coreClient.Event {
+		return &coreClient.Event{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: id, Amount: amount})}
+	}
+
+	rc := &chainClient{
+		latest: 5,
+		events: map[uint64][]*coreClient.Event{
+			1: {lock(0, types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination))},
+			2: {
+				lock(1, types.NewBaseUnits(*quantity.NewFromUint64(5), "oETH")),
+				release(0, types.NewBaseUnits(*quantity.NewFromUint64(30), types.NativeDenomination)),
+			},
+			4: {lock(2, types.NewBaseUnits(large, types.NativeDenomination))},
+			5: {release(1, types.NewBaseUnits(*quantity.NewFromUint64(2), "oETH"))},
+		},
+	}
+	v := NewV1(rc)
+
+	largeTotal := large.Clone()
+	if err := largeTotal.Add(quantity.NewFromUint64(100)); err != nil {
+		t.Fatalf("failed to add: %s", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		round    uint64
+		expected map[types.Denomination]LockReleaseTotals
+	}{
+		{"Genesis", 0, map[types.Denomination]LockReleaseTotals{}},
+		{"Partial", 2, map[types.Denomination]LockReleaseTotals{
+			types.NativeDenomination: {Locked: *quantity.NewFromUint64(100), Locks: 1, Released: *quantity.NewFromUint64(30), Releases: 1},
+			"oETH":                   {Locked: *quantity.NewFromUint64(5), Locks: 1},
+		}},
+		{"Latest", client.RoundLatest, map[types.Denomination]LockReleaseTotals{
+			types.NativeDenomination: {Locked: *largeTotal, Locks: 2, Released: *quantity.NewFromUint64(30), Releases: 1},
+			"oETH":                   {Locked: *quantity.NewFromUint64(5), Locks: 1, Released: *quantity.NewFromUint64(2), Releases: 1},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			totals, err := v.Accounting(ctx, tc.round)
+			if err != nil {
+				t.Fatalf("Accounting: %s", err)
+			}
+			if len(totals) != len(tc.expected) {
+				t.Fatalf("expected totals of %d denominations, got %+v", len(tc.expected), totals)
+			}
+			for denomination, expected := range tc.expected {
+				actual, ok := totals[denomination]
+				switch {
+				case !ok:
+					t.Fatalf("missing totals of %s", denomination)
+				case actual.Locked.Cmp(&expected.Locked) != 0 || actual.Locks != expected.Locks:
+					t.Fatalf("expected %s locked in %d locks of %s, got %s in %d", expected.Locked, expected.Locks, denomination, actual.Locked, actual.Locks)
+				case actual.Released.Cmp(&expected.Released) != 0 || actual.Releases != expected.Releases:
+					t.Fatalf("expected %s released in %d releases of %s, got %s in %d", expected.Released, expected.Releases, denomination, actual.Released, actual.Releases)
+				}
+			}
+		})
+	}
+
+	rc.eventsErr = errors.New("unavailable")
+	if _, err := v.Accounting(ctx, client.RoundLatest); !errors.Is(err, rc.eventsErr) {
+		t.Fatalf("expected the error fetching events, got %v", err)
+	}
+}
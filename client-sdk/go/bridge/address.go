@@ -0,0 +1,32 @@
+package bridge
+
+import (
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// AddressOf returns the runtime account address of the given signer.
+func AddressOf(signer signature.Signer) types.Address {
+	return AddressOfPublicKey(signer.Public())
+}
+
+// AddressOfPublicKey returns the runtime account address of the given public key.
+func AddressOfPublicKey(pk signature.PublicKey) types.Address {
+	return types.NewAddress(derefPublicKey(pk))
+}
+
+// derefPublicKey returns the given public key by value. Decoded public keys (e.g., the witnesses
+// of the parameters or the signers of a transaction) are pointers, which are neither supported by
+// types.NewAddress nor considered equal to other public keys.
+func derefPublicKey(pk signature.PublicKey) signature.PublicKey {
+	switch k := pk.(type) {
+	case *ed25519.PublicKey:
+		return *k
+	case *secp256k1.PublicKey:
+		return *k
+	default:
+		return pk
+	}
+}
@@ -0,0 +1,71 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrDenominationMismatch is the error returned when combining amounts of different
+	// denominations.
+	ErrDenominationMismatch = errors.New("bridge: denomination mismatch")
+
+	// ErrAmountOverflow is the error returned when an amount exceeds MaxAmount.
+	ErrAmountOverflow = errors.New("bridge: amount overflow")
+
+	// ErrAmountUnderflow is the error returned when an amount would become negative.
+	ErrAmountUnderflow = errors.New("bridge: amount underflow")
+)
+
+// MaxAmount is the largest amount the runtime can represent (amounts are 128-bit unsigned
+// integers).
+var MaxAmount = func() *quantity.Quantity {
+	var q quantity.Quantity
+	max := new(big.Int).Lsh(big.NewInt(1), 128)
+	if err := q.FromBigInt(max.Sub(max, big.NewInt(1))); err != nil {
+		panic(err)
+	}
+	return &q
+}()
+
+// AddBaseUnits returns the sum of the given amounts, which must be of the same denomination.
+//
+// ErrDenominationMismatch is returned in case the denominations differ and ErrAmountOverflow in
+// case the sum exceeds MaxAmount.
+func AddBaseUnits(a, b types.BaseUnits) (types.BaseUnits, error) {
+	if a.Denomination != b.Denomination {
+		return types.BaseUnits{}, fmt.Errorf("%w: %s and %s", ErrDenominationMismatch, a.Denomination, b.Denomination)
+	}
+	sum := a.Amount.Clone()
+	if err := sum.Add(&b.Amount); err != nil {
+		return types.BaseUnits{}, fmt.Errorf("bridge: failed to add amounts: %w", err)
+	}
+	if sum.Cmp(MaxAmount) > 0 {
+		return types.BaseUnits{}, fmt.Errorf("%w: %s + %s", ErrAmountOverflow, a.Amount, b.Amount)
+	}
+	return types.NewBaseUnits(*sum, a.Denomination), nil
+}
+
+// SubBaseUnits returns the difference of the given amounts, which must be of the same
+// denomination.
+//
+// ErrDenominationMismatch is returned in case the denominations differ and ErrAmountUnderflow in
+// case b is larger than a.
+func SubBaseUnits(a, b types.BaseUnits) (types.BaseUnits, error) {
+	if a.Denomination != b.Denomination {
+		return types.BaseUnits{}, fmt.Errorf("%w: %s and %s", ErrDenominationMismatch, a.Denomination, b.Denomination)
+	}
+	if a.Amount.Cmp(&b.Amount) < 0 {
+		return types.BaseUnits{}, fmt.Errorf("%w: %s - %s", ErrAmountUnderflow, a.Amount, b.Amount)
+	}
+	diff := a.Amount.Clone()
+	if err := diff.Sub(&b.Amount); err != nil {
+		return types.BaseUnits{}, fmt.Errorf("bridge: failed to subtract amounts: %w", err)
+	}
+	return types.NewBaseUnits(*diff, a.Denomination), nil
+}
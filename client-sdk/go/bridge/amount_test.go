@@ -0,0 +1,60 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestBaseUnitsArithmetic(t *testing.T) {
+	native := func(n uint64) types.BaseUnits {
+		return types.NewBaseUnits(*quantity.NewFromUint64(n), types.NativeDenomination)
+	}
+
+	sum, err := AddBaseUnits(native(10), native(5))
+	if err != nil || !baseUnitsEqual(&sum, &types.BaseUnits{Amount: *quantity.NewFromUint64(15)}) {
+		t.Fatalf("unexpected sum: %s (err: %v)", sum.Amount, err)
+	}
+	diff, err := SubBaseUnits(native(10), native(10))
+	if err != nil || !diff.Amount.IsZero() || diff.Denomination != types.NativeDenomination {
+		t.Fatalf("unexpected difference: %s (err: %v)", diff.Amount, err)
+	}
+
+	// Operands must not be modified.
+	a, b := native(10), native(5)
+	if _, err = AddBaseUnits(a, b); err != nil {
+		t.Fatalf("failed to add amounts: %s", err)
+	}
+	if _, err = SubBaseUnits(a, b); err != nil {
+		t.Fatalf("failed to subtract amounts: %s", err)
+	}
+	if a.Amount.Cmp(quantity.NewFromUint64(10)) != 0 || b.Amount.Cmp(quantity.NewFromUint64(5)) != 0 {
+		t.Fatalf("operands have been modified: %s, %s", a.Amount, b.Amount)
+	}
+
+	if _, err = SubBaseUnits(native(5), native(10)); !errors.Is(err, ErrAmountUnderflow) {
+		t.Fatalf("expected ErrAmountUnderflow, got %v", err)
+	}
+	max := types.NewBaseUnits(*MaxAmount.Clone(), types.NativeDenomination)
+	if _, err = AddBaseUnits(max, native(0)); err != nil {
+		t.Fatalf("adding zero to the maximum amount should succeed: %s", err)
+	}
+	if _, err = AddBaseUnits(max, native(1)); !errors.Is(err, ErrAmountOverflow) {
+		t.Fatalf("expected ErrAmountOverflow, got %v", err)
+	}
+}
+
+func TestBaseUnitsDenominationMismatch(t *testing.T) {
+	a := types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination)
+	b := types.NewBaseUnits(*quantity.NewFromUint64(10), types.Denomination("oETH"))
+
+	if _, err := AddBaseUnits(a, b); !errors.Is(err, ErrDenominationMismatch) {
+		t.Fatalf("expected ErrDenominationMismatch, got %v", err)
+	}
+	if _, err := SubBaseUnits(b, a); !errors.Is(err, ErrDenominationMismatch) {
+		t.Fatalf("expected ErrDenominationMismatch, got %v", err)
+	}
+}
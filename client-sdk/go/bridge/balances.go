@@ -0,0 +1,108 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// defaultBalanceFetchConcurrency is the default number of accounts whose balances are fetched
+// concurrently.
+const defaultBalanceFetchConcurrency = 4
+
+// BalancesError is the error returned when fetching the balances of some accounts failed.
+type BalancesError struct {
+	// Errors are the errors encountered for each of the failed accounts.
+	Errors map[types.Address]error
+}
+
+// Error implements error.
+func (e *BalancesError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for addr, err := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", addr, err))
+	}
+	sort.Strings(msgs)
+	return fmt.Sprintf("bridge: failed to fetch balances of %d account(s): %s", len(msgs), strings.Join(msgs, "; "))
+}
+
+// FetchBalances fetches the latest balances of the given account.
+func FetchBalances(ctx context.Context, rc client.RuntimeClient, addr types.Address) (map[types.Denomination]*quantity.Quantity, error) {
+	rsp, err := accounts.NewV1(rc).Balances(ctx, client.RoundLatest, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account balances: %w", err)
+	}
+
+	balances := make(map[types.Denomination]*quantity.Quantity, len(rsp.Balances))
+	for denom, balance := range rsp.Balances {
+		balances[denom] = balance.Clone()
+	}
+	return balances, nil
+}
+
+// FetchBalancesMulti fetches the latest balances of all given accounts concurrently.
+//
+// In case fetching the balances of some of the accounts fails, the balances of the remaining
+// accounts are still returned together with a *BalancesError describing the failures.
+func FetchBalancesMulti(
+	ctx context.Context,
+	rc client.RuntimeClient,
+	addrs []types.Address,
+) (map[types.Address]map[types.Denomination]*quantity.Quantity, error) {
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+	)
+	results := make(map[types.Address]map[types.Denomination]*quantity.Quantity, len(addrs))
+	errs := make(map[types.Address]error)
+	sem := make(chan struct{}, defaultBalanceFetchConcurrency)
+	for _, addr := range addrs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(addr types.Address) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			balances, err := FetchBalances(ctx, rc, addr)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs[addr] = err
+				return
+			}
+			results[addr] = balances
+		}(addr)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, &BalancesError{Errors: errs}
+	}
+	return results, nil
+}
+
+// precheckBalance verifies that the given account holds at least the given amount.
+func (a *v1) precheckBalance(ctx context.Context, addr types.Address, amount *types.BaseUnits) error {
+	balances, err := FetchBalances(ctx, a.rc, addr)
+	if err != nil {
+		return err
+	}
+	balance := balances[amount.Denomination]
+	if balance == nil || balance.Cmp(&amount.Amount) < 0 {
+		available := quantity.NewQuantity()
+		if balance != nil {
+			available = balance
+		}
+		return fmt.Errorf("%w: %s has %s %s, need %s", ErrInsufficientBalance, addr, available, amount.Denomination, &amount.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// balancesClient is a runtime client returning the configured native balances of accounts and
+// failing balance queries of the configured accounts, recording the maximum number of concurrent
+// queries.
+type balancesClient struct {
+	client.RuntimeClient
+
+	balances map[types.Address]uint64
+	failing  map[types.Address]error
+
+	lock        sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (rc *balancesClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	if method != "accounts.Balances" {
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	addr := args.(*accounts.BalancesQuery).Address
+
+	rc.lock.Lock()
+	rc.inFlight++
+	if rc.inFlight > rc.maxInFlight {
+		rc.maxInFlight = rc.inFlight
+	}
+	rc.lock.Unlock()
+	defer func() {
+		rc.lock.Lock()
+		rc.inFlight--
+		rc.lock.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	if err := rc.failing[addr]; err != nil {
+		return err
+	}
+	*rsp.(*accounts.AccountBalances) = accounts.AccountBalances{
+		Balances: map[types.Denomination]quantity.Quantity{
+			types.NativeDenomination: *quantity.NewFromUint64(rc.balances[addr]),
+		},
+	}
+	return nil
+}
+
+func TestFetchBalancesMulti(t *testing.T) {
+	errUnavailable := errors.New("node unavailable")
+	rc := &balancesClient{
+		balances: map[types.Address]uint64{
+			sdkTesting.Alice.Address:   100,
+			sdkTesting.Bob.Address:     200,
+			sdkTesting.Charlie.Address: 300,
+		},
+		failing: map[types.Address]error{
+			sdkTesting.Dave.Address: errUnavailable,
+		},
+	}
+	var addrs []types.Address
+	for i := 0; i < 3; i++ {
+		addrs = append(addrs, sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address)
+	}
+	addrs = append(addrs, sdkTesting.Dave.Address)
+
+	balances, err := FetchBalancesMulti(context.Background(), rc, addrs)
+
+	// The balances of the remaining accounts are returned together with the failures.
+	var balancesErr *BalancesError
+	if !errors.As(err, &balancesErr) {
+		t.Fatalf("expected BalancesError, got %v", err)
+	}
+	if len(balancesErr.Errors) != 1 || !errors.Is(balancesErr.Errors[sdkTesting.Dave.Address], errUnavailable) {
+		t.Fatalf("expected only the balances of Dave to fail, got %v", balancesErr.Errors)
+	}
+	if !strings.Contains(err.Error(), sdkTesting.Dave.Address.String()) {
+		t.Fatalf("expected the error to name the failed account, got '%s'", err)
+	}
+	if len(balances) != 3 {
+		t.Fatalf("expected balances of 3 accounts, got %d", len(balances))
+	}
+	for addr, expected := range rc.balances {
+		if balance := balances[addr][types.NativeDenomination]; balance == nil || balance.Cmp(quantity.NewFromUint64(expected)) != 0 {
+			t.Fatalf("expected balance %d for %s, got %v", expected, addr, balance)
+		}
+	}
+	if rc.maxInFlight > defaultBalanceFetchConcurrency {
+		t.Fatalf("expected at most %d concurrent queries, got %d", defaultBalanceFetchConcurrency, rc.maxInFlight)
+	}
+
+	// Without failures no error is returned.
+	balances, err = FetchBalancesMulti(context.Background(), rc, addrs[:3])
+	if err != nil {
+		t.Fatalf("FetchBalancesMulti: %s", err)
+	}
+	if len(balances) != 3 {
+		t.Fatalf("expected balances of 3 accounts, got %d", len(balances))
+	}
+}
+
+func TestSubmitLockBalancePrecheck(t *testing.T) {
+	body := &Lock{
+		Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	}
+
+	for _, tc := range []struct {
+		name      string
+		balance   uint64
+		precheck  bool
+		submitted bool
+	}{
+		{"Sufficient", 10, true, true},
+		{"Insufficient", 9, true, false},
+		// Without the precheck the runtime is left to reject the lock.
+		{"Disabled", 9, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := newSubmitLockClient()
+			rc.balances = map[types.Address]uint64{sdkTesting.Alice.Address: tc.balance}
+
+			var opts []SubmitOption
+			if tc.precheck {
+				opts = append(opts, WithBalancePrecheck())
+			}
+			_, err := NewV1(rc).SubmitLock(context.Background(), sdkTesting.Alice.Signer, body, opts...)
+			switch {
+			case tc.submitted && err != nil:
+				t.Fatalf("SubmitLock: %s", err)
+			case !tc.submitted && !errors.Is(err, ErrInsufficientBalance):
+				t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+			}
+			if submitted := len(rc.txs) > 0; submitted != tc.submitted {
+				t.Fatalf("expected submitted %t, got %t", tc.submitted, submitted)
+			}
+		})
+	}
+}
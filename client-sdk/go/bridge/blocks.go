@@ -0,0 +1,289 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// ResubscribePolicy configures how a block subscription is reestablished after it drops.
+type ResubscribePolicy struct {
+	// MaxRetries is the maximum number of consecutive attempts to resubscribe before giving up.
+	// Zero disables resubscription.
+	MaxRetries uint64
+
+	// InitialInterval is the delay before the first resubscription attempt. Subsequent attempts
+	// are delayed exponentially.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum delay between resubscription attempts.
+	MaxInterval time.Duration
+}
+
+// DefaultResubscribePolicy is the default resubscribe policy.
+var DefaultResubscribePolicy = ResubscribePolicy{
+	MaxRetries:      10,
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     30 * time.Second,
+}
+
+func (p *ResubscribePolicy) backOff(ctx context.Context, clock Clock) backoff.BackOff {
+	eb := backoff.NewExponentialBackOff()
+	eb.Clock = clock
+	eb.InitialInterval = p.InitialInterval
+	eb.MaxInterval = p.MaxInterval
+	eb.MaxElapsedTime = 0
+	return backoff.WithContext(backoff.WithMaxRetries(eb, p.MaxRetries), ctx)
+}
+
+// WatchOption is an option for configuring watch helpers.
+type WatchOption func(o *watchOptions)
+
+type watchOptions struct {
+	resubscribe   ResubscribePolicy
+	onReorg       func(ev *ReorgEvent)
+	eventBuffer   int
+	overflow      OverflowPolicy
+	confirmations map[OperationKind]uint64
+
+	startRound      uint64
+	startRoundValid bool
+}
+
+func newWatchOptions(opts ...WatchOption) *watchOptions {
+	o := &watchOptions{
+		resubscribe: DefaultResubscribePolicy,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithResubscribePolicy configures the policy used to resubscribe in case the underlying block
+// subscription drops.
+//
+// By default DefaultResubscribePolicy is used.
+func WithResubscribePolicy(policy ResubscribePolicy) WatchOption {
+	return func(o *watchOptions) {
+		o.resubscribe = policy
+	}
+}
+
+// ReorgEvent is emitted by block watchers when the runtime goes back to an already seen round,
+// either with a lower round or with a different block for the last seen round.
+type ReorgEvent struct {
+	// FromRound is the last round delivered before the reorganization.
+	FromRound uint64 `json:"from_round"`
+
+	// ToRound is the round the runtime went back to. Delivery resumes with this round so
+	// consumers should invalidate any state derived from rounds [ToRound, FromRound].
+	ToRound uint64 `json:"to_round"`
+}
+
+// WithReorgHandler configures a handler invoked by block watchers when a reorganization is
+// detected, before the block of the round the runtime went back to is delivered.
+//
+// By default reorganizations are only logged.
+func WithReorgHandler(fn func(ev *ReorgEvent)) WatchOption {
+	return func(o *watchOptions) {
+		o.onReorg = fn
+	}
+}
+
+// BlockSubscription is a runtime block subscription that transparently resubscribes in case the
+// underlying subscription drops.
+type BlockSubscription struct {
+	v      *v1
+	opts   *watchOptions
+	ch     chan *roothash.AnnotatedBlock
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+
+	lastRound      uint64
+	lastRoundHash  hash.Hash
+	lastRoundValid bool
+}
+
+// Close unsubscribes the subscription.
+//
+// Once Close returns, the goroutines backing the subscription have terminated and the block
+// channel has been closed. Close may be called multiple times.
+func (s *BlockSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// Err returns the error that caused the subscription to terminate. It must only be called after
+// the block channel has been closed.
+func (s *BlockSubscription) Err() error {
+	return s.err
+}
+
+func (s *BlockSubscription) worker(ctx context.Context, ch <-chan *roothash.AnnotatedBlock, sub pubsub.ClosableSubscription) {
+	defer close(s.done)
+	defer close(s.ch)
+
+	for {
+		err := s.forward(ctx, ch)
+		sub.Close()
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+
+		s.v.logger.Warn("block subscription dropped, resubscribing",
+			"err", err,
+			"last_round", s.lastRound,
+		)
+		err = backoff.RetryNotifyWithTimer(func() error {
+			var rerr error
+			ch, sub, rerr = s.v.rc.WatchBlocks(ctx)
+			return rerr
+		}, s.opts.resubscribe.backOff(ctx, s.v.clock), nil, &backoffTimer{clock: s.v.clock})
+		if err != nil {
+			s.err = fmt.Errorf("bridge: failed to resubscribe to runtime blocks: %w", err)
+			return
+		}
+	}
+}
+
+// forward forwards blocks from the given channel until it is closed or the context is canceled.
+// Any rounds missed since the last forwarded round are fetched and forwarded first while blocks
+// that have already been forwarded are skipped. Going back to an already forwarded round is
+// treated as a reorganization.
+func (s *BlockSubscription) forward(ctx context.Context, ch <-chan *roothash.AnnotatedBlock) error {
+	for {
+		var blk *roothash.AnnotatedBlock
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("subscription closed")
+			}
+			blk = b
+		}
+
+		round := blk.Block.Header.Round
+		if s.lastRoundValid {
+			switch {
+			case round < s.lastRound:
+				s.reorg(round)
+			case round == s.lastRound:
+				if blkHash := blk.Block.Header.EncodedHash(); blkHash.Equal(&s.lastRoundHash) {
+					continue
+				}
+				s.reorg(round)
+			}
+		}
+		if s.lastRoundValid {
+			for r := s.lastRound + 1; r < round; r++ {
+				missed, err := s.v.rc.GetBlock(ctx, r)
+				if err != nil {
+					return fmt.Errorf("failed to fetch missed block for round %d: %w", r, err)
+				}
+				if err = s.emit(ctx, &roothash.AnnotatedBlock{Block: missed}); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.emit(ctx, blk); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *BlockSubscription) emit(ctx context.Context, blk *roothash.AnnotatedBlock) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s.ch <- blk:
+	}
+	s.lastRound = blk.Block.Header.Round
+	s.lastRoundHash = blk.Block.Header.EncodedHash()
+	s.lastRoundValid = true
+	return nil
+}
+
+// reorg handles the runtime going back to the given round. Tracking is reset so that the block
+// of the given round is delivered next.
+func (s *BlockSubscription) reorg(round uint64) {
+	ev := &ReorgEvent{
+		FromRound: s.lastRound,
+		ToRound:   round,
+	}
+	s.v.logger.Warn("runtime went back to an already seen round",
+		"from_round", ev.FromRound,
+		"to_round", ev.ToRound,
+	)
+	if s.opts.onReorg != nil {
+		s.opts.onReorg(ev)
+	}
+	s.lastRoundValid = false
+}
+
+// Implements V1.
+func (a *v1) WatchBlocks(ctx context.Context, opts ...WatchOption) (<-chan *roothash.AnnotatedBlock, *BlockSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch, sub, err := a.rc.WatchBlocks(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	s := &BlockSubscription{
+		v:      a,
+		opts:   newWatchOptions(opts...),
+		ch:     make(chan *roothash.AnnotatedBlock),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.worker(ctx, ch, sub)
+
+	return s.ch, s, nil
+}
+
+// Implements V1.
+func (a *v1) WaitForRound(ctx context.Context, round uint64, opts ...WatchOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before checking the latest round so that no rounds are missed in between.
+	blkCh, blkSub, err := a.WatchBlocks(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	defer blkSub.Close()
+
+	latest, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+	if latest.Header.Round >= round {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case blk, ok := <-blkCh:
+			if !ok {
+				return fmt.Errorf("failed while waiting for round %d: %w", round, blkSub.Err())
+			}
+			if blk.Block.Header.Round >= round {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package bridge
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// blockClient is a runtime client producing blocks on request whose latest round is the
+// configured one, recording the rounds of the blocks fetched by round.
+type blockClient struct {
+	watchClient
+
+	latest uint64
+
+	fetchLock sync.Mutex
+	fetched   []uint64
+}
+
+func (rc *blockClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	if round != client.RoundLatest {
+		rc.fetchLock.Lock()
+		rc.fetched = append(rc.fetched, round)
+		rc.fetchLock.Unlock()
+
+		blk.Header.Round = round
+	}
+	return &blk, nil
+}
+
+// drop closes all block subscriptions as if the connection to the node dropped.
+func (rc *blockClient) drop() {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	for _, sub := range rc.subs {
+		close(sub.ch)
+	}
+	rc.subs = nil
+}
+
+func TestWaitForRound(t *testing.T) {
+	t.Run("Reached", func(t *testing.T) {
+		rc := &blockClient{latest: 5}
+		for _, round := range []uint64{3, 5} {
+			if err := NewV1(rc).WaitForRound(context.Background(), round); err != nil {
+				t.Fatalf("WaitForRound(%d): %s", round, err)
+			}
+		}
+	})
+
+	t.Run("Future", func(t *testing.T) {
+		rc := &blockClient{latest: 1}
+		waitErr := make(chan error, 1)
+		go func() {
+			waitErr <- NewV1(rc).WaitForRound(context.Background(), 4)
+		}()
+
+		// Each block is only forwarded once the previous one has been consumed.
+		rc.produce(2)
+		rc.produce(3)
+		select {
+		case err := <-waitErr:
+			t.Fatalf("WaitForRound returned before round 4 was reached: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		rc.produce(4)
+		if err := <-waitErr; err != nil {
+			t.Fatalf("WaitForRound: %s", err)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		rc := &blockClient{latest: 1}
+		ctx, cancel := context.WithCancel(context.Background())
+		waitErr := make(chan error, 1)
+		go func() {
+			waitErr <- NewV1(rc).WaitForRound(ctx, 4)
+		}()
+
+		rc.produce(2)
+		cancel()
+		select {
+		case err := <-waitErr:
+			if err != context.Canceled {
+				t.Fatalf("expected WaitForRound to be canceled, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("WaitForRound did not return after its context was canceled")
+		}
+	})
+}
+
+func TestBlockSubscription(t *testing.T) {
+	rc := &blockClient{}
+	reorgCh := make(chan *ReorgEvent, 1)
+	blkCh, blkSub, err := NewV1(rc).WatchBlocks(context.Background(),
+		WithReorgHandler(func(ev *ReorgEvent) {
+			reorgCh <- ev
+		}),
+		WithResubscribePolicy(ResubscribePolicy{
+			MaxRetries:      3,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("WatchBlocks: %s", err)
+	}
+	defer blkSub.Close()
+
+	expectRounds := func(rounds ...uint64) {
+		t.Helper()
+		for _, expected := range rounds {
+			select {
+			case blk := <-blkCh:
+				if round := blk.Block.Header.Round; round != expected {
+					t.Fatalf("expected block of round %d, got %d", expected, round)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for block of round %d", expected)
+			}
+		}
+	}
+	expectReorg := func(expected *ReorgEvent) {
+		t.Helper()
+		select {
+		case ev := <-reorgCh:
+			if *ev != *expected {
+				t.Fatalf("expected reorganization %+v, got %+v", expected, ev)
+			}
+		default:
+			t.Fatalf("expected reorganization %+v", expected)
+		}
+	}
+
+	for round := uint64(1); round <= 3; round++ {
+		go rc.produce(round)
+		expectRounds(round)
+	}
+
+	// Going back to an earlier round is reported before its block is delivered.
+	go rc.produce(2)
+	expectRounds(2)
+	expectReorg(&ReorgEvent{FromRound: 3, ToRound: 2})
+
+	// So is a different block for the last delivered round.
+	var blk block.Block
+	blk.Header.Round = 2
+	blk.Header.Timestamp = 1
+	go rc.produceBlock(&blk)
+	expectRounds(2)
+	expectReorg(&ReorgEvent{FromRound: 2, ToRound: 2})
+
+	// After resubscribing, already delivered blocks are skipped and missed rounds are fetched.
+	rc.drop()
+	go func() {
+		rc.produceBlock(&blk)
+		rc.produce(5)
+	}()
+	expectRounds(3, 4, 5)
+	if expected := []uint64{3, 4}; !reflect.DeepEqual(rc.fetched, expected) {
+		t.Fatalf("expected missed rounds %v to be fetched, got %v", expected, rc.fetched)
+	}
+	select {
+	case ev := <-reorgCh:
+		t.Fatalf("unexpected reorganization %+v", ev)
+	default:
+	}
+}
@@ -0,0 +1,874 @@
+// Package bridge implements a client for the Oasis bridge runtime module.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ModuleName is the unique bridge module name.
+const ModuleName = "bridge"
+
+// Error codes emitted by the bridge module.
+const (
+	ErrInvalidArgumentCode           = 1
+	ErrNotAuthorizedCode             = 2
+	ErrInvalidSequenceNumberCode     = 3
+	ErrInsufficientBalanceCode       = 4
+	ErrAlreadySubmittedSignatureCode = 5
+	ErrUnsupportedDenominationCode   = 6
+)
+
+// Method names without the module name prefix (see (*v1).method).
+const (
+	// Callable methods.
+	methodLock    = "Lock"
+	methodWitness = "Witness"
+	methodRelease = "Release"
+
+	// Queries.
+	methodNextSequenceNumbers = "NextSequenceNumbers"
+	methodParameters          = "Parameters"
+)
+
+const (
+	// defaultScanChunkSize is the default number of rounds fetched per chunk when scanning for
+	// events.
+	defaultScanChunkSize = 100
+
+	// defaultEventFetchConcurrency is the default number of rounds whose events are fetched
+	// concurrently.
+	defaultEventFetchConcurrency = 4
+)
+
+// logger is the logger used by bridge helpers unless another logger is configured.
+var logger = logging.GetLogger("bridge")
+
+var (
+	// ErrNotAuthorizedWitness is the error returned when a public key is not an authorized
+	// witness.
+	ErrNotAuthorizedWitness = errors.New("bridge: not an authorized witness")
+
+	// ErrUnsupportedDenomination is the error returned when a denomination is not supported by
+	// the bridge.
+	ErrUnsupportedDenomination = errors.New("bridge: unsupported denomination")
+
+	// ErrInsufficientBalance is the error returned when an account does not hold enough funds.
+	ErrInsufficientBalance = errors.New("bridge: insufficient balance")
+
+	// ErrGetEventsTimeout is the error returned when fetching the events of a round does not
+	// complete within the configured timeout (see WithGetEventsTimeout). It is retryable.
+	ErrGetEventsTimeout = errors.New("bridge: timed out fetching events")
+
+	// ErrLockEventTimeout is the error returned when the event of a submitted lock has not been
+	// observed before the context deadline (see SubmitLockAndWait).
+	ErrLockEventTimeout = errors.New("bridge: timed out waiting for lock event")
+
+	// ErrNoTransferTimeSamples is the error returned together with the default transfer time
+	// when no transfers have been observed yet (see EstimateTransferTime).
+	ErrNoTransferTimeSamples = errors.New("bridge: no transfer time samples")
+)
+
+// V1 is the v1 bridge module interface.
+type V1 interface {
+	// ModuleName returns the name of the bridge module this client talks to.
+	ModuleName() string
+
+	// RemoteAddressCodec returns the codec used for addresses of the remote chain.
+	RemoteAddressCodec() RemoteAddressCodec
+
+	// Parameters queries the bridge module parameters.
+	//
+	// Passing client.RoundLatest queries the round selected by the configured round strategy
+	// (see WithRoundStrategy).
+	Parameters(ctx context.Context, round uint64) (*Parameters, error)
+
+	// SupportedDenominations queries the denominations supported by the bridge, returning the
+	// sorted denominations local to this side of the bridge and the sorted remote denominations
+	// (see Parameters.SupportedDenominations).
+	//
+	// Passing client.RoundLatest queries the round selected by the configured round strategy
+	// (see WithRoundStrategy).
+	SupportedDenominations(ctx context.Context, round uint64) ([]types.Denomination, []RemoteDenomination, error)
+
+	// NextSequenceNumbers queries the next bridge sequence numbers.
+	//
+	// Passing client.RoundLatest queries the round selected by the configured round strategy
+	// (see WithRoundStrategy).
+	NextSequenceNumbers(ctx context.Context, round uint64) (*NextSequenceNumbers, error)
+
+	// Info returns aggregated bridge information such as the module parameters together with the
+	// metadata of all supported denominations.
+	//
+	// Results are cached for a short time (see WithInfoCacheTTL).
+	Info(ctx context.Context, round uint64) (*RuntimeBridgeInfo, error)
+
+	// NativeDenominationInfo returns the ticker symbol and the number of decimal places of the
+	// native denomination.
+	//
+	// The bridge runtime does not expose denomination metadata so the consensus token metadata
+	// is used (see WithConsensusStaking), falling back to the info configured via
+	// WithDenominationInfo. ErrUnknownDenominationInfo is returned if neither is available.
+	NativeDenominationInfo(ctx context.Context) (symbol string, decimals uint8, err error)
+
+	// WitnessProgress returns the number of witness signatures collected for the lock with the
+	// given identifier as of the given round together with the number of signatures required.
+	// ErrLockNotFound is returned if the lock does not exist at that round.
+	//
+	// The bridge runtime does not expose the signatures collected for pending locks, so they are
+	// derived from the bridge.Witness transactions of authorized witnesses included since the
+	// lock has been created. Such transactions are counted even if they failed to execute.
+	WitnessProgress(ctx context.Context, round, lockID uint64) (collected uint64, threshold uint64, err error)
+
+	// IsLockReleased returns true iff the lock with the given identifier has been processed by
+	// this side of the bridge as of the given round, i.e. the witnesses have signed off on it so
+	// that it can be released on the remote side. ErrLockNotFound is returned if the lock does not
+	// exist at that round.
+	//
+	// The bridge runtime does not track releases on the remote side nor expose the state of
+	// locks, so this is derived from the WitnessesSigned events emitted since the lock has been
+	// created (see ReplayEvents).
+	IsLockReleased(ctx context.Context, round, lockID uint64) (bool, error)
+
+	// LockByTxHash returns the lock event emitted by the transaction with the given hash (see
+	// TxHash), e.g. to obtain the identifier of a lock submitted by another service.
+	// ErrLockNotFound is returned if no such lock event has been emitted since the given round.
+	//
+	// The runtime does not index transactions by hash, so the rounds from the given round up to
+	// the latest round are searched with one query each. The round should thus be as close to the
+	// transaction's round as possible, e.g. the round of its receipt (see WithReceipt) or the
+	// latest round before it has been submitted.
+	LockByTxHash(ctx context.Context, fromRound uint64, txHash hash.Hash) (*LockEvent, error)
+
+	// GetReleaseAck returns the acknowledgement that the lock with the given identifier has been
+	// released on the remote side of the bridge as of the given round of the remote runtime, so
+	// that users can confirm that their transfer has completed on both sides.
+	// ErrReleaseNotAcknowledged is returned in case the lock has not been released yet.
+	//
+	// The bridge runtime does not record acknowledgements, so the lock is correlated with the
+	// release event with the same sequence number emitted by the remote runtime, which must be
+	// configured via WithRemoteRuntime. ErrNoRemoteRuntime is returned otherwise.
+	GetReleaseAck(ctx context.Context, round, lockID uint64) (*ReleaseAck, error)
+
+	// Snapshot returns a point-in-time view of the bridge state at the given round.
+	//
+	// Pending locks are determined by replaying all bridge events since the runtime genesis
+	// which may take a while.
+	Snapshot(ctx context.Context, round uint64) (*BridgeSnapshot, error)
+
+	// Accounting returns the total amounts locked and released by the bridge per denomination
+	// up to and including the given round, e.g., for auditing that the amounts locked on one
+	// side match the amounts released on the other side.
+	//
+	// The bridge runtime does not track aggregate amounts, so they are computed by replaying all
+	// bridge events since the runtime genesis which may take a while.
+	Accounting(ctx context.Context, round uint64) (map[types.Denomination]LockReleaseTotals, error)
+
+	// RoundTime returns the wall-clock time of the given runtime round as recorded in the
+	// block header.
+	RoundTime(ctx context.Context, round uint64) (time.Time, error)
+
+	// WaitForRound blocks until the runtime reaches the given round or the context is canceled.
+	//
+	// Blocks are watched as in WatchBlocks.
+	WaitForRound(ctx context.Context, round uint64, opts ...WatchOption) error
+
+	// WatchBlocks subscribes to runtime blocks.
+	//
+	// In case the underlying subscription drops, it is transparently reestablished according to
+	// the configured resubscribe policy and any rounds missed in the meantime are delivered in
+	// order (such blocks do not carry a consensus height). Once resubscription fails the channel
+	// is closed and the subscription's Err method reports the cause.
+	//
+	// In case the runtime goes back to an already delivered round, the reorganization is
+	// reported to the handler configured via WithReorgHandler and delivery resumes with the
+	// round the runtime went back to.
+	WatchBlocks(ctx context.Context, opts ...WatchOption) (<-chan *roothash.AnnotatedBlock, *BlockSubscription, error)
+
+	// WatchEvents subscribes to bridge events.
+	//
+	// Blocks are watched as in WatchBlocks and the events of each round are delivered in order.
+	// Once the underlying block subscription fails or events cannot be fetched the channel is
+	// closed and the subscription's Err method reports the cause.
+	//
+	// The event channel buffer and the behavior when the consumer is slow are configured via
+	// WithEventBuffer and WithOverflowPolicy. By default the watcher blocks until the consumer
+	// receives each event so that no events are lost. See WithConfirmations for holding back
+	// events until they are unlikely to be reverted.
+	WatchEvents(ctx context.Context, opts ...WatchOption) (<-chan *Event, *EventSubscription, error)
+
+	// WatchEventsFiltered subscribes to bridge events like WatchEvents, but only delivers events
+	// selected by the given filter.
+	WatchEventsFiltered(ctx context.Context, filter EventFilter, opts ...WatchOption) (<-chan *Event, *EventSubscription, error)
+
+	// WatchReleases subscribes to release events, e.g., for crediting users on the receiving side
+	// of the bridge.
+	//
+	// Events are watched as in WatchEvents and the Round of each delivered release event is the
+	// round in which it was emitted.
+	WatchReleases(ctx context.Context, opts ...WatchOption) (<-chan *ReleaseEvent, *ReleaseSubscription, error)
+
+	// GetEvents returns all bridge events emitted in the given round in emission order. The Index
+	// of each event is its position in the returned slice.
+	//
+	// Malformed events are logged and skipped.
+	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
+
+	// GetEventsRange returns all bridge events emitted in rounds [fromRound, toRound], keyed by
+	// round. Every round in the range has an entry (which is empty in case the round has no bridge
+	// events) and events within a round are in emission order.
+	//
+	// Rounds are fetched concurrently (see WithEventFetchConcurrency). Passing client.RoundLatest
+	// as toRound fetches up to and including the latest round.
+	GetEventsRange(ctx context.Context, fromRound, toRound uint64) (map[uint64][]*Event, error)
+
+	// ReplayEvents passes the bridge events emitted in each of the rounds [fromRound, toRound] to
+	// fn, in round order, including rounds without bridge events. Events within a round are in
+	// emission order.
+	//
+	// Rounds are fetched concurrently ahead of the round being replayed (see
+	// WithBackfillConcurrency and WithBackfillWindow). Passing client.RoundLatest as toRound
+	// replays up to and including the latest round. Replay stops at the first error returned by
+	// fn.
+	//
+	// ErrBackfillBelowMinRound is returned in case fromRound is below the configured minimum
+	// round (see WithBackfillMinRound). Progress is reported as configured by
+	// WithOnBackfillProgress.
+	ReplayEvents(ctx context.Context, fromRound, toRound uint64, fn func(round uint64, events []*Event) error) error
+
+	// ListReleases returns all release events emitted in rounds [fromRound, toRound].
+	//
+	// Passing client.RoundLatest as toRound scans up to and including the latest round.
+	ListReleases(ctx context.Context, fromRound, toRound uint64) ([]*ReleaseEvent, error)
+
+	// ScanReleases scans rounds [fromRound, toRound] for release events, fetching chunkSize
+	// rounds at a time and passing each non-empty chunk of release events to fn. Unlike
+	// ListReleases it never holds more than a single chunk in memory.
+	//
+	// Passing client.RoundLatest as toRound scans up to and including the latest round.
+	ScanReleases(
+		ctx context.Context,
+		fromRound, toRound, chunkSize uint64,
+		fn func(releases []*ReleaseEvent) error,
+	) error
+
+	// EstimateFee estimates the fee required for the given transaction based on the configured gas
+	// price (see WithGasPrice).
+	EstimateFee(ctx context.Context, tx *types.Transaction) (*types.Fee, error)
+
+	// SubmitLock signs and submits a bridge.Lock transaction and waits for its result.
+	//
+	// The denomination of the locked amount is validated against the latest bridge parameters
+	// before submission and ErrUnsupportedDenomination is returned if it is not supported. See
+	// WithBalancePrecheck for also checking the signer's balance before submission and
+	// WithReceipt for obtaining the round in which the lock has been executed.
+	SubmitLock(ctx context.Context, signer signature.Signer, body *Lock, opts ...SubmitOption) (*LockResult, error)
+
+	// SubmitLockAndWait is like SubmitLock, but additionally waits for the round in which the
+	// lock has been executed and returns the emitted lock event together with the call result.
+	//
+	// In case the lock event is not observed before the context deadline, the call result is
+	// returned together with an error wrapping ErrLockEventTimeout.
+	SubmitLockAndWait(
+		ctx context.Context,
+		signer signature.Signer,
+		body *Lock,
+		opts ...SubmitOption,
+	) (*LockResult, *LockEvent, error)
+
+	// EstimateTransferTime returns the average time between submitting a lock and observing its
+	// WitnessesSignedEvent over the most recent transfers (see WithTransferTimeWindow), e.g. for
+	// showing an ETA.
+	//
+	// Only locks submitted via SubmitLock (or SubmitLockAndWait) whose WitnessesSignedEvent is
+	// subsequently fetched by this client (e.g. via WatchEvents) are taken into account, so the
+	// estimate is not updated unless events are fetched. In case no such transfer has completed
+	// yet, the default transfer time (see WithDefaultTransferTime) is returned together with an
+	// error wrapping ErrNoTransferTimeSamples.
+	EstimateTransferTime(ctx context.Context) (time.Duration, error)
+
+	// PrepareLock validates the lock accumulated by the given builder against the latest bridge
+	// parameters (see LockBuilder.Build) and only then prepares an unsigned bridge.Lock
+	// transaction authenticated by the given public key.
+	//
+	// It returns the transaction signer together with the chain context to sign with. The signed
+	// transaction can then be submitted using SubmitAndDecode.
+	PrepareLock(
+		ctx context.Context,
+		pk signature.PublicKey,
+		b *LockBuilder,
+		opts ...SubmitOption,
+	) (*types.TransactionSigner, signature.Context, error)
+
+	// SubmitWitness signs and submits a bridge.Witness transaction and waits for its result. See
+	// WithReceipt for obtaining the round in which the transaction has been executed.
+	SubmitWitness(ctx context.Context, signer signature.Signer, body *Witness, opts ...SubmitOption) error
+
+	// PrepareWitness prepares an unsigned bridge.Witness transaction authenticated by the given
+	// Ed25519 public key for signing by an external (e.g., offline or HSM-backed) signer.
+	//
+	// It returns the preimage that needs to be signed with plain Ed25519 and a function that
+	// verifies the resulting signature and assembles the signed transaction, which can then be
+	// submitted using SubmitAndDecode.
+	PrepareWitness(ctx context.Context, pk signature.PublicKey, body *Witness, opts ...SubmitOption) ([]byte, AssembleFunc, error)
+
+	// SubmitRelease signs and submits a bridge.Release transaction and waits for its result.
+	//
+	// Submitting a release that has already been processed or that has already been signed by
+	// the given signer is a no-op and the returned status indicates what has taken place. See
+	// WithReceipt for obtaining the round in which a submitted release has been executed.
+	SubmitRelease(ctx context.Context, signer signature.Signer, body *Release, opts ...SubmitOption) (ReleaseStatus, error)
+
+	// Cancel requests cancellation of the lock with the given identifier which has not been
+	// witnessed by enough witnesses and the refund of the locked amount to its owner.
+	//
+	// The bridge runtime does not support cancelling locks yet and ErrCancelNotSupported is
+	// always returned.
+	Cancel(ctx context.Context, signer signature.Signer, id uint64, opts ...SubmitOption) error
+
+	// WaitForCancel waits for the lock with the given identifier to be cancelled.
+	//
+	// The bridge runtime does not support cancelling locks yet and ErrCancelNotSupported is
+	// always returned.
+	WaitForCancel(ctx context.Context, id uint64) (*CancelEvent, error)
+}
+
+type v1 struct {
+	module   string
+	keys     *eventKeys
+	codec    RemoteAddressCodec
+	rc       client.RuntimeClient
+	accounts accounts.V1
+	core     core.V1
+
+	infoLock sync.Mutex
+	info     *types.RuntimeInfo
+
+	denominations map[types.Denomination]DenominationInfo
+	infoCache     infoCache
+	paramsCache   *parametersCache
+
+	remote *v1
+
+	staking        staking.Backend
+	nativeInfoLock sync.Mutex
+	nativeInfo     *DenominationInfo
+	gasPrice       types.BaseUnits
+	gasPrices      map[types.Denomination]types.BaseUnits
+
+	eventFetchConcurrency    int
+	backfillConcurrency      int
+	backfillWindow           int
+	backfillMinRound         uint64
+	onBackfillProgress       func(current, target uint64)
+	backfillProgressInterval time.Duration
+	onUnknownEvent           func(ev *client.Event)
+	rawEventLogging          bool
+	getEventsTimeout         time.Duration
+	decodeOpts               []DecodeOption
+	clock                    Clock
+	roundStrategy            RoundStrategy
+	transferTimes            *transferTimes
+	logger                   *logging.Logger
+}
+
+// V1Option is an option for configuring the bridge module client.
+type V1Option func(a *v1)
+
+// Implements V1.
+func (a *v1) ModuleName() string {
+	return a.module
+}
+
+// Implements V1.
+func (a *v1) RemoteAddressCodec() RemoteAddressCodec {
+	return a.codec
+}
+
+// method returns the full name of the given bridge module method.
+func (a *v1) method(name string) string {
+	return a.module + "." + name
+}
+
+// Implements V1.
+func (a *v1) Parameters(ctx context.Context, round uint64) (*Parameters, error) {
+	round, err := a.queryRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.paramsCache != nil {
+		if params, ok := a.paramsCache.get(round); ok {
+			return params, nil
+		}
+	}
+
+	var params Parameters
+	err = a.rc.Query(ctx, round, a.method(methodParameters), nil, &params)
+	if err != nil {
+		return nil, err
+	}
+	if a.paramsCache != nil {
+		a.paramsCache.put(round, &params)
+	}
+	return &params, nil
+}
+
+// Implements V1.
+func (a *v1) SupportedDenominations(ctx context.Context, round uint64) ([]types.Denomination, []RemoteDenomination, error) {
+	params, err := a.Parameters(ctx, round)
+	if err != nil {
+		return nil, nil, err
+	}
+	local, remote := params.SupportedDenominations()
+	return local, remote, nil
+}
+
+// Implements V1.
+func (a *v1) NextSequenceNumbers(ctx context.Context, round uint64) (*NextSequenceNumbers, error) {
+	round, err := a.queryRound(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	var sequences NextSequenceNumbers
+	err = a.rc.Query(ctx, round, a.method(methodNextSequenceNumbers), nil, &sequences)
+	if err != nil {
+		return nil, err
+	}
+	return &sequences, nil
+}
+
+// Implements V1.
+func (a *v1) RoundTime(ctx context.Context, round uint64) (time.Time, error) {
+	blk, err := a.rc.GetBlock(ctx, round)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch block for round %d: %w", round, err)
+	}
+	return time.Unix(int64(blk.Header.Timestamp), 0), nil
+}
+
+// Implements V1.
+func (a *v1) GetEvents(ctx context.Context, round uint64) ([]*Event, error) {
+	rawEvents, err := a.getRawEvents(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	for _, rawEv := range rawEvents {
+		if a.rawEventLogging {
+			a.logRawEvent(round, rawEv)
+		}
+		ev, err := a.keys.decode(rawEv, a.decodeOpts...)
+		switch {
+		case errors.Is(err, ErrMalformedEvent):
+			a.logMalformedEvent(round, rawEv, err)
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to decode event in round %d: %w", round, err)
+		}
+		if ev == nil {
+			if unknown := a.keys.unknown(rawEv); unknown != nil {
+				a.onUnknownEvent(unknown)
+			}
+			continue
+		}
+		ev.Round = round
+		ev.Index = uint32(len(events))
+		if ev.Release != nil {
+			ev.Release.Round = round
+		}
+		if ws := ev.WitnessesSigned; ws != nil && ws.IsLock() {
+			a.transferTimes.completed(ws.ID, a.clock.Now())
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// getRawEvents fetches the raw events of the given round, bounded by the configured per-call
+// timeout if any.
+func (a *v1) getRawEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	if a.getEventsTimeout <= 0 {
+		rawEvents, err := a.rc.GetEvents(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch events for round %d: %w", round, err)
+		}
+		return rawEvents, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, a.getEventsTimeout)
+	defer cancel()
+
+	rawEvents, err := a.rc.GetEvents(callCtx, round)
+	switch {
+	case err == nil:
+		return rawEvents, nil
+	case ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded):
+		// Only report a timeout in case the parent context is still alive, otherwise the caller
+		// has given up anyway.
+		return nil, fmt.Errorf("%w: round %d after %s", ErrGetEventsTimeout, round, a.getEventsTimeout)
+	default:
+		return nil, fmt.Errorf("failed to fetch events for round %d: %w", round, err)
+	}
+}
+
+// Implements V1.
+func (a *v1) GetEventsRange(ctx context.Context, fromRound, toRound uint64) (map[uint64][]*Event, error) {
+	toRound, err := a.resolveRoundRange(ctx, fromRound, toRound)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	events := make(map[uint64][]*Event, toRound-fromRound+1)
+	sem := make(chan struct{}, a.eventFetchConcurrency)
+	for round := fromRound; round <= toRound; round++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(round uint64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			evs, err := a.GetEvents(ctx, round)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			events[round] = evs
+		}(round)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Implements V1.
+func (a *v1) ListReleases(ctx context.Context, fromRound, toRound uint64) ([]*ReleaseEvent, error) {
+	var releases []*ReleaseEvent
+	err := a.ScanReleases(ctx, fromRound, toRound, defaultScanChunkSize, func(chunk []*ReleaseEvent) error {
+		releases = append(releases, chunk...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// Implements V1.
+func (a *v1) ScanReleases(
+	ctx context.Context,
+	fromRound, toRound, chunkSize uint64,
+	fn func(releases []*ReleaseEvent) error,
+) error {
+	toRound, err := a.resolveRoundRange(ctx, fromRound, toRound)
+	if err != nil {
+		return err
+	}
+	if chunkSize == 0 {
+		chunkSize = defaultScanChunkSize
+	}
+
+	var chunk []*ReleaseEvent
+	for round := fromRound; ; round++ {
+		events, err := a.GetEvents(ctx, round)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			if ev.Release != nil {
+				chunk = append(chunk, ev.Release)
+			}
+		}
+
+		last := round == toRound
+		if len(chunk) > 0 && (last || (round-fromRound+1)%chunkSize == 0) {
+			if err = fn(chunk); err != nil {
+				return err
+			}
+			chunk = nil
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// Implements V1.
+func (a *v1) SubmitLock(
+	ctx context.Context,
+	signer signature.Signer,
+	body *Lock,
+	opts ...SubmitOption,
+) (*LockResult, error) {
+	if err := a.codec.Validate(body.Target); err != nil {
+		return nil, fmt.Errorf("malformed lock target: %w", err)
+	}
+	params, err := a.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query parameters: %w", err)
+	}
+	denomination := body.Amount.Denomination
+	remote, ok := params.ResolveDenomination(denomination)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDenomination, denomination)
+	}
+	if newSubmitOptions(opts...).balancePrecheck {
+		if err = a.precheckBalance(ctx, AddressOf(signer), &body.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if remote != nil {
+		a.logger.Info("locking remote denomination",
+			"denomination", denomination,
+			"remote_denomination", remote,
+		)
+	} else {
+		a.logger.Info("locking local denomination",
+			"denomination", denomination,
+		)
+	}
+
+	submitted := a.clock.Now()
+	result, err := signAndSubmit[LockResult](ctx, a, signer, a.method(methodLock), body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.transferTimes.submitted(result.ID, submitted)
+	return result, nil
+}
+
+// Implements V1.
+func (a *v1) SubmitWitness(ctx context.Context, signer signature.Signer, body *Witness, opts ...SubmitOption) error {
+	_, err := signAndSubmit[struct{}](ctx, a, signer, a.method(methodWitness), body, opts...)
+	return err
+}
+
+// resolveRoundRange resolves client.RoundLatest as the end of the round range and validates that
+// the range is not empty.
+func (a *v1) resolveRoundRange(ctx context.Context, fromRound, toRound uint64) (uint64, error) {
+	if toRound == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		toRound = blk.Header.Round
+	}
+	if fromRound > toRound {
+		return 0, fmt.Errorf("bridge: invalid round range [%d, %d]", fromRound, toRound)
+	}
+	return toRound, nil
+}
+
+func (a *v1) runtimeInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	a.infoLock.Lock()
+	defer a.infoLock.Unlock()
+
+	if a.info != nil {
+		return a.info, nil
+	}
+	info, err := a.rc.GetInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch runtime info: %w", err)
+	}
+	a.info = info
+	return info, nil
+}
+
+// EnsureAuthorizedWitness checks that the given public key is an authorized witness according to
+// the latest bridge parameters, which must be valid (see Parameters.Validate), and returns its
+// witness index.
+func EnsureAuthorizedWitness(ctx context.Context, v V1, pk signature.PublicKey) (uint16, error) {
+	params, err := v.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query bridge parameters: %w", err)
+	}
+	if err = params.Validate(); err != nil {
+		return 0, err
+	}
+	index, ok := params.WitnessIndex(pk)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNotAuthorizedWitness, pk)
+	}
+	return index, nil
+}
+
+// IsModuleError checks whether the given error is a failed call result emitted by the bridge
+// module with the default name (see ModuleName) and with the given error code.
+func IsModuleError(err error, code uint32) bool {
+	return IsModuleErrorFor(err, ModuleName, code)
+}
+
+// IsModuleErrorFor checks whether the given error is a failed call result emitted by the bridge
+// module with the given name and with the given error code.
+func IsModuleErrorFor(err error, module string, code uint32) bool {
+	var failed *types.FailedCallResult
+	if !errors.As(err, &failed) {
+		return false
+	}
+	return failed.Module == module && failed.Code == code
+}
+
+// WithEventFetchConcurrency configures the maximum number of rounds whose events are fetched
+// concurrently when fetching events for a range of rounds.
+func WithEventFetchConcurrency(n int) V1Option {
+	return func(a *v1) {
+		if n > 0 {
+			a.eventFetchConcurrency = n
+		}
+	}
+}
+
+// WithUnknownEventHandler configures a handler invoked for each event emitted by the bridge module
+// that cannot be decoded as its event code is not known to this client (e.g., in case the runtime
+// is newer than the client).
+//
+// By default such events are logged at debug level and otherwise ignored. Passing nil disables
+// the handler.
+func WithUnknownEventHandler(fn func(ev *client.Event)) V1Option {
+	return func(a *v1) {
+		if fn == nil {
+			fn = func(*client.Event) {}
+		}
+		a.onUnknownEvent = fn
+	}
+}
+
+// WithDecodeOptions configures the options used to decode the events emitted by the bridge module
+// (see DecodeEvent), e.g. AllowUnknownFields.
+func WithDecodeOptions(opts ...DecodeOption) V1Option {
+	return func(a *v1) {
+		a.decodeOpts = append(a.decodeOpts, opts...)
+	}
+}
+
+// WithRawEventLogging configures whether the raw key and value of every event fetched from the
+// runtime are logged at debug level, e.g. for diagnosing decoding issues.
+//
+// Raw events are logged in addition to the regular debug logging and are very noisy on busy
+// chains, so this is disabled by default.
+func WithRawEventLogging(enabled bool) V1Option {
+	return func(a *v1) {
+		a.rawEventLogging = enabled
+	}
+}
+
+// WithGetEventsTimeout configures the timeout of each call fetching the events of a round so that
+// an unresponsive node does not stall event processing indefinitely. Calls that time out fail
+// with ErrGetEventsTimeout and may be retried.
+//
+// The timeout is derived from the context passed to the call. By default calls are only bounded
+// by that context.
+func WithGetEventsTimeout(d time.Duration) V1Option {
+	return func(a *v1) {
+		a.getEventsTimeout = d
+	}
+}
+
+// WithLogger configures the logger used by the bridge module client and the subscriptions created
+// by it.
+//
+// By default a logger for the "bridge" module is used.
+func WithLogger(l *logging.Logger) V1Option {
+	return func(a *v1) {
+		a.logger = l
+	}
+}
+
+// WithRemoteAddressCodec configures the codec used to validate the addresses of the remote chain,
+// e.g. lock targets before submitting them.
+//
+// By default DefaultRemoteAddressCodec is used.
+func WithRemoteAddressCodec(codec RemoteAddressCodec) V1Option {
+	return func(a *v1) {
+		a.codec = codec
+	}
+}
+
+// NewV1 generates a V1 client helper for the bridge module.
+func NewV1(rc client.RuntimeClient, opts ...V1Option) V1 {
+	return NewV1WithModule(rc, ModuleName, opts...)
+}
+
+// NewV1WithModule generates a V1 client helper for a bridge module with the given name (e.g., in
+// case a runtime contains multiple bridge module instances). The event keys and method names are
+// derived from the module name.
+func NewV1WithModule(rc client.RuntimeClient, module string, opts ...V1Option) V1 {
+	return newV1(rc, module, opts...)
+}
+
+func newV1(rc client.RuntimeClient, module string, opts ...V1Option) *v1 {
+	a := &v1{
+		module:        module,
+		keys:          newEventKeys(module),
+		codec:         DefaultRemoteAddressCodec,
+		rc:            rc,
+		accounts:      accounts.NewV1(rc),
+		core:          core.NewV1(rc),
+		denominations: make(map[types.Denomination]DenominationInfo),
+		infoCache: infoCache{
+			clock:   RealClock,
+			ttl:     DefaultInfoCacheTTL,
+			entries: make(map[uint64]*infoCacheEntry),
+		},
+		gasPrice:                 types.NewBaseUnits(*quantity.NewFromUint64(0), types.NativeDenomination),
+		gasPrices:                make(map[types.Denomination]types.BaseUnits),
+		eventFetchConcurrency:    defaultEventFetchConcurrency,
+		backfillConcurrency:      defaultEventFetchConcurrency,
+		backfillWindow:           defaultBackfillWindow,
+		backfillProgressInterval: defaultBackfillProgressInterval,
+		clock:                    RealClock,
+		roundStrategy:            LatestRound(),
+		transferTimes:            newTransferTimes(),
+		logger:                   logger,
+	}
+	a.onUnknownEvent = a.logUnknownEvent
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
@@ -0,0 +1,279 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	sdk "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// hangingClient is a runtime client whose first hangs calls fetching events block until their
+// context is done.
+type hangingClient struct {
+	client.RuntimeClient
+
+	lock  sync.Mutex
+	hangs int
+	calls int
+}
+
+func (rc *hangingClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.lock.Lock()
+	rc.calls++
+	hang := rc.calls <= rc.hangs
+	rc.lock.Unlock()
+
+	if hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return []*coreClient.Event{{
+		Key:   LockEventKey,
+		Value: cbor.Marshal(&LockEvent{ID: round}),
+	}}, nil
+}
+
+func TestGetEventsTimeout(t *testing.T) {
+	rc := &hangingClient{hangs: 1}
+	v := NewV1(rc, WithGetEventsTimeout(10*time.Millisecond))
+
+	_, err := v.GetEvents(context.Background(), 5)
+	if !errors.Is(err, ErrGetEventsTimeout) {
+		t.Fatalf("expected ErrGetEventsTimeout, got %v", err)
+	}
+
+	events, err := v.GetEvents(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Lock.ID != 5 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestGetEventsTimeoutParentCanceled(t *testing.T) {
+	rc := &hangingClient{hangs: 1}
+	v := NewV1(rc, WithGetEventsTimeout(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := v.GetEvents(ctx, 5)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrGetEventsTimeout) {
+		t.Fatalf("expected the parent context error, got %v", err)
+	}
+}
+
+func TestProcessorRetriesGetEventsTimeout(t *testing.T) {
+	rc := &hangingClient{hangs: 2}
+	p := NewEventProcessor(rc, nil,
+		WithProcessorBridgeOptions(WithGetEventsTimeout(10*time.Millisecond)),
+		WithGetEventsRetryPolicy(ResubscribePolicy{
+			MaxRetries:      2,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+		}),
+	)
+
+	events, err := p.getEvents(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("getEvents: %v", err)
+	}
+	if len(events) != 1 || rc.calls != 3 {
+		t.Fatalf("expected a single event after 3 calls, got %d events after %d calls", len(events), rc.calls)
+	}
+
+	rc.calls = 0
+	rc.hangs = 3
+	if _, err = p.getEvents(context.Background(), 5); !errors.Is(err, ErrGetEventsTimeout) {
+		t.Fatalf("expected ErrGetEventsTimeout once retries are exhausted, got %v", err)
+	}
+}
+
+var errRangeRound = errors.New("failed round")
+
+// rangeClient is a runtime client whose rounds up to latest contain two locks each, except for
+// the empty round, and fetching the events of the failing round fails. Earlier rounds take longer
+// to fetch so that concurrently fetched rounds complete out of order.
+type rangeClient struct {
+	client.RuntimeClient
+
+	latest       uint64
+	emptyRound   uint64
+	failingRound uint64
+}
+
+func (rc *rangeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *rangeClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	time.Sleep(time.Duration(rc.latest-round) * time.Millisecond)
+
+	switch round {
+	case rc.failingRound:
+		return nil, errRangeRound
+	case rc.emptyRound:
+		return nil, nil
+	}
+	return []*coreClient.Event{
+		{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 2 * round})},
+		{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 2*round + 1})},
+	}, nil
+}
+
+func TestGetEventsRange(t *testing.T) {
+	ctx := context.Background()
+	rc := &rangeClient{latest: 6, emptyRound: 3}
+	v := NewV1(rc, WithEventFetchConcurrency(3))
+
+	for _, toRound := range []uint64{6, client.RoundLatest} {
+		events, err := v.GetEventsRange(ctx, 1, toRound)
+		if err != nil {
+			t.Fatalf("GetEventsRange: %s", err)
+		}
+		if len(events) != 6 {
+			t.Fatalf("expected events of 6 rounds, got %d", len(events))
+		}
+		for round := uint64(1); round <= 6; round++ {
+			evs, ok := events[round]
+			switch {
+			case !ok:
+				t.Fatalf("missing entry for round %d", round)
+			case round == rc.emptyRound:
+				if len(evs) != 0 {
+					t.Fatalf("expected no events in round %d, got %+v", round, evs)
+				}
+			default:
+				if len(evs) != 2 {
+					t.Fatalf("expected 2 events in round %d, got %d", round, len(evs))
+				}
+				for i, ev := range evs {
+					if ev.Round != round || ev.Lock == nil || ev.Lock.ID != 2*round+uint64(i) {
+						t.Fatalf("unexpected event %d of round %d: %+v", i, round, ev)
+					}
+				}
+			}
+		}
+	}
+
+	// An error fetching any round fails the whole range.
+	rc.failingRound = 4
+	if _, err := v.GetEventsRange(ctx, 1, 6); !errors.Is(err, errRangeRound) {
+		t.Fatalf("expected the error of the failing round, got %v", err)
+	}
+
+	if _, err := v.GetEventsRange(ctx, 5, 4); err == nil {
+		t.Fatalf("expected an invalid range to be rejected")
+	}
+}
+
+// moduleClient is a watch client whose rounds contain the configured events, recording the methods
+// of performed queries and submitted transactions.
+type moduleClient struct {
+	eventsClient
+
+	methods []string
+}
+
+func (rc *moduleClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *moduleClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	rc.methods = append(rc.methods, method)
+	switch rsp := rsp.(type) {
+	case *Parameters:
+		*rsp = Parameters{Threshold: 1}
+	case *uint64:
+		*rsp = 0
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func (rc *moduleClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	var tx types.Transaction
+	if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+		return nil, err
+	}
+	rc.methods = append(rc.methods, string(tx.Call.Method))
+	return cbor.Marshal(nil), nil
+}
+
+func TestNewV1WithModule(t *testing.T) {
+	const module = "bridge2"
+	rc := &moduleClient{
+		eventsClient: eventsClient{
+			events: map[uint64][]*coreClient.Event{
+				1: {
+					{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 1})},
+					{Key: sdk.NewEventKey(module, lockEventCode), Value: cbor.Marshal(&LockEvent{ID: 2})},
+					{Key: sdk.NewEventKey(module, 99), Value: cbor.Marshal(nil)},
+				},
+			},
+		},
+	}
+
+	// Only the events of the configured module instance are decoded.
+	var unknown []*client.Event
+	v := NewV1WithModule(rc, module, WithUnknownEventHandler(func(ev *client.Event) {
+		unknown = append(unknown, ev)
+	}))
+	ctx := context.Background()
+	events, err := v.GetEvents(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetEvents: %s", err)
+	}
+	if len(events) != 1 || events[0].Lock == nil || events[0].Lock.ID != 2 {
+		t.Fatalf("expected only lock 2 to be decoded, got %+v", events)
+	}
+	if len(unknown) != 1 || unknown[0].Module != module || unknown[0].Code != 99 {
+		t.Fatalf("expected an unknown event of module %s, got %+v", module, unknown)
+	}
+	events, err = NewV1(rc).GetEvents(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetEvents: %s", err)
+	}
+	if len(events) != 1 || events[0].Lock == nil || events[0].Lock.ID != 1 {
+		t.Fatalf("expected only lock 1 to be decoded by the default module, got %+v", events)
+	}
+
+	// Queries and transactions call the methods of the configured module instance.
+	if _, err = v.Parameters(ctx, client.RoundLatest); err != nil {
+		t.Fatalf("Parameters: %s", err)
+	}
+	body := &Witness{ID: 2, Signature: make([]byte, WitnessSignatureSize)}
+	if err = v.SubmitWitness(ctx, sdkTesting.Alice.Signer, body); err != nil {
+		t.Fatalf("SubmitWitness: %s", err)
+	}
+	for _, method := range []string{module + "." + methodParameters, module + "." + methodWitness} {
+		var found bool
+		for _, m := range rc.methods {
+			found = found || m == method
+		}
+		if !found {
+			t.Fatalf("expected method %s to be called, got %v", method, rc.methods)
+		}
+	}
+	for _, m := range rc.methods {
+		if strings.HasPrefix(m, ModuleName+".") {
+			t.Fatalf("unexpected call of default module method %s", m)
+		}
+	}
+}
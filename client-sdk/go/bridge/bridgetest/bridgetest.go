@@ -0,0 +1,349 @@
+// Package bridgetest implements a harness for running integration tests against a local network
+// with the bridge runtime.
+//
+// The harness requires the following binaries which can be obtained by running
+// tests/download-artifacts.sh and building the example runtime:
+//
+//   - oasis-net-runner (TEST_NET_RUNNER),
+//   - oasis-node (TEST_NODE_BINARY),
+//   - oasis-core-runtime-loader (TEST_RUNTIME_LOADER),
+//   - simple-keymanager (TEST_KM_BINARY),
+//   - the bridge runtime, e.g. target/debug/oasis-bridge-runtime (TEST_BRIDGE_RUNTIME).
+//
+// The environment variables in parentheses are used by ConfigFromEnv and match the ones used by
+// tests/paths.sh.
+package bridgetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// RuntimeID is the identifier of the bridge runtime in the default network fixture.
+	RuntimeID = "8000000000000000000000000000000000000000000000000000000000000000"
+
+	// DefaultStartTimeout is the default time to wait for the network to become ready.
+	DefaultStartTimeout = 2 * time.Minute
+
+	// DefaultFundingAmount is the default amount of native tokens transferred to test accounts
+	// that are not funded in the runtime genesis.
+	DefaultFundingAmount = 100_000
+
+	clientSocketPath = "net-runner/network/client-0/internal.sock"
+	logFileName      = "net-runner.log"
+)
+
+// Environment variables used by ConfigFromEnv.
+const (
+	EnvNetRunner     = "TEST_NET_RUNNER"
+	EnvNodeBinary    = "TEST_NODE_BINARY"
+	EnvRuntimeLoader = "TEST_RUNTIME_LOADER"
+	EnvKeyManager    = "TEST_KM_BINARY"
+	EnvRuntime       = "TEST_BRIDGE_RUNTIME"
+)
+
+// ErrMissingBinaries is the error returned when the binaries required by the harness are not
+// configured.
+var ErrMissingBinaries = errors.New("bridgetest: missing binaries")
+
+// Config is the network configuration.
+type Config struct {
+	// NetRunnerBinary is the path to the oasis-net-runner binary.
+	NetRunnerBinary string
+	// NodeBinary is the path to the oasis-node binary.
+	NodeBinary string
+	// RuntimeLoaderBinary is the path to the oasis-core-runtime-loader binary.
+	RuntimeLoaderBinary string
+	// KeyManagerBinary is the path to the simple-keymanager binary.
+	KeyManagerBinary string
+	// RuntimeBinary is the path to the bridge runtime binary.
+	RuntimeBinary string
+
+	// BaseDir is the directory in which the network state is stored. If empty, a temporary
+	// directory is created and removed on teardown.
+	BaseDir string
+
+	// StartTimeout is the time to wait for the network to become ready. If zero,
+	// DefaultStartTimeout is used.
+	StartTimeout time.Duration
+
+	// Fund are the test accounts that are funded with DefaultFundingAmount from Alice in case
+	// they have no native balance.
+	Fund []sdkTesting.TestKey
+}
+
+// ConfigFromEnv returns a network configuration with binary paths taken from the environment.
+//
+// The test accounts used in the user/witness flow example (Alice, Bob and Dave) are funded.
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		NetRunnerBinary:     os.Getenv(EnvNetRunner),
+		NodeBinary:          os.Getenv(EnvNodeBinary),
+		RuntimeLoaderBinary: os.Getenv(EnvRuntimeLoader),
+		KeyManagerBinary:    os.Getenv(EnvKeyManager),
+		RuntimeBinary:       os.Getenv(EnvRuntime),
+		Fund:                []sdkTesting.TestKey{sdkTesting.Alice, sdkTesting.Bob, sdkTesting.Dave},
+	}
+	for _, v := range []struct {
+		env, value string
+	}{
+		{EnvNetRunner, cfg.NetRunnerBinary},
+		{EnvNodeBinary, cfg.NodeBinary},
+		{EnvRuntimeLoader, cfg.RuntimeLoaderBinary},
+		{EnvKeyManager, cfg.KeyManagerBinary},
+		{EnvRuntime, cfg.RuntimeBinary},
+	} {
+		if v.value == "" {
+			return nil, fmt.Errorf("%w: %s not set", ErrMissingBinaries, v.env)
+		}
+	}
+	return cfg, nil
+}
+
+// Network is a running local network with the bridge runtime.
+type Network struct {
+	// Client is a client connected to the network's client node.
+	Client *bridge.Client
+
+	cmd        *exec.Cmd
+	baseDir    string
+	removeDir  bool
+	logFile    *os.File
+	exited     chan struct{}
+	stopOnce   sync.Once
+	stopErr    error
+	logsLock   sync.Mutex
+	logsBuffer bytes.Buffer
+}
+
+// Write implements io.Writer and captures network runner output.
+func (n *Network) Write(p []byte) (int, error) {
+	n.logsLock.Lock()
+	defer n.logsLock.Unlock()
+
+	return n.logsBuffer.Write(p)
+}
+
+// Logs returns the output captured from the network runner so far.
+//
+// Logs of the individual nodes are stored in the network base directory.
+func (n *Network) Logs() []byte {
+	n.logsLock.Lock()
+	defer n.logsLock.Unlock()
+
+	return append([]byte{}, n.logsBuffer.Bytes()...)
+}
+
+// BaseDir returns the directory in which the network state and node logs are stored.
+func (n *Network) BaseDir() string {
+	return n.baseDir
+}
+
+// Stop tears down the network.
+func (n *Network) Stop() error {
+	n.stopOnce.Do(func() {
+		if n.Client != nil {
+			_ = n.Client.Close()
+		}
+		if n.cmd.Process != nil {
+			_ = n.cmd.Process.Signal(os.Interrupt)
+			select {
+			case <-n.exited:
+			case <-time.After(10 * time.Second):
+				_ = n.cmd.Process.Kill()
+				<-n.exited
+			}
+		}
+		_ = n.logFile.Close()
+		if n.removeDir {
+			n.stopErr = os.RemoveAll(n.baseDir)
+		}
+	})
+	return n.stopErr
+}
+
+func (n *Network) waitReady(ctx context.Context, runtimeID common.Namespace) error {
+	socketPath := filepath.Join(n.baseDir, clientSocketPath)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("bridgetest: network not ready: %w", ctx.Err())
+		case <-n.exited:
+			return fmt.Errorf("bridgetest: network runner exited: %s", n.cmd.ProcessState)
+		case <-ticker.C:
+		}
+
+		if _, err := os.Stat(socketPath); err != nil {
+			continue
+		}
+		if n.Client == nil {
+			c, err := bridge.Connect("unix:"+socketPath, runtimeID)
+			if err != nil {
+				return err
+			}
+			n.Client = c
+		}
+		// Wait for the runtime to produce its first block.
+		if _, err := n.Client.GetBlock(ctx, client.RoundLatest); err == nil {
+			return nil
+		}
+	}
+}
+
+func (n *Network) fund(ctx context.Context, keys []sdkTesting.TestKey) error {
+	for _, key := range keys {
+		balances, err := n.Client.Accounts.Balances(ctx, client.RoundLatest, key.Address)
+		if err != nil {
+			return fmt.Errorf("bridgetest: failed to query balances of %s: %w", key.Address, err)
+		}
+		if balance, ok := balances.Balances[types.NativeDenomination]; ok && !balance.IsZero() {
+			continue
+		}
+		if err = n.transfer(ctx, sdkTesting.Alice.Signer, key.Address, DefaultFundingAmount); err != nil {
+			return fmt.Errorf("bridgetest: failed to fund %s: %w", key.Address, err)
+		}
+	}
+	return nil
+}
+
+// transfer is the body of the accounts.Transfer call.
+type transfer struct {
+	To     types.Address   `json:"to"`
+	Amount types.BaseUnits `json:"amount"`
+}
+
+func (n *Network) transfer(ctx context.Context, signer signature.Signer, to types.Address, amount uint64) error {
+	info, err := n.Client.GetInfo(ctx)
+	if err != nil {
+		return err
+	}
+	nonce, err := n.Client.Accounts.Nonce(ctx, client.RoundLatest, bridge.AddressOf(signer))
+	if err != nil {
+		return err
+	}
+
+	tx := types.NewTransaction(nil, "accounts.Transfer", &transfer{
+		To:     to,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination),
+	})
+	tx.AppendAuthSignature(signer.Public(), nonce)
+	tb := tx.PrepareForSigning()
+	if err = tb.AppendSign(info.ChainContext, signer); err != nil {
+		return err
+	}
+	_, err = bridge.SubmitAndDecode[struct{}](ctx, n.Client, tb.UnverifiedTransaction())
+	return err
+}
+
+// Start boots a local network according to the given configuration, funds the configured test
+// accounts and returns once the bridge runtime is ready.
+func Start(ctx context.Context, cfg *Config) (*Network, error) {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(RuntimeID); err != nil {
+		return nil, err
+	}
+
+	n := &Network{
+		baseDir: cfg.BaseDir,
+		exited:  make(chan struct{}),
+	}
+	if n.baseDir == "" {
+		dir, err := ioutil.TempDir("", "oasis-bridge-test")
+		if err != nil {
+			return nil, fmt.Errorf("bridgetest: failed to create base directory: %w", err)
+		}
+		n.baseDir = dir
+		n.removeDir = true
+	}
+
+	logFile, err := os.Create(filepath.Join(n.baseDir, logFileName))
+	if err != nil {
+		return nil, fmt.Errorf("bridgetest: failed to create log file: %w", err)
+	}
+	n.logFile = logFile
+
+	n.cmd = exec.Command(cfg.NetRunnerBinary,
+		"--fixture.default.node.binary", cfg.NodeBinary,
+		"--fixture.default.runtime.binary", cfg.RuntimeBinary,
+		"--fixture.default.runtime.loader", cfg.RuntimeLoaderBinary,
+		"--fixture.default.keymanager.binary", cfg.KeyManagerBinary,
+		"--basedir", n.baseDir,
+		"--basedir.no_temp_dir",
+	)
+	n.cmd.Stdout = io.MultiWriter(n, logFile)
+	n.cmd.Stderr = n.cmd.Stdout
+	if err = n.cmd.Start(); err != nil {
+		_ = n.Stop()
+		return nil, fmt.Errorf("bridgetest: failed to start network runner: %w", err)
+	}
+	go func() {
+		_ = n.cmd.Wait()
+		close(n.exited)
+	}()
+
+	timeout := cfg.StartTimeout
+	if timeout == 0 {
+		timeout = DefaultStartTimeout
+	}
+	startCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err = n.waitReady(startCtx, runtimeID); err != nil {
+		_ = n.Stop()
+		return nil, err
+	}
+	if err = n.fund(startCtx, cfg.Fund); err != nil {
+		_ = n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+// New starts a local network configured from the environment for the duration of the given test.
+//
+// The test is skipped in case the required binaries are not configured. The network is torn down
+// once the test completes and the network runner output is logged in case the test failed.
+func New(t *testing.T) *Network {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	n, err := Start(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to start network: %s", err)
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("network runner output:\n%s", n.Logs())
+		}
+		if err := n.Stop(); err != nil {
+			t.Logf("failed to tear down network: %s", err)
+		}
+	})
+	return n
+}
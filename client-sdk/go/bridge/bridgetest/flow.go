@@ -0,0 +1,271 @@
+package bridgetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+// RemoteReleaseDenomination is the denomination released by RunFullFlow to simulate a release of
+// funds locked on the remote side of the bridge.
+const RemoteReleaseDenomination = types.Denomination("oETH")
+
+// FlowLocks are the locks made by the user in RunFullFlow.
+var FlowLocks = []bridge.Lock{
+	{
+		Target: bridge.NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	},
+	{
+		Target: bridge.NewRemoteAddressFromHex("0000000000000000000000000000000000000001"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(5), types.NativeDenomination),
+	},
+}
+
+// FlowReleases are the releases to the user made by the witnesses in RunFullFlow, in order. The
+// first one releases native funds previously locked on this side of the bridge and the second one
+// releases funds locked on the remote side of the bridge.
+var FlowReleases = []types.BaseUnits{
+	types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	types.NewBaseUnits(*quantity.NewFromUint64(10), RemoteReleaseDenomination),
+}
+
+// FlowResult is the result of RunFullFlow.
+type FlowResult struct {
+	// FromRound is the latest round before the flow started.
+	FromRound uint64
+	// ToRound is the latest round after the flow completed.
+	ToRound uint64
+
+	// LockIDs are the identifiers of the locks made by the user, in FlowLocks order.
+	LockIDs []uint64
+	// ReleaseIDs are the identifiers of the releases made to the user, in FlowReleases order.
+	ReleaseIDs []uint64
+
+	// SequencesBefore are the next sequence numbers before the flow started.
+	SequencesBefore *bridge.NextSequenceNumbers
+	// SequencesAfter are the next sequence numbers after the flow completed.
+	SequencesAfter *bridge.NextSequenceNumbers
+
+	// BalancesBefore are the user's balances before the flow started.
+	BalancesBefore map[types.Denomination]quantity.Quantity
+	// BalancesAfter are the user's balances after the flow completed.
+	BalancesAfter map[types.Denomination]quantity.Quantity
+
+	// Events are all bridge events emitted in rounds (FromRound, ToRound], in order.
+	Events []*bridge.Event
+	// Locks are the lock events among Events.
+	Locks []*bridge.LockEvent
+	// WitnessesSigned are the witnesses signed events among Events.
+	WitnessesSigned []*bridge.WitnessesSignedEvent
+	// Releases are the release events among Events.
+	Releases []*bridge.ReleaseEvent
+}
+
+// RunFullFlow runs the end-to-end bridge flow of the user/witness flow example against the given
+// client and fails the test in case any step fails or the outcome is not as expected.
+//
+// The user locks FlowLocks, each of the witnesses witnesses all of them and then each of the
+// witnesses signs off on FlowReleases to the user, simulating a release of funds locked on this
+// side of the bridge followed by a release of funds locked on the remote side. All steps are
+// performed sequentially so that the flow is deterministic.
+//
+// Once the flow completes, the advancement of the sequence numbers and the user's final balances
+// are asserted. Balance assertions assume that transactions do not pay fees (i.e. the default
+// zero gas price, see bridge.WithGasPrice). The returned result contains the events observed
+// during the flow for custom assertions.
+func RunFullFlow(t *testing.T, c *bridge.Client, user signature.Signer, witnesses ...signature.Signer) *FlowResult {
+	t.Helper()
+
+	ctx := context.Background()
+	userAddress := bridge.AddressOf(user)
+
+	params, err := c.Bridge.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		t.Fatalf("failed to query bridge parameters: %s", err)
+	}
+	if uint64(len(witnesses)) < params.Threshold {
+		t.Fatalf("need at least %d witnesses, got %d", params.Threshold, len(witnesses))
+	}
+
+	res := &FlowResult{}
+	blk, err := c.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		t.Fatalf("failed to fetch latest block: %s", err)
+	}
+	res.FromRound = blk.Header.Round
+	if res.SequencesBefore, err = c.Bridge.NextSequenceNumbers(ctx, client.RoundLatest); err != nil {
+		t.Fatalf("failed to query next sequence numbers: %s", err)
+	}
+	res.BalancesBefore = balances(ctx, t, c, userAddress)
+
+	// User locks funds.
+	for i := range FlowLocks {
+		result, err := c.Bridge.SubmitLock(ctx, user, &FlowLocks[i])
+		if err != nil {
+			t.Fatalf("failed to submit lock %d: %s", i, err)
+		}
+		res.LockIDs = append(res.LockIDs, result.ID)
+	}
+
+	// Witnesses witness the locks.
+	info, err := c.GetInfo(ctx)
+	if err != nil {
+		t.Fatalf("failed to fetch runtime info: %s", err)
+	}
+	for _, w := range witnesses {
+		ws := bridge.NewWitnessSigner(w, info.ChainContext)
+		for i, id := range res.LockIDs {
+			op := bridge.NewLockOperation(FlowLocks[i])
+			sig, err := ws.SignOperation(res.FromRound, id, &op)
+			if err != nil {
+				t.Fatalf("failed to sign lock %d by %s: %s", id, w.Public(), err)
+			}
+			err = c.Bridge.SubmitWitness(ctx, w, &bridge.Witness{
+				ID:        id,
+				Signature: bridge.EncodeWitnessSignature(sig),
+			})
+			if err != nil {
+				t.Fatalf("failed to witness lock %d by %s: %s", id, w.Public(), err)
+			}
+		}
+	}
+
+	// Witnesses release funds to the user.
+	for i, amount := range FlowReleases {
+		seq, err := c.Bridge.NextSequenceNumbers(ctx, client.RoundLatest)
+		if err != nil {
+			t.Fatalf("failed to query next sequence numbers: %s", err)
+		}
+		body := &bridge.Release{
+			ID:     seq.Incoming,
+			Target: userAddress,
+			Amount: amount,
+		}
+		for _, w := range witnesses {
+			if _, err = c.Bridge.SubmitRelease(ctx, w, body); err != nil {
+				t.Fatalf("failed to submit release %d by %s: %s", i, w.Public(), err)
+			}
+		}
+		res.ReleaseIDs = append(res.ReleaseIDs, body.ID)
+	}
+
+	if blk, err = c.GetBlock(ctx, client.RoundLatest); err != nil {
+		t.Fatalf("failed to fetch latest block: %s", err)
+	}
+	res.ToRound = blk.Header.Round
+	if res.SequencesAfter, err = c.Bridge.NextSequenceNumbers(ctx, client.RoundLatest); err != nil {
+		t.Fatalf("failed to query next sequence numbers: %s", err)
+	}
+	res.BalancesAfter = balances(ctx, t, c, userAddress)
+
+	if res.ToRound > res.FromRound {
+		err = c.Bridge.ReplayEvents(ctx, res.FromRound+1, res.ToRound, func(_ uint64, events []*bridge.Event) error {
+			for _, ev := range events {
+				res.Events = append(res.Events, ev)
+				switch {
+				case ev.Lock != nil:
+					res.Locks = append(res.Locks, ev.Lock)
+				case ev.WitnessesSigned != nil:
+					res.WitnessesSigned = append(res.WitnessesSigned, ev.WitnessesSigned)
+				case ev.Release != nil:
+					res.Releases = append(res.Releases, ev.Release)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to replay flow events: %s", err)
+		}
+	}
+
+	res.check(t)
+	return res
+}
+
+// check asserts the outcome of the flow.
+func (res *FlowResult) check(t *testing.T) {
+	t.Helper()
+
+	if got, want := res.SequencesAfter.Outgoing, res.SequencesBefore.Outgoing+uint64(len(FlowLocks)); got != want {
+		t.Fatalf("expected next outgoing sequence number %d, got %d", want, got)
+	}
+	if got, want := res.SequencesAfter.Incoming, res.SequencesBefore.Incoming+uint64(len(FlowReleases)); got != want {
+		t.Fatalf("expected next incoming sequence number %d, got %d", want, got)
+	}
+
+	if len(res.Locks) != len(FlowLocks) {
+		t.Fatalf("expected %d lock events, got %d", len(FlowLocks), len(res.Locks))
+	}
+	for i, ev := range res.Locks {
+		if ev.ID != res.LockIDs[i] || !ev.Target.Equal(FlowLocks[i].Target) || !amountsEqual(&ev.Amount, &FlowLocks[i].Amount) {
+			t.Fatalf("unexpected lock event %d: %+v", i, ev)
+		}
+	}
+	signed := make(map[uint64]bool)
+	for _, ev := range res.WitnessesSigned {
+		if ev.IsLock() {
+			signed[ev.ID] = true
+		}
+	}
+	for _, id := range res.LockIDs {
+		if !signed[id] {
+			t.Fatalf("lock %d has not been signed by the witnesses", id)
+		}
+	}
+	if len(res.Releases) != len(FlowReleases) {
+		t.Fatalf("expected %d release events, got %d", len(FlowReleases), len(res.Releases))
+	}
+	for i, ev := range res.Releases {
+		if ev.ID != res.ReleaseIDs[i] || !amountsEqual(&ev.Amount, &FlowReleases[i]) {
+			t.Fatalf("unexpected release event %d: %+v", i, ev)
+		}
+	}
+
+	// Compute the expected balance changes per denomination.
+	expected := make(map[types.Denomination]quantity.Quantity)
+	for denomination, balance := range res.BalancesBefore {
+		expected[denomination] = *balance.Clone()
+	}
+	for _, amount := range FlowReleases {
+		q := expected[amount.Denomination]
+		if err := q.Add(&amount.Amount); err != nil {
+			t.Fatalf("failed to compute expected balance: %s", err)
+		}
+		expected[amount.Denomination] = q
+	}
+	for _, lock := range FlowLocks {
+		q := expected[lock.Amount.Denomination]
+		if err := q.Sub(&lock.Amount.Amount); err != nil {
+			t.Fatalf("failed to compute expected balance: %s", err)
+		}
+		expected[lock.Amount.Denomination] = q
+	}
+	for denomination, want := range expected {
+		got := res.BalancesAfter[denomination]
+		if got.Cmp(&want) != 0 {
+			t.Fatalf("expected %s balance %s, got %s", denomination, &want, &got)
+		}
+	}
+}
+
+func balances(ctx context.Context, t *testing.T, c *bridge.Client, address types.Address) map[types.Denomination]quantity.Quantity {
+	t.Helper()
+
+	rsp, err := c.Accounts.Balances(ctx, client.RoundLatest, address)
+	if err != nil {
+		t.Fatalf("failed to query balances of %s: %s", address, err)
+	}
+	return rsp.Balances
+}
+
+func amountsEqual(a, b *types.BaseUnits) bool {
+	return a.Denomination == b.Denomination && a.Amount.Cmp(&b.Amount) == 0
+}
@@ -0,0 +1,13 @@
+package bridgetest
+
+import (
+	"testing"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+)
+
+func TestFullFlow(t *testing.T) {
+	n := New(t)
+
+	RunFullFlow(t, n.Client, sdkTesting.Alice.Signer, sdkTesting.Bob.Signer, sdkTesting.Dave.Signer)
+}
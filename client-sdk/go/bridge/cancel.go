@@ -0,0 +1,38 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrCancelNotSupported is the error returned when cancelling a lock which is not yet supported
+// by the bridge runtime module.
+var ErrCancelNotSupported = errors.New("bridge: lock cancellation is not supported by the runtime")
+
+// Cancel is the body of a Cancel call which requests the refund of a lock that has not been
+// witnessed by enough witnesses.
+type Cancel struct {
+	ID uint64 `json:"id"`
+}
+
+// CancelEvent is the cancel event emitted once a lock has been cancelled and refunded.
+type CancelEvent struct {
+	ID     uint64          `json:"id"`
+	Owner  types.Address   `json:"owner"`
+	Amount types.BaseUnits `json:"amount"`
+}
+
+// Implements V1.
+func (a *v1) Cancel(ctx context.Context, signer signature.Signer, id uint64, opts ...SubmitOption) error {
+	// TODO: Submit a bridge.Cancel transaction once the runtime supports cancelling locks.
+	return ErrCancelNotSupported
+}
+
+// Implements V1.
+func (a *v1) WaitForCancel(ctx context.Context, id uint64) (*CancelEvent, error) {
+	// TODO: Watch for the cancel event once the runtime supports cancelling locks.
+	return nil, ErrCancelNotSupported
+}
@@ -0,0 +1,110 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoCheckpoint is the error returned when no checkpoint has been stored yet.
+var ErrNoCheckpoint = errors.New("bridge: no checkpoint")
+
+// CheckpointStore persists the last round fully processed by an event processor so that
+// processing can be resumed after a restart.
+type CheckpointStore interface {
+	// Load returns the last processed round.
+	//
+	// Returns ErrNoCheckpoint in case no checkpoint has been stored yet.
+	Load() (uint64, error)
+
+	// Store persists the last processed round.
+	Store(round uint64) error
+}
+
+type memoryCheckpointStore struct {
+	sync.Mutex
+
+	round uint64
+	valid bool
+}
+
+// Implements CheckpointStore.
+func (s *memoryCheckpointStore) Load() (uint64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.valid {
+		return 0, ErrNoCheckpoint
+	}
+	return s.round, nil
+}
+
+// Implements CheckpointStore.
+func (s *memoryCheckpointStore) Store(round uint64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.round = round
+	s.valid = true
+	return nil
+}
+
+// NewMemoryCheckpointStore creates a new checkpoint store that keeps the checkpoint in memory.
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{}
+}
+
+type fileCheckpointStore struct {
+	path string
+}
+
+// Implements CheckpointStore.
+func (s *fileCheckpointStore) Load() (uint64, error) {
+	data, err := ioutil.ReadFile(s.path)
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		return 0, ErrNoCheckpoint
+	default:
+		return 0, fmt.Errorf("bridge: failed to read checkpoint: %w", err)
+	}
+
+	round, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bridge: malformed checkpoint: %w", err)
+	}
+	return round, nil
+}
+
+// Implements CheckpointStore.
+func (s *fileCheckpointStore) Store(round uint64) error {
+	// Write to a temporary file first and then rename it so the checkpoint is never torn.
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("bridge: failed to create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Fails after a successful rename, which is fine.
+
+	if _, err = tmp.WriteString(strconv.FormatUint(round, 10) + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("bridge: failed to write checkpoint: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("bridge: failed to write checkpoint: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("bridge: failed to store checkpoint: %w", err)
+	}
+	return nil
+}
+
+// NewFileCheckpointStore creates a new checkpoint store that persists the checkpoint in the
+// file at the given path.
+func NewFileCheckpointStore(path string) CheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
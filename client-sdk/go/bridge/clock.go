@@ -0,0 +1,214 @@
+package bridge
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of time used by the time-dependent bridge helpers (e.g., retries and
+// caches) so that they can be tested deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new timer that fires after the given duration.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the current time is sent when the timer fires.
+	C() <-chan time.Time
+
+	// Reset changes the timer to fire after the given duration.
+	Reset(d time.Duration)
+
+	// Stop prevents the timer from firing.
+	Stop()
+}
+
+type realClock struct{}
+
+// Implements Clock.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Implements Clock.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+// Implements Timer.
+func (t *realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Implements Timer.
+func (t *realTimer) Reset(d time.Duration) {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+	t.timer.Reset(d)
+}
+
+// Implements Timer.
+func (t *realTimer) Stop() {
+	t.timer.Stop()
+}
+
+// RealClock is the clock backed by the system time.
+var RealClock Clock = realClock{}
+
+// FakeClock is a clock whose time only changes when it is explicitly advanced.
+type FakeClock struct {
+	sync.Mutex
+
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// Implements Clock.
+func (c *FakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.now
+}
+
+// Implements Clock.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.Lock()
+	defer c.Unlock()
+
+	t := &fakeTimer{
+		clock: c,
+		ch:    make(chan time.Time, 1),
+	}
+	c.scheduleLocked(t, d)
+	return t
+}
+
+// Advance advances the clock by the given duration, firing any timers that expire.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	c.timers = pending
+}
+
+// PendingTimers returns the number of timers that have not yet fired or been stopped.
+func (c *FakeClock) PendingTimers() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.timers)
+}
+
+func (c *FakeClock) scheduleLocked(t *fakeTimer, d time.Duration) {
+	c.removeLocked(t)
+	t.deadline = c.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+		return
+	}
+	c.timers = append(c.timers, t)
+}
+
+func (c *FakeClock) removeLocked(t *fakeTimer) {
+	for i, pt := range c.timers {
+		if pt == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// NewFakeClock creates a new fake clock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	ch       chan time.Time
+	deadline time.Time
+}
+
+// Implements Timer.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Implements Timer.
+func (t *fakeTimer) Reset(d time.Duration) {
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	t.clock.scheduleLocked(t, d)
+}
+
+// Implements Timer.
+func (t *fakeTimer) Stop() {
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	t.clock.removeLocked(t)
+}
+
+// backoffTimer adapts a Clock to the backoff.Timer interface.
+type backoffTimer struct {
+	clock Clock
+	timer Timer
+}
+
+func (t *backoffTimer) Start(d time.Duration) {
+	if t.timer == nil {
+		t.timer = t.clock.NewTimer(d)
+		return
+	}
+	t.timer.Reset(d)
+}
+
+func (t *backoffTimer) Stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+func (t *backoffTimer) C() <-chan time.Time {
+	return t.timer.C()
+}
+
+// WithClock configures the clock used by time-dependent helpers.
+//
+// By default RealClock is used.
+func WithClock(clock Clock) V1Option {
+	return func(a *v1) {
+		a.clock = clock
+		a.infoCache.clock = clock
+	}
+}
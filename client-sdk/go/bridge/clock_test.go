@@ -0,0 +1,44 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1_600_000_000, 0)
+	clock := NewFakeClock(start)
+
+	timer := clock.NewTimer(10 * time.Second)
+	clock.Advance(9 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatalf("timer fired before its deadline")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case now := <-timer.C():
+		if expected := start.Add(10 * time.Second); !now.Equal(expected) {
+			t.Fatalf("unexpected fire time: expected %s, got %s", expected, now)
+		}
+	default:
+		t.Fatalf("timer did not fire at its deadline")
+	}
+	if n := clock.PendingTimers(); n != 0 {
+		t.Fatalf("unexpected number of pending timers: %d", n)
+	}
+
+	timer.Reset(5 * time.Second)
+	timer.Stop()
+	clock.Advance(time.Minute)
+	select {
+	case <-timer.C():
+		t.Fatalf("stopped timer fired")
+	default:
+	}
+	if now := clock.Now(); !now.Equal(start.Add(70 * time.Second)) {
+		t.Fatalf("unexpected current time: %s", now)
+	}
+}
@@ -0,0 +1,52 @@
+package bridge
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+)
+
+// Client is a bridge runtime client.
+type Client struct {
+	client.RuntimeClient
+
+	Accounts accounts.V1
+	Bridge   V1
+
+	conns []*grpc.ClientConn
+}
+
+// IsConnected returns true iff the underlying gRPC connection to at least one node is ready.
+func (c *Client) IsConnected() bool {
+	for _, conn := range c.conns {
+		if conn.GetState() == connectivity.Ready {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes the underlying gRPC connections to the nodes.
+func (c *Client) Close() error {
+	var firstErr error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Connect establishes a new gRPC connection with the node at the given address and returns a
+// client for the bridge runtime with the given identifier.
+//
+// The bridge client is configured with the node's consensus staking backend.
+func Connect(addr string, runtimeID common.Namespace) (*Client, error) {
+	return ConnectMulti(context.Background(), []string{addr}, runtimeID)
+}
@@ -0,0 +1,150 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Cursor is a named position in the bridge event stream (see WatchEvents) that is advanced by
+// acknowledging events once they have been handled.
+//
+// Several cursors with different names (e.g., "witness" and "relayer") can follow the same event
+// stream at different speeds, each persisting its own progress in a separate checkpoint store.
+// The checkpoint store holds the last round whose events have all been acknowledged, while the
+// acknowledged events of the round in progress are only tracked in memory. After a restart, the
+// events of a partially handled round are thus delivered again and handlers must be idempotent.
+//
+// A cursor does not run handlers itself, so stopping it only requires no longer acknowledging
+// events. The event stream of the witness is instead driven by an EventProcessor, which runs the
+// handlers and persists checkpoints itself; use EventProcessor.Stop to drain it for a clean
+// shutdown.
+type Cursor struct {
+	sync.Mutex
+
+	name  string
+	store CheckpointStore
+
+	loaded          bool
+	checkpoint      uint64
+	checkpointValid bool
+
+	round uint64
+	acked map[uint32]bool
+}
+
+// NewCursor creates a new event cursor with the given name whose progress is persisted in the
+// given checkpoint store. Cursors following the same event stream must use separate stores.
+func NewCursor(name string, store CheckpointStore) *Cursor {
+	return &Cursor{
+		name:  name,
+		store: store,
+		acked: make(map[uint32]bool),
+	}
+}
+
+// Name returns the name of the cursor.
+func (c *Cursor) Name() string {
+	return c.name
+}
+
+// load loads the persisted checkpoint unless already loaded. The cursor lock must be held.
+func (c *Cursor) load() error {
+	if c.loaded {
+		return nil
+	}
+	round, err := c.store.Load()
+	switch {
+	case err == nil:
+		c.checkpoint, c.checkpointValid = round, true
+	case errors.Is(err, ErrNoCheckpoint):
+	default:
+		return fmt.Errorf("bridge: failed to load checkpoint of cursor %s: %w", c.name, err)
+	}
+	c.loaded = true
+	return nil
+}
+
+// storeCheckpoint persists the given round as the last fully handled round. The cursor lock must
+// be held.
+func (c *Cursor) storeCheckpoint(round uint64) error {
+	if c.checkpointValid && round <= c.checkpoint {
+		return nil
+	}
+	if err := c.store.Store(round); err != nil {
+		return fmt.Errorf("bridge: failed to store checkpoint of cursor %s: %w", c.name, err)
+	}
+	c.checkpoint, c.checkpointValid = round, true
+	if c.round <= round {
+		c.round = round + 1
+		c.acked = make(map[uint32]bool)
+	}
+	return nil
+}
+
+// Checkpoint returns the last round whose events have all been acknowledged and true, or false in
+// case no round has been fully handled yet. Handling should resume with the following round.
+func (c *Cursor) Checkpoint() (uint64, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.load(); err != nil {
+		return 0, false, err
+	}
+	return c.checkpoint, c.checkpointValid, nil
+}
+
+// Acked returns true iff the given event has already been acknowledged, in which case it should
+// not be handled again.
+func (c *Cursor) Acked(ev *Event) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.load(); err != nil {
+		return false, err
+	}
+	if c.checkpointValid && ev.Round <= c.checkpoint {
+		return true, nil
+	}
+	return ev.Round == c.round && c.acked[ev.Index], nil
+}
+
+// Ack acknowledges that the given event has been handled.
+//
+// Events of a round may be acknowledged in any order. Acknowledging an event of a later round
+// implies that all events of the earlier rounds have been handled, so the round preceding it is
+// persisted as the checkpoint. Use AckRound to mark the last round as fully handled.
+func (c *Cursor) Ack(ev *Event) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+	if c.checkpointValid && ev.Round <= c.checkpoint {
+		return nil
+	}
+	if ev.Round > 0 {
+		if err := c.storeCheckpoint(ev.Round - 1); err != nil {
+			return err
+		}
+	}
+	if ev.Round != c.round {
+		c.round = ev.Round
+		c.acked = make(map[uint32]bool)
+	}
+	c.acked[ev.Index] = true
+	return nil
+}
+
+// AckRound acknowledges that all events of the given round (and of the rounds before it) have
+// been handled and persists it as the checkpoint.
+func (c *Cursor) AckRound(round uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+	return c.storeCheckpoint(round)
+}
@@ -0,0 +1,86 @@
+package bridge
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	dir := t.TempDir()
+	witnessStore := NewFileCheckpointStore(filepath.Join(dir, "witness.checkpoint"))
+	relayerStore := NewFileCheckpointStore(filepath.Join(dir, "relayer.checkpoint"))
+	witness := NewCursor("witness", witnessStore)
+	relayer := NewCursor("relayer", relayerStore)
+
+	checkAcked := func(c *Cursor, ev *Event, expected bool) {
+		t.Helper()
+		acked, err := c.Acked(ev)
+		if err != nil {
+			t.Fatalf("Acked: %v", err)
+		}
+		if acked != expected {
+			t.Fatalf("expected event %d/%d acked by %s to be %t", ev.Round, ev.Index, c.Name(), expected)
+		}
+	}
+	checkCheckpoint := func(c *Cursor, round uint64, valid bool) {
+		t.Helper()
+		r, ok, err := c.Checkpoint()
+		if err != nil {
+			t.Fatalf("Checkpoint: %v", err)
+		}
+		if ok != valid || (valid && r != round) {
+			t.Fatalf("expected checkpoint %d (%t) of %s, got %d (%t)", round, valid, c.Name(), r, ok)
+		}
+	}
+
+	events := []*Event{
+		{Round: 1, Index: 0},
+		{Round: 1, Index: 1},
+		{Round: 2, Index: 0},
+		{Round: 3, Index: 0},
+	}
+	checkCheckpoint(witness, 0, false)
+
+	// Events of a round may be acknowledged out of order.
+	for _, ev := range []*Event{events[1], events[0]} {
+		if err := witness.Ack(ev); err != nil {
+			t.Fatalf("Ack: %v", err)
+		}
+	}
+	checkAcked(witness, events[0], true)
+	checkAcked(witness, events[1], true)
+	checkAcked(witness, events[2], false)
+	checkCheckpoint(witness, 0, true)
+
+	// Acknowledging an event of a later round completes the earlier rounds.
+	if err := witness.Ack(events[3]); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	checkCheckpoint(witness, 2, true)
+	checkAcked(witness, events[2], true)
+
+	// The relayer cursor follows the same events independently.
+	checkCheckpoint(relayer, 0, false)
+	checkAcked(relayer, events[0], false)
+	if err := relayer.Ack(events[0]); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	checkAcked(relayer, events[0], true)
+	checkAcked(relayer, events[1], false)
+	checkCheckpoint(witness, 2, true)
+
+	// Only fully handled rounds are persisted, so events of partially handled rounds are delivered
+	// again after a restart.
+	witness = NewCursor("witness", witnessStore)
+	checkCheckpoint(witness, 2, true)
+	checkAcked(witness, events[2], true)
+	checkAcked(witness, events[3], false)
+	if err := witness.AckRound(3); err != nil {
+		t.Fatalf("AckRound: %v", err)
+	}
+	checkAcked(witness, events[3], true)
+
+	relayer = NewCursor("relayer", relayerStore)
+	checkCheckpoint(relayer, 0, true)
+	checkAcked(relayer, events[0], false)
+}
@@ -0,0 +1,259 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+)
+
+// DryRunSuffix is appended to the checkpoint and signing history paths of a witness daemon in
+// dry-run mode (see Config).
+const DryRunSuffix = ".dry-run"
+
+// ErrNoWitnessSigners is the error returned when a witness daemon is configured without any
+// witness keys.
+var ErrNoWitnessSigners = errors.New("bridge: no witness signers configured")
+
+// Config is the configuration of a witness daemon (see RunWitness).
+type Config struct {
+	// NodeAddress is the gRPC address of the Oasis node.
+	NodeAddress string
+
+	// FailoverAddresses are the gRPC addresses of additional Oasis nodes to fail over to in case
+	// the node at NodeAddress is unavailable (see ConnectMulti).
+	FailoverAddresses []string
+
+	// RuntimeID is the identifier of the bridge runtime.
+	RuntimeID common.Namespace
+
+	// ExpectedChainContext is the chain context all nodes must have (see
+	// WithExpectedChainContext). If empty, the chain context of the nodes is not verified.
+	ExpectedChainContext signature.Context
+
+	// ConnectTimeout is how long the witness waits for the node at NodeAddress when connecting
+	// (see ConnectMulti). Zero disables the timeout.
+	ConnectTimeout time.Duration
+
+	// Signers are the witness keys managed by the daemon. Each of them must be an authorized
+	// witness.
+	Signers []signature.Signer
+
+	// CheckpointPath is the path of the file used to persist witness progress. If empty,
+	// progress is only kept in memory and witnessing starts with the latest round.
+	CheckpointPath string
+
+	// SigningHistoryPath is the path of the file used to record signed operations (see
+	// SigningHistory). If empty, no signing history is kept.
+	SigningHistoryPath string
+
+	// DryRun configures whether the witness runs in dry-run mode (see WithDryRun). Progress and
+	// the signing history are stored next to the regular ones in files with the DryRunSuffix.
+	DryRun bool
+
+	// MinFeeBalance is the witness account balance below which a warning is emitted (see
+	// WithMinFeeBalance).
+	MinFeeBalance *quantity.Quantity
+
+	// SubmitJitter is the maximum random delay before submitting witness transactions (see
+	// WithSubmitJitter).
+	SubmitJitter time.Duration
+
+	// RawEventLogging configures whether the raw bytes of all fetched events are logged (see
+	// WithRawEventLogging).
+	RawEventLogging bool
+
+	// GetEventsTimeout is the timeout of each call fetching the events of a round (see
+	// WithGetEventsTimeout). Zero disables the timeout.
+	GetEventsTimeout time.Duration
+
+	// ParametersCacheSize is the number of rounds whose bridge parameters are cached (see
+	// WithParametersCache). Zero disables the cache.
+	ParametersCacheSize int
+
+	// ParametersRefreshInterval is the interval at which the bridge parameters are queried to
+	// detect witness rotations and threshold changes (see WithParametersRefreshInterval). If
+	// zero, DefaultParametersRefreshInterval is used.
+	ParametersRefreshInterval time.Duration
+
+	// DrainTimeout is how long the witness waits for the round being processed to be finished
+	// and checkpointed once the context is canceled (see EventProcessor.Stop). If zero,
+	// processing is aborted as soon as the context is canceled.
+	DrainTimeout time.Duration
+
+	// CatchUpOnly configures the witness to exit once it has processed all rounds up to the
+	// latest round instead of tailing new rounds (see EventProcessor.RunOnce).
+	CatchUpOnly bool
+
+	// ProcessorOptions are additional options for the event processor.
+	ProcessorOptions []ProcessorOption
+
+	// OnStart is invoked once the witnesses have been authorized, right before events are
+	// processed (e.g., to start serving health checks). An error aborts the daemon.
+	OnStart func(ctx context.Context, c *Client, p *EventProcessor) error
+}
+
+// bridgeOptions returns the options for the bridge module clients used by the daemon.
+func (cfg *Config) bridgeOptions() []V1Option {
+	return []V1Option{
+		WithRawEventLogging(cfg.RawEventLogging),
+		WithGetEventsTimeout(cfg.GetEventsTimeout),
+		WithParametersCache(cfg.ParametersCacheSize),
+	}
+}
+
+// RunWitness connects to the node, checks that all configured witness keys are authorized and
+// witnesses lock events, resuming from the checkpoint if one is available and then tailing new
+// rounds, until the context is canceled. In catch-up only mode (see Config.CatchUpOnly), it
+// returns once the latest round has been processed instead.
+//
+// It returns nil once the context is canceled or the first fatal error otherwise.
+func RunWitness(ctx context.Context, cfg Config) error {
+	if len(cfg.Signers) == 0 {
+		return ErrNoWitnessSigners
+	}
+
+	connectCtx := ctx
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		connectCtx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+	rc, err := ConnectMulti(
+		connectCtx,
+		append([]string{cfg.NodeAddress}, cfg.FailoverAddresses...),
+		cfg.RuntimeID,
+		WithBridgeOptions(cfg.bridgeOptions()...),
+		WithExpectedChainContext(cfg.ExpectedChainContext),
+	)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return runWitness(ctx, cfg, rc)
+}
+
+// runWitness runs the witness daemon with the given configuration using the given client.
+func runWitness(ctx context.Context, cfg Config, rc *Client) error {
+	info, err := rc.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to fetch runtime info: %w", err)
+	}
+
+	var signerOpts []WitnessSignerOption
+	if path := cfg.SigningHistoryPath; path != "" {
+		if cfg.DryRun {
+			path += DryRunSuffix
+		}
+		history, err := OpenSigningHistory(path)
+		if err != nil {
+			return err
+		}
+		defer history.Close()
+		signerOpts = append(signerOpts, WithSigningHistory(history))
+	}
+
+	var witnesses []WitnessIdentity
+	for _, signer := range cfg.Signers {
+		index, err := EnsureAuthorizedWitness(ctx, rc.Bridge, signer.Public())
+		if err != nil {
+			return err
+		}
+		logger.Info("starting witness",
+			"public_key", signer.Public(),
+			"index", index,
+		)
+
+		witnesses = append(witnesses, WitnessIdentity{
+			Signer:        signer,
+			WitnessSigner: NewWitnessSigner(signer, info.ChainContext, signerOpts...),
+		})
+	}
+
+	opts := []ProcessorOption{
+		WithDryRun(cfg.DryRun),
+		WithProcessorBridgeOptions(cfg.bridgeOptions()...),
+	}
+	if path := cfg.CheckpointPath; path != "" {
+		if cfg.DryRun {
+			opts = append(opts, WithDryRunCheckpointStore(NewFileCheckpointStore(path+DryRunSuffix)))
+		} else {
+			opts = append(opts, WithCheckpointStore(NewFileCheckpointStore(path)))
+		}
+	}
+	if cfg.MinFeeBalance != nil {
+		opts = append(opts, WithMinFeeBalance(*cfg.MinFeeBalance))
+	}
+	if cfg.SubmitJitter > 0 {
+		opts = append(opts, WithSubmitJitter(cfg.SubmitJitter))
+	}
+	if cfg.ParametersRefreshInterval > 0 {
+		opts = append(opts, WithParametersRefreshInterval(cfg.ParametersRefreshInterval))
+	}
+	processor := NewEventProcessor(rc, witnesses, append(opts, cfg.ProcessorOptions...)...)
+
+	if cfg.OnStart != nil {
+		if err = cfg.OnStart(ctx, rc, processor); err != nil {
+			return err
+		}
+	}
+
+	runCtx, cancel := drainContext(ctx, processor, cfg.DrainTimeout)
+	defer cancel()
+	if cfg.CatchUpOnly {
+		var round uint64
+		if round, err = processor.RunOnce(runCtx); err == nil {
+			logger.Info("witness caught up",
+				"round", round,
+			)
+		}
+	} else {
+		err = processor.Run(runCtx)
+	}
+	if err == nil || errors.Is(err, context.Canceled) {
+		logger.Info("witness stopped")
+		return nil
+	}
+	return fmt.Errorf("bridge: witness failed: %w", err)
+}
+
+// drainContext returns the context in which the event processor is run. Once the given context
+// is canceled, the processor is stopped gracefully and the returned context is only canceled in
+// case stopping takes longer than the given timeout.
+func drainContext(
+	ctx context.Context,
+	processor *EventProcessor,
+	timeout time.Duration,
+) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		select {
+		case <-runCtx.Done():
+			return
+		case <-ctx.Done():
+		}
+
+		logger.Info("draining witness",
+			"timeout", timeout,
+		)
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), timeout)
+		defer stopCancel()
+		if err := processor.Stop(stopCtx); err != nil {
+			logger.Warn("witness did not drain in time, aborting",
+				"err", err,
+			)
+		}
+	}()
+	return runCtx, cancel
+}
@@ -0,0 +1,235 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// newDaemonClient returns a bridge client using the given runtime client as the witness daemon
+// would after connecting to the node.
+func newDaemonClient(rc client.RuntimeClient, cfg *Config) *Client {
+	return &Client{
+		RuntimeClient: rc,
+		Accounts:      accounts.NewV1(rc),
+		Bridge:        NewV1(rc, cfg.bridgeOptions()...),
+	}
+}
+
+// daemonClient is a watch client of a runtime with the configured bridge parameters whose
+// GetEvents calls for the blocked round do not return until released or canceled.
+type daemonClient struct {
+	watchClient
+
+	params       *Parameters
+	blockedRound uint64
+	entered      chan struct{}
+	release      chan struct{}
+}
+
+func (rc *daemonClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *daemonClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*Parameters) = *rc.params
+	return nil
+}
+
+func (rc *daemonClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	if round != rc.blockedRound {
+		return nil, nil
+	}
+	close(rc.entered)
+	select {
+	case <-rc.release:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestRunWitness(t *testing.T) {
+	params := &Parameters{
+		Witnesses: []types.PublicKey{{PublicKey: sdkTesting.Alice.Signer.Public()}},
+		Threshold: 1,
+	}
+
+	if err := RunWitness(context.Background(), Config{}); !errors.Is(err, ErrNoWitnessSigners) {
+		t.Fatalf("expected ErrNoWitnessSigners, got %v", err)
+	}
+
+	t.Run("NotAuthorized", func(t *testing.T) {
+		cfg := Config{
+			Signers:     []signature.Signer{sdkTesting.Alice.Signer, sdkTesting.Bob.Signer},
+			CatchUpOnly: true,
+			OnStart: func(context.Context, *Client, *EventProcessor) error {
+				t.Fatalf("unexpected start of unauthorized witnesses")
+				return nil
+			},
+		}
+		rc := &headClient{submissionClient: newSubmissionClient(params, 1, make(map[types.Address]uint64)), head: 1}
+		if err := runWitness(context.Background(), cfg, newDaemonClient(rc, &cfg)); !errors.Is(err, ErrNotAuthorizedWitness) {
+			t.Fatalf("expected ErrNotAuthorizedWitness, got %v", err)
+		}
+	})
+
+	t.Run("OnStartFailure", func(t *testing.T) {
+		errStart := errors.New("address in use")
+		cfg := Config{
+			Signers:     []signature.Signer{sdkTesting.Alice.Signer},
+			CatchUpOnly: true,
+			OnStart: func(context.Context, *Client, *EventProcessor) error {
+				return errStart
+			},
+		}
+		rc := &headClient{submissionClient: newSubmissionClient(params, 1, make(map[types.Address]uint64)), head: 1}
+		if err := runWitness(context.Background(), cfg, newDaemonClient(rc, &cfg)); !errors.Is(err, errStart) {
+			t.Fatalf("expected start error, got %v", err)
+		}
+		if rc.submits != 0 {
+			t.Fatalf("expected no submissions after a failed start, got %d", rc.submits)
+		}
+	})
+
+	t.Run("Paths", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointPath := filepath.Join(dir, "checkpoint")
+		historyPath := filepath.Join(dir, "history")
+
+		run := func(dryRun bool) *headClient {
+			t.Helper()
+			cfg := Config{
+				Signers:            []signature.Signer{sdkTesting.Alice.Signer},
+				CheckpointPath:     checkpointPath,
+				SigningHistoryPath: historyPath,
+				DryRun:             dryRun,
+				CatchUpOnly:        true,
+			}
+			rc := &headClient{submissionClient: newSubmissionClient(params, 2, make(map[types.Address]uint64)), head: 3}
+			if err := runWitness(context.Background(), cfg, newDaemonClient(rc, &cfg)); err != nil {
+				t.Fatalf("runWitness: %s", err)
+			}
+			return rc
+		}
+		expectCheckpoint := func(path string, expected uint64) {
+			t.Helper()
+			if round, err := NewFileCheckpointStore(path).Load(); err != nil || round != expected {
+				t.Fatalf("expected checkpoint of round %d at %s, got %d (err: %v)", expected, path, round, err)
+			}
+		}
+		expectHistory := func(path string, expected int) {
+			t.Helper()
+			history, err := OpenSigningHistory(path)
+			if err != nil {
+				t.Fatalf("OpenSigningHistory: %s", err)
+			}
+			defer history.Close()
+			records, err := history.Records(time.Time{})
+			if err != nil {
+				t.Fatalf("Records: %s", err)
+			}
+			if len(records) != expected {
+				t.Fatalf("expected %d signing records at %s, got %d", expected, path, len(records))
+			}
+			for _, r := range records {
+				if r.Round != 3 || !r.PublicKey.PublicKey.Equal(sdkTesting.Alice.Signer.Public()) {
+					t.Fatalf("unexpected signing record %+v", r)
+				}
+			}
+		}
+		expectMissing := func(path string) {
+			t.Helper()
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("expected %s not to exist, got %v", path, err)
+			}
+		}
+
+		// In dry-run mode, progress and signed operations are stored in separate files.
+		if rc := run(true); rc.submits != 0 {
+			t.Fatalf("expected no submissions in dry-run mode, got %d", rc.submits)
+		}
+		expectCheckpoint(checkpointPath+DryRunSuffix, 3)
+		expectHistory(historyPath+DryRunSuffix, 2)
+		expectMissing(checkpointPath)
+		expectMissing(historyPath)
+
+		// So the regular ones are not affected by dry runs.
+		if rc := run(false); rc.submits != 2 {
+			t.Fatalf("expected 2 submissions, got %d", rc.submits)
+		}
+		expectCheckpoint(checkpointPath, 3)
+		expectHistory(historyPath, 2)
+		expectHistory(historyPath+DryRunSuffix, 2)
+	})
+
+	t.Run("Drain", func(t *testing.T) {
+		for _, tc := range []struct {
+			name       string
+			release    bool
+			checkpoint uint64
+		}{
+			// The round being processed is finished and checkpointed before returning.
+			{"Drained", true, 2},
+			// Unless it takes longer than the drain timeout, in which case it is aborted.
+			{"TimedOut", false, 1},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				rc := &daemonClient{
+					params:       params,
+					blockedRound: 2,
+					entered:      make(chan struct{}),
+					release:      make(chan struct{}),
+				}
+				checkpoints := NewMemoryCheckpointStore()
+				cfg := Config{
+					Signers:          []signature.Signer{sdkTesting.Alice.Signer},
+					DrainTimeout:     100 * time.Millisecond,
+					ProcessorOptions: []ProcessorOption{WithCheckpointStore(checkpoints)},
+				}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				runErr := make(chan error, 1)
+				go func() {
+					runErr <- runWitness(ctx, cfg, newDaemonClient(rc, &cfg))
+				}()
+				rc.produce(1)
+				rc.produce(2)
+				<-rc.entered
+
+				cancel()
+				if tc.release {
+					select {
+					case err := <-runErr:
+						t.Fatalf("runWitness returned before the in-flight round was processed: %v", err)
+					case <-time.After(20 * time.Millisecond):
+					}
+					close(rc.release)
+				}
+				select {
+				case err := <-runErr:
+					if err != nil {
+						t.Fatalf("runWitness: %s", err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("runWitness did not return after draining")
+				}
+
+				if round, err := checkpoints.Load(); err != nil || round != tc.checkpoint {
+					t.Fatalf("expected checkpoint of round %d, got %d (err: %v)", tc.checkpoint, round, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,378 @@
+package bridge
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+const (
+	// EIP712SignatureSize is the size of an EIP-712 witness signature (r || s || v) in bytes.
+	EIP712SignatureSize = 65
+
+	// EIP712OperationType is the EIP-712 type of signed operations. The chain context is the
+	// chain context of the runtime (see SigningPreimage), the kind is the numeric value of the
+	// OperationKind, the target is the lock's remote address or the release's runtime address
+	// and the denomination is the local denomination of the operation.
+	EIP712OperationType = "Operation(string chainContext,uint64 id,uint8 kind,bytes target,uint256 amount,bytes denomination)"
+
+	// EIP712DomainType is the EIP-712 type of the signing domain.
+	EIP712DomainType = "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+
+	ethereumAddressSize = 20
+)
+
+var (
+	// ErrMalformedEIP712Signature is the error returned when recovering the signer of a malformed
+	// EIP-712 signature.
+	ErrMalformedEIP712Signature = errors.New("bridge: malformed EIP-712 signature")
+
+	// ErrInvalidEIP712Domain is the error returned when an EIP-712 domain is not valid.
+	ErrInvalidEIP712Domain = errors.New("bridge: invalid EIP-712 domain")
+
+	eip712OperationTypeHash = keccak256([]byte(EIP712OperationType))
+	eip712DomainTypeHash    = keccak256([]byte(EIP712DomainType))
+)
+
+// EIP712Domain is the EIP-712 signing domain of the contract verifying witness signatures on the
+// remote chain.
+type EIP712Domain struct {
+	// Name is the name of the signing domain.
+	Name string `json:"name"`
+
+	// Version is the version of the signing domain.
+	Version string `json:"version"`
+
+	// ChainID is the EIP-155 chain identifier of the remote chain.
+	ChainID uint64 `json:"chain_id"`
+
+	// VerifyingContract is the 20-byte address of the contract verifying the signatures.
+	VerifyingContract RemoteAddress `json:"verifying_contract"`
+}
+
+// Validate checks that the domain is well-formed.
+func (d *EIP712Domain) Validate() error {
+	if len(d.VerifyingContract) != ethereumAddressSize {
+		return fmt.Errorf("%w: verifying contract must be %d bytes, got %d",
+			ErrInvalidEIP712Domain, ethereumAddressSize, len(d.VerifyingContract))
+	}
+	return nil
+}
+
+// Separator returns the EIP-712 domain separator.
+func (d *EIP712Domain) Separator() []byte {
+	return keccak256(
+		eip712DomainTypeHash,
+		keccak256([]byte(d.Name)),
+		keccak256([]byte(d.Version)),
+		abiUint(new(big.Int).SetUint64(d.ChainID)),
+		leftPad(d.VerifyingContract),
+	)
+}
+
+// eip712Operation is the flattened form of an operation as defined by EIP712OperationType.
+type eip712Operation struct {
+	chainContext signature.Context
+	id           uint64
+	kind         OperationKind
+	target       []byte
+	amount       *big.Int
+	denomination []byte
+}
+
+func newEIP712Operation(chainContext signature.Context, id uint64, op *Operation) (*eip712Operation, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	eop := &eip712Operation{
+		chainContext: chainContext,
+		id:           id,
+		kind:         op.Kind(),
+	}
+	var amount types.BaseUnits
+	switch eop.kind {
+	case OperationLock:
+		eop.target = op.Lock.Target
+		amount = op.Lock.Amount
+	case OperationRelease:
+		target, err := op.Release.Target.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		eop.target = target
+		amount = op.Release.Amount
+	}
+	eop.amount = amount.Amount.ToBigInt()
+	if eop.amount.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: amount does not fit into uint256", ErrInvalidOperation)
+	}
+	eop.denomination = []byte(amount.Denomination)
+	return eop, nil
+}
+
+func (eop *eip712Operation) structHash() []byte {
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], eop.id)
+	return keccak256(
+		eip712OperationTypeHash,
+		keccak256([]byte(eop.chainContext)),
+		leftPad(id[:]),
+		leftPad([]byte{byte(eop.kind)}),
+		keccak256(eop.target),
+		abiUint(eop.amount),
+		keccak256(eop.denomination),
+	)
+}
+
+// EIP712Digest returns the EIP-712 digest that is signed for the operation with the given
+// identifier of the runtime with the given chain context, i.e.
+// keccak256("\x19\x01" || domainSeparator || hashStruct(operation)).
+func EIP712Digest(domain *EIP712Domain, chainContext signature.Context, id uint64, op *Operation) ([]byte, error) {
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	eop, err := newEIP712Operation(chainContext, id, op)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte{0x19, 0x01}, domain.Separator(), eop.structHash()), nil
+}
+
+type eip712Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type eip712TypedData struct {
+	Types       map[string][]eip712Field `json:"types"`
+	PrimaryType string                   `json:"primaryType"`
+	Domain      map[string]interface{}   `json:"domain"`
+	Message     map[string]interface{}   `json:"message"`
+}
+
+// EIP712TypedData returns the JSON encoding of the typed data signed for the operation with the
+// given identifier of the runtime with the given chain context in the format accepted by
+// eth_signTypedData_v4, e.g. for documenting the signed structure or checking signatures with
+// Ethereum tooling.
+func EIP712TypedData(domain *EIP712Domain, chainContext signature.Context, id uint64, op *Operation) ([]byte, error) {
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	eop, err := newEIP712Operation(chainContext, id, op)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&eip712TypedData{
+		Types: map[string][]eip712Field{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Operation": {
+				{Name: "chainContext", Type: "string"},
+				{Name: "id", Type: "uint64"},
+				{Name: "kind", Type: "uint8"},
+				{Name: "target", Type: "bytes"},
+				{Name: "amount", Type: "uint256"},
+				{Name: "denomination", Type: "bytes"},
+			},
+		},
+		PrimaryType: "Operation",
+		Domain: map[string]interface{}{
+			"name":              domain.Name,
+			"version":           domain.Version,
+			"chainId":           domain.ChainID,
+			"verifyingContract": hexPrefixed(domain.VerifyingContract),
+		},
+		Message: map[string]interface{}{
+			"chainContext": string(eop.chainContext),
+			"id":           eop.id,
+			"kind":         uint8(eop.kind),
+			"target":       hexPrefixed(eop.target),
+			"amount":       eop.amount.String(),
+			"denomination": hexPrefixed(eop.denomination),
+		},
+	})
+}
+
+// EthereumAddressOfPublicKey returns the Ethereum address of the given secp256k1 public key, i.e.
+// the address recovered from its EIP-712 signatures.
+func EthereumAddressOfPublicKey(pk secp256k1.PublicKey) RemoteAddress {
+	bpk := btcec.PublicKey(pk)
+	// Skip the uncompressed point prefix.
+	return RemoteAddress(keccak256(bpk.SerializeUncompressed()[1:])[32-ethereumAddressSize:])
+}
+
+// RecoverEIP712Signer returns the Ethereum address of the witness that produced the given EIP-712
+// signature (r || s || v, with v being either 0/1 or 27/28) over the operation with the given
+// identifier of the runtime with the given chain context. Callers must compare the address to
+// the expected witness addresses.
+func RecoverEIP712Signer(
+	domain *EIP712Domain,
+	chainContext signature.Context,
+	id uint64,
+	op *Operation,
+	sig []byte,
+) (RemoteAddress, error) {
+	if len(sig) != EIP712SignatureSize {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrMalformedEIP712Signature, EIP712SignatureSize, len(sig))
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("%w: invalid recovery identifier", ErrMalformedEIP712Signature)
+	}
+
+	digest, err := EIP712Digest(domain, chainContext, id, op)
+	if err != nil {
+		return nil, err
+	}
+	// Convert into the compact format (v || r || s) of an uncompressed key.
+	compact := make([]byte, 0, EIP712SignatureSize)
+	compact = append(compact, 27+v)
+	compact = append(compact, sig[:64]...)
+	pk, _, err := btcec.RecoverCompact(btcec.S256(), compact, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformedEIP712Signature, err)
+	}
+	return EthereumAddressOfPublicKey(secp256k1.PublicKey(*pk)), nil
+}
+
+type eip712WitnessSigner struct {
+	privateKey   *btcec.PrivateKey
+	domain       EIP712Domain
+	chainContext signature.Context
+	history      *SigningHistory
+	clock        Clock
+}
+
+// Implements WitnessSigner.
+func (s *eip712WitnessSigner) SignOperation(round, id uint64, op *Operation) ([]byte, error) {
+	digest, err := EIP712Digest(&s.domain, s.chainContext, id, op)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign operation %d: %w", id, err)
+	}
+	compact, err := btcec.SignCompact(btcec.S256(), s.privateKey, digest, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign operation %d: %w", id, err)
+	}
+	// Convert from the compact format (v || r || s) into the Ethereum format (r || s || v).
+	sig := append(compact[1:], compact[0])
+
+	if s.history != nil {
+		err = s.history.append(&SigningRecord{
+			Time:         s.clock.Now(),
+			Round:        round,
+			ID:           id,
+			PublicKey:    types.PublicKey{PublicKey: s.public()},
+			PreimageHash: hash.NewFromBytes(digest),
+			Signature:    sig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record signed operation %d: %w", id, err)
+		}
+	}
+	return sig, nil
+}
+
+// Implements WitnessSigner.
+func (s *eip712WitnessSigner) History(since time.Time) ([]*SigningRecord, error) {
+	if s.history == nil {
+		return nil, ErrNoSigningHistory
+	}
+	records, err := s.history.Records(since)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history may be shared with other signers.
+	own := records[:0]
+	for _, r := range records {
+		if r.PublicKey.Equal(s.public()) {
+			own = append(own, r)
+		}
+	}
+	return own, nil
+}
+
+func (s *eip712WitnessSigner) public() secp256k1.PublicKey {
+	return secp256k1.PublicKey(*s.privateKey.PubKey())
+}
+
+// NewEIP712WitnessSigner creates a witness signer that produces EIP-712 signatures over
+// operations of the runtime with the given chain context for verification by a contract on an
+// Ethereum-compatible remote chain, using the given secp256k1 private key. Signatures are encoded
+// as r || s || v with v being 27 or 28 and can be verified with RecoverEIP712Signer or the
+// contract's ecrecover.
+//
+// The signing history (see WithSigningHistory) records the hash of the EIP-712 digest as the
+// preimage hash.
+func NewEIP712WitnessSigner(
+	privateKey []byte,
+	chainContext signature.Context,
+	domain EIP712Domain,
+	opts ...WitnessSignerOption,
+) (WitnessSigner, error) {
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	if len(privateKey) != btcec.PrivKeyBytesLen {
+		return nil, fmt.Errorf("bridge: malformed secp256k1 private key: expected %d bytes, got %d", btcec.PrivKeyBytesLen, len(privateKey))
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKey)
+
+	// Witness signer options only configure the signing history and its clock.
+	cfg := witnessSigner{clock: RealClock}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &eip712WitnessSigner{
+		privateKey:   key,
+		domain:       domain,
+		chainContext: chainContext,
+		history:      cfg.history,
+		clock:        cfg.clock,
+	}, nil
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// leftPad pads the given big-endian value to a 32-byte ABI word.
+func leftPad(b []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[32-len(b):], b)
+	return word
+}
+
+func abiUint(n *big.Int) []byte {
+	return leftPad(n.Bytes())
+}
+
+func hexPrefixed(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,146 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// encodingVector is a known encoding of a value that crosses the boundary to the runtime.
+type encodingVector struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+	CBOR string `json:"cbor"`
+}
+
+func loadEncodingVectors(t *testing.T) (addresses, denominations []encodingVector) {
+	raw, err := os.ReadFile("testdata/remote_encoding.json")
+	if err != nil {
+		t.Fatalf("failed to read encoding vectors: %s", err)
+	}
+	var vectors struct {
+		RemoteAddresses     []encodingVector `json:"remote_addresses"`
+		RemoteDenominations []encodingVector `json:"remote_denominations"`
+	}
+	if err = json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatalf("failed to parse encoding vectors: %s", err)
+	}
+	return vectors.RemoteAddresses, vectors.RemoteDenominations
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("malformed hex %q: %s", s, err)
+	}
+	return b
+}
+
+func TestRemoteAddressEncodingVectors(t *testing.T) {
+	addresses, _ := loadEncodingVectors(t)
+	for _, v := range addresses {
+		t.Run(v.Name, func(t *testing.T) {
+			var ra RemoteAddress
+			if err := ra.UnmarshalText([]byte(v.Text)); err != nil {
+				t.Fatalf("failed to unmarshal text: %s", err)
+			}
+			if text, _ := ra.MarshalText(); string(text) != v.Text {
+				t.Fatalf("text encoding mismatch: expected %s, got %s", v.Text, text)
+			}
+
+			expected := mustDecodeHex(t, v.CBOR)
+			if encoded := cbor.Marshal(ra); !bytes.Equal(encoded, expected) {
+				t.Fatalf("CBOR encoding mismatch: expected %x, got %x", expected, encoded)
+			}
+			var decoded RemoteAddress
+			if err := cbor.Unmarshal(expected, &decoded); err != nil {
+				t.Fatalf("failed to unmarshal CBOR: %s", err)
+			}
+			if !decoded.Equal(ra) {
+				t.Fatalf("CBOR decoding mismatch: expected %s, got %s", ra, decoded)
+			}
+		})
+	}
+}
+
+func TestRemoteDenominationEncodingVectors(t *testing.T) {
+	_, denominations := loadEncodingVectors(t)
+	var sawMaxLength bool
+	for _, v := range denominations {
+		t.Run(v.Name, func(t *testing.T) {
+			var rd RemoteDenomination
+			if err := rd.UnmarshalText([]byte(v.Text)); err != nil {
+				t.Fatalf("failed to unmarshal text: %s", err)
+			}
+			if len(rd) > MaxRemoteDenominationLength {
+				t.Fatalf("vector exceeds maximum length: %d", len(rd))
+			}
+			sawMaxLength = sawMaxLength || len(rd) == MaxRemoteDenominationLength
+			if text, _ := rd.MarshalText(); string(text) != v.Text {
+				t.Fatalf("text encoding mismatch: expected %s, got %s", v.Text, text)
+			}
+
+			expected := mustDecodeHex(t, v.CBOR)
+			if encoded := cbor.Marshal(rd); !bytes.Equal(encoded, expected) {
+				t.Fatalf("CBOR encoding mismatch: expected %x, got %x", expected, encoded)
+			}
+			var decoded RemoteDenomination
+			if err := cbor.Unmarshal(expected, &decoded); err != nil {
+				t.Fatalf("failed to unmarshal CBOR: %s", err)
+			}
+			if !bytes.Equal(decoded, rd) {
+				t.Fatalf("CBOR decoding mismatch: expected %s, got %s", rd, decoded)
+			}
+		})
+	}
+	if !sawMaxLength {
+		t.Fatalf("missing maximum length remote denomination vector")
+	}
+}
+
+func TestRemoteDenominationMappingEncoding(t *testing.T) {
+	// The remote denomination of oETH is the same as the local denomination wBTC so that confusing
+	// local and remote denominations would be detected.
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{types.NativeDenomination, "wBTC"},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			"oETH": RemoteDenomination("wBTC"),
+		},
+	}
+
+	// Keys and values are untagged byte strings: {h'6f455448': h'77425443'}.
+	expected := mustDecodeHex(t, "a1446f4554484477425443")
+	if encoded := cbor.Marshal(params.RemoteDenominations); !bytes.Equal(encoded, expected) {
+		t.Fatalf("CBOR encoding mismatch: expected %x, got %x", expected, encoded)
+	}
+
+	var decoded Parameters
+	if err := cbor.Unmarshal(cbor.Marshal(params), &decoded); err != nil {
+		t.Fatalf("failed to unmarshal parameters: %s", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Fatalf("decoded parameters are not valid: %s", err)
+	}
+	if len(decoded.RemoteDenominations) != 1 {
+		t.Fatalf("unexpected remote denominations: %v", decoded.RemoteDenominations)
+	}
+	if remote, ok := decoded.ResolveDenomination("oETH"); !ok || !bytes.Equal(remote, []byte("wBTC")) {
+		t.Fatalf("expected oETH to resolve to remote denomination %x, got %s (ok: %t)", "wBTC", remote, ok)
+	}
+	// The remote denomination must not be taken for the local denomination with the same bytes.
+	if remote, ok := decoded.ResolveDenomination("wBTC"); !ok || remote != nil {
+		t.Fatalf("expected wBTC to resolve as a local denomination, got %s (ok: %t)", remote, ok)
+	}
+
+	// Remote denominations are never decoded from text strings.
+	var mapping map[types.Denomination]RemoteDenomination
+	if err := cbor.Unmarshal(mustDecodeHex(t, "a1446f4554486477425443"), &mapping); err == nil {
+		t.Fatalf("decoded a text string as a remote denomination: %v", mapping)
+	}
+}
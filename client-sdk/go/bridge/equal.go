@@ -0,0 +1,104 @@
+package bridge
+
+import (
+	"bytes"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// baseUnitsEqual compares the given amounts by value.
+func baseUnitsEqual(a, b *types.BaseUnits) bool {
+	return a.Denomination == b.Denomination && a.Amount.Cmp(&b.Amount) == 0
+}
+
+// Equal compares vs another lock event for equality.
+func (e *LockEvent) Equal(other *LockEvent) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	return e.ID == other.ID &&
+		e.Owner == other.Owner &&
+		e.Target.Equal(other.Target) &&
+		baseUnitsEqual(&e.Amount, &other.Amount)
+}
+
+// Hash returns the hash of the canonical encoding of the lock event.
+//
+// Events that are equal have the same hash so it can be used as a map key.
+func (e *LockEvent) Hash() hash.Hash {
+	return hash.NewFrom(e)
+}
+
+// Equal compares vs another release event for equality.
+func (e *ReleaseEvent) Equal(other *ReleaseEvent) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	return e.ID == other.ID &&
+		e.Target == other.Target &&
+		baseUnitsEqual(&e.Amount, &other.Amount)
+}
+
+// Hash returns the hash of the canonical encoding of the release event.
+//
+// Events that are equal have the same hash so it can be used as a map key.
+func (e *ReleaseEvent) Hash() hash.Hash {
+	return hash.NewFrom(e)
+}
+
+// Equal compares vs another operation for equality.
+func (op *Operation) Equal(other *Operation) bool {
+	if op == nil || other == nil {
+		return op == other
+	}
+
+	switch {
+	case (op.Lock == nil) != (other.Lock == nil):
+		return false
+	case op.Lock != nil && !(op.Lock.Target.Equal(other.Lock.Target) && baseUnitsEqual(&op.Lock.Amount, &other.Lock.Amount)):
+		return false
+	}
+
+	switch {
+	case (op.Release == nil) != (other.Release == nil):
+		return false
+	case op.Release != nil:
+		return op.Release.ID == other.Release.ID &&
+			op.Release.Target == other.Release.Target &&
+			baseUnitsEqual(&op.Release.Amount, &other.Release.Amount)
+	}
+	return true
+}
+
+// Equal compares vs another witnesses signed event for equality.
+func (e *WitnessesSignedEvent) Equal(other *WitnessesSignedEvent) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	if e.ID != other.ID || !e.Op.Equal(&other.Op) {
+		return false
+	}
+	if len(e.Witnesses) != len(other.Witnesses) || len(e.Signatures) != len(other.Signatures) {
+		return false
+	}
+	for i := range e.Witnesses {
+		if e.Witnesses[i] != other.Witnesses[i] {
+			return false
+		}
+	}
+	for i := range e.Signatures {
+		if !bytes.Equal(e.Signatures[i], other.Signatures[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the hash of the canonical encoding of the witnesses signed event.
+//
+// Events that are equal have the same hash so it can be used as a map key.
+func (e *WitnessesSignedEvent) Hash() hash.Hash {
+	return hash.NewFrom(e)
+}
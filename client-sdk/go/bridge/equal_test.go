@@ -0,0 +1,81 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestLockEventEqual(t *testing.T) {
+	a := &LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: RemoteAddress{1, 2, 3},
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	}
+
+	// Round-trip through CBOR so that the quantities do not share their internal representation.
+	var b LockEvent
+	if err := cbor.Unmarshal(cbor.Marshal(a), &b); err != nil {
+		t.Fatalf("failed to unmarshal lock event: %s", err)
+	}
+	if !a.Equal(&b) {
+		t.Fatalf("expected lock events to be equal")
+	}
+	if a.Hash() != b.Hash() {
+		t.Fatalf("expected equal lock events to have the same hash")
+	}
+
+	// Quantities are compared by value.
+	var amount quantity.Quantity
+	if err := amount.FromBigInt(big.NewInt(10)); err != nil {
+		t.Fatalf("failed to create quantity: %s", err)
+	}
+	b.Amount.Amount = amount
+	if !a.Equal(&b) {
+		t.Fatalf("expected lock events with the same amount to be equal")
+	}
+
+	b.Amount = types.NewBaseUnits(*quantity.NewFromUint64(11), types.NativeDenomination)
+	if a.Equal(&b) {
+		t.Fatalf("expected lock events with different amounts to differ")
+	}
+	if a.Hash() == b.Hash() {
+		t.Fatalf("expected different lock events to have different hashes")
+	}
+}
+
+func TestWitnessesSignedEventEqual(t *testing.T) {
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination)
+	a := &WitnessesSignedEvent{
+		ID:         1,
+		Op:         Operation{Lock: &Lock{Target: RemoteAddress{1}, Amount: amount}},
+		Witnesses:  []uint16{0, 2},
+		Signatures: [][]byte{{1}, {2}},
+	}
+	b := &WitnessesSignedEvent{
+		ID:         1,
+		Op:         Operation{Lock: &Lock{Target: RemoteAddress{1}, Amount: amount}},
+		Witnesses:  []uint16{0, 2},
+		Signatures: [][]byte{{1}, {2}},
+	}
+	if !a.Equal(b) || a.Hash() != b.Hash() {
+		t.Fatalf("expected witnesses signed events to be equal")
+	}
+
+	b.Op = Operation{Release: &Release{ID: 1, Target: sdkTesting.Alice.Address, Amount: amount}}
+	if a.Equal(b) {
+		t.Fatalf("expected witnesses signed events with different operations to differ")
+	}
+
+	b.Op = a.Op
+	b.Signatures = [][]byte{{1}, {3}}
+	if a.Equal(b) {
+		t.Fatalf("expected witnesses signed events with different signatures to differ")
+	}
+}
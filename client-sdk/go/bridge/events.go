@@ -0,0 +1,273 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	sdk "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// ErrMalformedEvent is the error returned when the value of a bridge module event cannot be
+// decoded.
+var ErrMalformedEvent = errors.New("bridge: malformed event")
+
+var errEmptyEventValue = errors.New("empty value")
+
+// MalformedEventError is the error returned when the value of an event cannot be decoded. It
+// matches ErrMalformedEvent and wraps the underlying decoding error.
+type MalformedEventError struct {
+	// Err is the error encountered while decoding the event value.
+	Err error
+}
+
+// Error implements error.
+func (e *MalformedEventError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrMalformedEvent, e.Err)
+}
+
+// Is returns true iff the target is ErrMalformedEvent.
+func (e *MalformedEventError) Is(target error) bool {
+	return target == ErrMalformedEvent
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *MalformedEventError) Unwrap() error {
+	return e.Err
+}
+
+// Event is a decoded bridge module event.
+//
+// Exactly one of the event fields is set.
+type Event struct {
+	// Round is the runtime round in which the event was emitted.
+	Round uint64 `json:"round"`
+	// Index is the position of the event among the bridge events emitted in the round. Together
+	// with the round it totally orders all bridge events.
+	Index uint32 `json:"index"`
+	// TxHash is the hash of the transaction that emitted the event.
+	TxHash hash.Hash `json:"tx_hash"`
+	// Raw is the raw CBOR-encoded event value, which can be used to inspect fields that are not
+	// known to this client (see AllowUnknownFields).
+	Raw []byte `json:"-"`
+
+	Lock            *LockEvent            `json:"lock,omitempty"`
+	Release         *ReleaseEvent         `json:"release,omitempty"`
+	WitnessesSigned *WitnessesSignedEvent `json:"witnesses_signed,omitempty"`
+}
+
+// Kind returns the kind of the operation the event refers to.
+func (ev *Event) Kind() OperationKind {
+	switch {
+	case ev.Lock != nil:
+		return OperationLock
+	case ev.Release != nil:
+		return OperationRelease
+	case ev.WitnessesSigned != nil:
+		return ev.WitnessesSigned.Kind()
+	default:
+		return OperationUnknown
+	}
+}
+
+// eventKeys are the keys of the events emitted by a bridge module.
+type eventKeys struct {
+	module          string
+	lock            sdk.EventKey
+	release         sdk.EventKey
+	witnessesSigned sdk.EventKey
+}
+
+func newEventKeys(module string) *eventKeys {
+	return &eventKeys{
+		module:          module,
+		lock:            sdk.NewEventKey(module, lockEventCode),
+		release:         sdk.NewEventKey(module, releaseEventCode),
+		witnessesSigned: sdk.NewEventKey(module, witnessesSignedEventCode),
+	}
+}
+
+// tolerantDecMode is the CBOR decoding mode used for event values when unknown fields are
+// allowed. Apart from unknown fields it is as strict as the default decoding mode.
+var tolerantDecMode fxcbor.DecMode
+
+func init() {
+	var err error
+	tolerantDecMode, err = fxcbor.DecOptions{
+		DupMapKey:   fxcbor.DupMapKeyEnforcedAPF,
+		IndefLength: fxcbor.IndefLengthForbidden,
+		TagsMd:      fxcbor.TagsForbidden,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DecodeOption is an option for decoding bridge module events.
+type DecodeOption func(o *decodeOptions)
+
+type decodeOptions struct {
+	allowUnknownFields bool
+}
+
+// AllowUnknownFields configures event decoding to ignore fields that are not known to this client
+// instead of failing (e.g., in case the runtime is newer than the client). The unknown fields can
+// still be inspected via the raw event value (see Event.Raw).
+//
+// By default events with unknown fields are malformed.
+func AllowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.allowUnknownFields = true
+	}
+}
+
+// DecodeEvent decodes a raw runtime event into a bridge module event.
+//
+// Events that are not emitted by the bridge module are ignored and nil is returned without an
+// error. The round and index of the returned event are not set.
+//
+// In case the event value (including an empty one) cannot be decoded, an error wrapping
+// ErrMalformedEvent is returned so that callers can choose to skip such events.
+func DecodeEvent(ev *coreClient.Event, opts ...DecodeOption) (*Event, error) {
+	keys := eventKeys{
+		module:          ModuleName,
+		lock:            LockEventKey,
+		release:         ReleaseEventKey,
+		witnessesSigned: WitnessesSignedEventKey,
+	}
+	return keys.decode(ev, opts...)
+}
+
+// MatchEvent checks whether the given SDK event has the given key and if so decodes its value into
+// out unless it is nil. As it is not specific to bridge events, it can be used to handle the
+// events of several modules in the same loop, e.g.:
+//
+//	transferKey := sdk.NewEventKey("accounts", 1)
+//	var transfer struct {
+//		From   types.Address   `json:"from"`
+//		To     types.Address   `json:"to"`
+//		Amount types.BaseUnits `json:"amount"`
+//	}
+//	if ok, err := bridge.MatchEvent(ev, &transferKey, &transfer); ok && err == nil {
+//		...
+//	}
+//
+// For events with a different key false is returned without an error. In case the value cannot be
+// decoded, true and an error wrapping ErrMalformedEvent are returned.
+func MatchEvent(ev *client.Event, key *sdk.EventKey, out interface{}) (bool, error) {
+	if !key.IsEqual(sdk.NewEventKey(ev.Module, ev.Code)) {
+		return false, nil
+	}
+	return true, decodeEventValue(ev.Value, out)
+}
+
+// MatchRawEvent is like MatchEvent, but for raw runtime events as returned by
+// client.RuntimeClient.GetEvents.
+func MatchRawEvent(ev *coreClient.Event, key *sdk.EventKey, out interface{}) (bool, error) {
+	if !key.IsEqual(ev.Key) {
+		return false, nil
+	}
+	return true, decodeEventValue(ev.Value, out)
+}
+
+func decodeEventValue(value []byte, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	if len(value) == 0 {
+		return &MalformedEventError{Err: errEmptyEventValue}
+	}
+	if err := cbor.Unmarshal(value, out); err != nil {
+		return &MalformedEventError{Err: err}
+	}
+	return nil
+}
+
+func (k *eventKeys) decode(ev *coreClient.Event, opts ...DecodeOption) (*Event, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var (
+		event = Event{TxHash: ev.TxHash, Raw: ev.Value}
+		body  interface{}
+	)
+	switch {
+	case k.lock.IsEqual(ev.Key):
+		event.Lock = new(LockEvent)
+		body = event.Lock
+	case k.release.IsEqual(ev.Key):
+		event.Release = new(ReleaseEvent)
+		body = event.Release
+	case k.witnessesSigned.IsEqual(ev.Key):
+		event.WitnessesSigned = new(WitnessesSignedEvent)
+		body = event.WitnessesSigned
+	default:
+		return nil, nil
+	}
+
+	if len(ev.Value) == 0 {
+		return nil, &MalformedEventError{Err: errEmptyEventValue}
+	}
+	unmarshal := cbor.Unmarshal
+	if o.allowUnknownFields {
+		unmarshal = tolerantDecMode.Unmarshal
+	}
+	if err := unmarshal(ev.Value, body); err != nil {
+		return nil, &MalformedEventError{Err: err}
+	}
+	return &event, nil
+}
+
+// unknown converts a raw runtime event emitted by the module into an SDK event in case its key is
+// not known. Otherwise nil is returned.
+func (k *eventKeys) unknown(ev *coreClient.Event) *client.Event {
+	module := k.module
+	key := []byte(ev.Key)
+	if len(key) != len(module)+4 || !bytes.HasPrefix(key, []byte(module)) {
+		return nil
+	}
+	return &client.Event{
+		Module: module,
+		Code:   binary.BigEndian.Uint32(key[len(module):]),
+		TxHash: ev.TxHash,
+		Value:  ev.Value,
+	}
+}
+
+func (a *v1) logMalformedEvent(round uint64, ev *coreClient.Event, err error) {
+	a.logger.Error("ignoring malformed bridge event",
+		"err", err,
+		"round", round,
+		"key", base64.StdEncoding.EncodeToString(ev.Key),
+		"value", base64.StdEncoding.EncodeToString(ev.Value),
+		"tx_hash", ev.TxHash,
+	)
+}
+
+func (a *v1) logRawEvent(round uint64, ev *coreClient.Event) {
+	a.logger.Debug("got raw event",
+		"round", round,
+		"key", base64.StdEncoding.EncodeToString(ev.Key),
+		"value", base64.StdEncoding.EncodeToString(ev.Value),
+		"tx_hash", ev.TxHash,
+	)
+}
+
+func (a *v1) logUnknownEvent(ev *client.Event) {
+	a.logger.Debug("ignoring unknown bridge event",
+		"module", ev.Module,
+		"code", ev.Code,
+		"tx_hash", ev.TxHash,
+	)
+}
@@ -0,0 +1,269 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	sdk "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestDecodeEventMalformed(t *testing.T) {
+	lock := cbor.Marshal(&LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	})
+	witnessesSigned := cbor.Marshal(&WitnessesSignedEvent{
+		ID:         2,
+		Witnesses:  []uint16{0},
+		Signatures: [][]byte{{1, 2, 3}},
+	})
+
+	for _, tc := range []struct {
+		name  string
+		key   []byte
+		value []byte
+		cause error
+	}{
+		{"NilLock", LockEventKey, nil, errEmptyEventValue},
+		{"EmptyRelease", ReleaseEventKey, []byte{}, errEmptyEventValue},
+		{"TruncatedLock", LockEventKey, lock[:len(lock)/2], io.ErrUnexpectedEOF},
+		{"TruncatedLockByOne", LockEventKey, lock[:len(lock)-1], io.ErrUnexpectedEOF},
+		{"TruncatedWitnessesSigned", WitnessesSignedEventKey, witnessesSigned[:len(witnessesSigned)-2], io.ErrUnexpectedEOF},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ev, err := DecodeEvent(&coreClient.Event{Key: tc.key, Value: tc.value})
+			if !errors.Is(err, ErrMalformedEvent) {
+				t.Fatalf("expected ErrMalformedEvent, got %v", err)
+			}
+			// The decoding error is preserved.
+			var malformed *MalformedEventError
+			if !errors.As(err, &malformed) || !errors.Is(malformed.Err, tc.cause) {
+				t.Fatalf("expected malformed event error caused by %v, got %v", tc.cause, err)
+			}
+			if !errors.Is(err, tc.cause) {
+				t.Fatalf("expected error to wrap %v, got %v", tc.cause, err)
+			}
+			if ev != nil {
+				t.Fatalf("expected no event, got %+v", ev)
+			}
+		})
+	}
+}
+
+func TestDecodeEventWellFormed(t *testing.T) {
+	lock := &LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	}
+	ev, err := DecodeEvent(&coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(lock)})
+	if err != nil {
+		t.Fatalf("failed to decode lock event: %v", err)
+	}
+	if ev.Lock == nil || !ev.Lock.Equal(lock) {
+		t.Fatalf("decoded lock event mismatch: %+v", ev.Lock)
+	}
+
+	// Events of other modules are ignored even if their values are not valid CBOR.
+	ev, err = DecodeEvent(&coreClient.Event{Key: []byte("accounts\x00\x00\x00\x01"), Value: []byte{0xff}})
+	if err != nil || ev != nil {
+		t.Fatalf("expected foreign event to be ignored, got %+v, %v", ev, err)
+	}
+}
+
+func TestDecodeEventUnknownFields(t *testing.T) {
+	// A lock event as emitted by a newer runtime with an additional field.
+	type futureLockEvent struct {
+		LockEvent
+		Memo string `json:"memo"`
+	}
+	lock := LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	}
+	raw := &coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(&futureLockEvent{lock, "hello"})}
+
+	if _, err := DecodeEvent(raw); !errors.Is(err, ErrMalformedEvent) {
+		t.Fatalf("expected ErrMalformedEvent in strict mode, got %v", err)
+	}
+
+	ev, err := DecodeEvent(raw, AllowUnknownFields())
+	if err != nil {
+		t.Fatalf("failed to decode lock event with unknown field: %v", err)
+	}
+	if ev.Lock == nil || !ev.Lock.Equal(&lock) {
+		t.Fatalf("decoded lock event mismatch: %+v", ev.Lock)
+	}
+	var future futureLockEvent
+	if err = cbor.Unmarshal(ev.Raw, &future); err != nil || future.Memo != "hello" {
+		t.Fatalf("failed to inspect unknown field in raw value: %+v (err: %v)", future, err)
+	}
+}
+
+func TestMatchEvent(t *testing.T) {
+	lock := &LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	}
+	transferKey := sdk.NewEventKey("accounts", 1)
+	transfer := cbor.Marshal(map[string]uint64{"amount": 10})
+
+	// SDK events.
+	var decoded LockEvent
+	ok, err := MatchEvent(&client.Event{Module: ModuleName, Code: lockEventCode, Value: cbor.Marshal(lock)}, &LockEventKey, &decoded)
+	if !ok || err != nil || !decoded.Equal(lock) {
+		t.Fatalf("expected lock event to match, got %t, %v, %+v", ok, err, decoded)
+	}
+	ok, err = MatchEvent(&client.Event{Module: "accounts", Code: 1, Value: transfer}, &LockEventKey, &decoded)
+	if ok || err != nil {
+		t.Fatalf("expected accounts event not to match lock event key, got %t, %v", ok, err)
+	}
+	var amount map[string]uint64
+	ok, err = MatchEvent(&client.Event{Module: "accounts", Code: 1, Value: transfer}, &transferKey, &amount)
+	if !ok || err != nil || amount["amount"] != 10 {
+		t.Fatalf("expected accounts event to match, got %t, %v, %+v", ok, err, amount)
+	}
+	ok, err = MatchEvent(&client.Event{Module: "accounts", Code: 1, Value: []byte{0xff}}, &transferKey, &amount)
+	if !ok || !errors.Is(err, ErrMalformedEvent) {
+		t.Fatalf("expected malformed accounts event, got %t, %v", ok, err)
+	}
+
+	// Raw runtime events.
+	ok, err = MatchRawEvent(&coreClient.Event{Key: transferKey, Value: []byte{0xff}}, &transferKey, nil)
+	if !ok || err != nil {
+		t.Fatalf("expected raw accounts event to match without decoding, got %t, %v", ok, err)
+	}
+	ok, err = MatchRawEvent(&coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(lock)}, &transferKey, &amount)
+	if ok || err != nil {
+		t.Fatalf("expected raw lock event not to match, got %t, %v", ok, err)
+	}
+	ok, err = MatchRawEvent(&coreClient.Event{Key: LockEventKey}, &LockEventKey, &decoded)
+	if !ok || !errors.Is(err, ErrMalformedEvent) {
+		t.Fatalf("expected empty raw lock event to be malformed, got %t, %v", ok, err)
+	}
+}
+
+// FuzzDecodeEvent checks that decoding arbitrary event values never panics and that events which
+// cannot be decoded are always reported as malformed. Run it with:
+//
+//	go test -fuzz FuzzDecodeEvent ./bridge
+func FuzzDecodeEvent(f *testing.F) {
+	lock := &LockEvent{
+		ID:     1,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), "oETH"),
+	}
+	release := &ReleaseEvent{
+		ID:     2,
+		Target: sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+	}
+	witnessesSigned := &WitnessesSignedEvent{
+		ID:         3,
+		Witnesses:  []uint16{0, 2},
+		Signatures: [][]byte{make([]byte, WitnessSignatureSize), make([]byte, WitnessSignatureSize)},
+	}
+	for _, seed := range []struct {
+		key   []byte
+		value []byte
+	}{
+		{LockEventKey, cbor.Marshal(lock)},
+		{ReleaseEventKey, cbor.Marshal(release)},
+		{WitnessesSignedEventKey, cbor.Marshal(witnessesSigned)},
+		{LockEventKey, nil},
+		{[]byte("accounts\x00\x00\x00\x01"), []byte{0xff}},
+	} {
+		f.Add(seed.key, seed.value)
+	}
+
+	bridgeKeys := [][]byte{LockEventKey, ReleaseEventKey, WitnessesSignedEventKey}
+	f.Fuzz(func(t *testing.T, key, value []byte) {
+		raw := &coreClient.Event{Key: key, Value: value}
+		for _, opts := range [][]DecodeOption{nil, {AllowUnknownFields()}} {
+			ev, err := DecodeEvent(raw, opts...)
+			if err != nil {
+				if !errors.Is(err, ErrMalformedEvent) {
+					t.Fatalf("expected ErrMalformedEvent, got %v", err)
+				}
+				if ev != nil {
+					t.Fatalf("expected no event with an error, got %+v", ev)
+				}
+				continue
+			}
+
+			var isBridgeKey bool
+			for _, k := range bridgeKeys {
+				isBridgeKey = isBridgeKey || bytes.Equal(k, key)
+			}
+			switch {
+			case !isBridgeKey && ev != nil:
+				t.Fatalf("expected event of another module to be ignored, got %+v", ev)
+			case isBridgeKey && ev == nil:
+				t.Fatalf("expected bridge event to be decoded")
+			case ev != nil:
+				// Decoded events must be usable.
+				_ = ev.Kind()
+				_, _ = json.Marshal(ev)
+			}
+		}
+	})
+}
+
+func TestUnknownEventHandler(t *testing.T) {
+	unknownKey := sdk.NewEventKey(ModuleName, 99)
+	rc := &eventsClient{
+		events: map[uint64][]*coreClient.Event{
+			1: {
+				{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 1})},
+				{Key: unknownKey, Value: cbor.Marshal(map[string]uint64{"id": 2}), TxHash: hash.NewFromBytes([]byte("tx"))},
+				// Events of other modules are not reported.
+				{Key: sdk.NewEventKey("accounts", 99), Value: cbor.Marshal(map[string]uint64{"amount": 10})},
+				// Neither are events whose key only shares the module prefix.
+				{Key: unknownKey[:len(unknownKey)-1], Value: cbor.Marshal(nil)},
+			},
+		},
+	}
+
+	var unknown []*client.Event
+	events, err := NewV1(rc, WithUnknownEventHandler(func(ev *client.Event) {
+		unknown = append(unknown, ev)
+	})).GetEvents(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetEvents: %s", err)
+	}
+	if len(events) != 1 || events[0].Lock == nil {
+		t.Fatalf("expected only the lock event to be decoded, got %+v", events)
+	}
+	if len(unknown) != 1 {
+		t.Fatalf("expected 1 unknown event, got %d", len(unknown))
+	}
+	ev := unknown[0]
+	if ev.Module != ModuleName || ev.Code != 99 || !ev.TxHash.Equal(&rc.events[1][1].TxHash) || !bytes.Equal(ev.Value, rc.events[1][1].Value) {
+		t.Fatalf("unexpected unknown event %+v", ev)
+	}
+
+	// A nil handler disables reporting.
+	if _, err = NewV1(rc, WithUnknownEventHandler(nil)).GetEvents(context.Background(), 1); err != nil {
+		t.Fatalf("GetEvents: %s", err)
+	}
+}
@@ -0,0 +1,385 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrNoNodeAddresses is the error returned when connecting without any node addresses.
+	ErrNoNodeAddresses = errors.New("bridge: no node addresses")
+
+	// ErrChainContextMismatch is the error returned when a node's chain context does not match
+	// the expected one, i.e. the node is part of a different network.
+	ErrChainContextMismatch = errors.New("bridge: chain context mismatch")
+)
+
+// FailoverPolicy configures which node a client connected to several nodes uses (see
+// ConnectMulti).
+type FailoverPolicy uint8
+
+const (
+	// FailoverPrimary uses the first healthy node in the order the addresses were given in, so
+	// that the first node is used whenever it is healthy.
+	FailoverPrimary FailoverPolicy = iota
+	// FailoverRoundRobin spreads requests over all healthy nodes.
+	FailoverRoundRobin
+)
+
+// ConnectOption is an option for connecting to nodes.
+type ConnectOption func(o *connectOptions)
+
+type connectOptions struct {
+	policy    FailoverPolicy
+	logger    *logging.Logger
+	v1Options []V1Option
+
+	expectedChainContext signature.Context
+}
+
+// WithFailoverPolicy configures which node is used when connected to several nodes.
+//
+// By default FailoverPrimary is used.
+func WithFailoverPolicy(policy FailoverPolicy) ConnectOption {
+	return func(o *connectOptions) {
+		o.policy = policy
+	}
+}
+
+// WithConnectLogger configures the logger used to report failovers.
+//
+// By default a logger for the "bridge" module is used.
+func WithConnectLogger(l *logging.Logger) ConnectOption {
+	return func(o *connectOptions) {
+		o.logger = l
+	}
+}
+
+// WithBridgeOptions configures additional options for the bridge module client of the connected
+// client (see NewV1).
+func WithBridgeOptions(opts ...V1Option) ConnectOption {
+	return func(o *connectOptions) {
+		o.v1Options = append(o.v1Options, opts...)
+	}
+}
+
+// WithExpectedChainContext configures the chain context all nodes are expected to have so that
+// connecting to nodes of the wrong network fails with ErrChainContextMismatch.
+//
+// Only the chain context of the primary node is verified when connecting. The chain context of
+// each failover node is verified before the node is first used and a node of the wrong network
+// is failed over like an unavailable one.
+//
+// By default the chain context of the nodes is not verified.
+func WithExpectedChainContext(ctx signature.Context) ConnectOption {
+	return func(o *connectOptions) {
+		o.expectedChainContext = ctx
+	}
+}
+
+// VerifyChainContext verifies that the chain context of the runtime the given client is connected
+// to matches the expected one, returning ErrChainContextMismatch otherwise.
+func VerifyChainContext(ctx context.Context, rc client.RuntimeClient, expected signature.Context) error {
+	info, err := rc.GetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to fetch runtime info: %w", err)
+	}
+	if info.ChainContext != expected {
+		return fmt.Errorf("%w: node has '%s', expected '%s'", ErrChainContextMismatch, info.ChainContext, expected)
+	}
+	return nil
+}
+
+// failoverNode is a node a failover client is connected to.
+type failoverNode struct {
+	addr  string
+	state func() connectivity.State
+	rc    client.RuntimeClient
+
+	// expectedChainContext is the chain context the node must have, if any.
+	expectedChainContext signature.Context
+
+	lock     sync.Mutex
+	verified bool
+}
+
+// verify verifies the chain context of the node unless it has already been verified.
+func (n *failoverNode) verify(ctx context.Context) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.verified || n.expectedChainContext == "" {
+		return nil
+	}
+	if err := VerifyChainContext(ctx, n.rc, n.expectedChainContext); err != nil {
+		return err
+	}
+	n.verified = true
+	return nil
+}
+
+// healthy returns true iff the connection to the node is usable or has not been used yet.
+func (n *failoverNode) healthy() bool {
+	switch n.state() {
+	case connectivity.Ready, connectivity.Idle, connectivity.Connecting:
+		return true
+	default:
+		return false
+	}
+}
+
+// failoverClient is a runtime client backed by several nodes.
+//
+// Requests are sent to a node selected according to the failover policy, preferring nodes whose
+// connection is healthy. In case a read request fails as the node is unavailable, it is retried
+// with the next node. Transaction submissions are never retried (see submit).
+type failoverClient struct {
+	nodes  []*failoverNode
+	policy FailoverPolicy
+	logger *logging.Logger
+
+	lock sync.Mutex
+	next int
+}
+
+// candidates returns the nodes in the order they should be tried in.
+func (f *failoverClient) candidates() []*failoverNode {
+	start := 0
+	if f.policy == FailoverRoundRobin {
+		f.lock.Lock()
+		start = f.next
+		f.next = (f.next + 1) % len(f.nodes)
+		f.lock.Unlock()
+	}
+
+	var healthy, unhealthy []*failoverNode
+	for i := range f.nodes {
+		n := f.nodes[(start+i)%len(f.nodes)]
+		if n.healthy() {
+			healthy = append(healthy, n)
+		} else {
+			unhealthy = append(unhealthy, n)
+		}
+	}
+	// Unhealthy nodes are still tried as a last resort as they may have recovered.
+	return append(healthy, unhealthy...)
+}
+
+// isUnavailable returns true iff the given error indicates that the node could not be reached.
+func isUnavailable(err error) bool {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.GRPCStatus().Code() == codes.Unavailable
+}
+
+// do invokes fn with the runtime client of each candidate node in turn until it does not fail
+// due to the node being unavailable or part of a different network.
+func (f *failoverClient) do(ctx context.Context, fn func(rc client.RuntimeClient) error) error {
+	var err error
+	for i, n := range f.candidates() {
+		if err = n.verify(ctx); err == nil {
+			err = fn(n.rc)
+		}
+		if err == nil || ctx.Err() != nil || (!isUnavailable(err) && !errors.Is(err, ErrChainContextMismatch)) {
+			return err
+		}
+		f.logger.Warn("node unavailable, failing over",
+			"err", err,
+			"addr", n.addr,
+			"attempt", i+1,
+		)
+	}
+	return err
+}
+
+// submit invokes fn with the runtime client of the first candidate node whose chain context could
+// be verified.
+//
+// Unlike with do, a failed fn is not retried with the next node as the transaction may have
+// reached the node before it became unavailable, so the original error is returned instead.
+func (f *failoverClient) submit(ctx context.Context, fn func(rc client.RuntimeClient) error) error {
+	var err error
+	for i, n := range f.candidates() {
+		if err = n.verify(ctx); err == nil {
+			return fn(n.rc)
+		}
+		if ctx.Err() != nil || (!isUnavailable(err) && !errors.Is(err, ErrChainContextMismatch)) {
+			return err
+		}
+		f.logger.Warn("node unavailable, failing over",
+			"err", err,
+			"addr", n.addr,
+			"attempt", i+1,
+		)
+	}
+	return err
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) GetInfo(ctx context.Context) (info *types.RuntimeInfo, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		info, err = rc.GetInfo(ctx)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (rsp cbor.RawMessage, err error) {
+	err = f.submit(ctx, func(rc client.RuntimeClient) (err error) {
+		rsp, err = rc.SubmitTx(ctx, tx)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
+	return f.submit(ctx, func(rc client.RuntimeClient) error {
+		return rc.SubmitTxNoWait(ctx, tx)
+	})
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) GetGenesisBlock(ctx context.Context) (blk *block.Block, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		blk, err = rc.GetGenesisBlock(ctx)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) GetBlock(ctx context.Context, round uint64) (blk *block.Block, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		blk, err = rc.GetBlock(ctx, round)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) GetTransactions(ctx context.Context, round uint64) (txs []*types.UnverifiedTransaction, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		txs, err = rc.GetTransactions(ctx, round)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) GetEvents(ctx context.Context, round uint64) (evs []*coreClient.Event, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		evs, err = rc.GetEvents(ctx, round)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+//
+// The subscription is established with the first healthy node. In case it drops, watch helpers
+// (see V1.WatchBlocks) resubscribe which fails over to another node if needed.
+func (f *failoverClient) WatchBlocks(ctx context.Context) (ch <-chan *roothash.AnnotatedBlock, sub pubsub.ClosableSubscription, err error) {
+	err = f.do(ctx, func(rc client.RuntimeClient) (err error) {
+		ch, sub, err = rc.WatchBlocks(ctx)
+		return
+	})
+	return
+}
+
+// Implements client.RuntimeClient.
+func (f *failoverClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	return f.do(ctx, func(rc client.RuntimeClient) error {
+		return rc.Query(ctx, round, method, args, rsp)
+	})
+}
+
+// ConnectMulti establishes gRPC connections with the nodes at the given addresses and returns a
+// client for the bridge runtime with the given identifier that fails over between the nodes.
+//
+// Each request is sent to a node selected by the failover policy (see WithFailoverPolicy) among
+// the nodes whose connection is healthy. Read requests are retried with the next node in case the
+// node is unavailable while transaction submissions return the error of the selected node, as
+// the transaction may still have been received. With a single address the client behaves as one returned by Connect.
+//
+// The first address is the one of the primary node. If an expected chain context is configured
+// (see WithExpectedChainContext), it is verified with the primary node before returning, bounded
+// by the given context, so that only the primary node needs to be reachable when connecting.
+//
+// The bridge client is configured with the consensus staking backend of the first node.
+func ConnectMulti(ctx context.Context, addrs []string, runtimeID common.Namespace, opts ...ConnectOption) (*Client, error) {
+	if len(addrs) == 0 {
+		return nil, ErrNoNodeAddresses
+	}
+	o := connectOptions{
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	f := &failoverClient{
+		policy: o.policy,
+		logger: o.logger,
+	}
+	var conns []*grpc.ClientConn
+	for _, addr := range addrs {
+		conn, err := cmnGrpc.Dial(addr, grpc.WithInsecure())
+		if err != nil {
+			for _, c := range conns {
+				c.Close()
+			}
+			return nil, fmt.Errorf("bridge: failed to establish connection with %s: %w", addr, err)
+		}
+		conns = append(conns, conn)
+		f.nodes = append(f.nodes, &failoverNode{
+			addr:                 addr,
+			state:                conn.GetState,
+			rc:                   client.New(conn, runtimeID),
+			expectedChainContext: o.expectedChainContext,
+		})
+	}
+
+	if err := f.nodes[0].verify(ctx); err != nil {
+		for _, c := range conns {
+			c.Close()
+		}
+		return nil, fmt.Errorf("%s: %w", f.nodes[0].addr, err)
+	}
+
+	var rc client.RuntimeClient = f
+	if len(f.nodes) == 1 {
+		rc = f.nodes[0].rc
+	}
+	return &Client{
+		RuntimeClient: rc,
+		Accounts:      accounts.NewV1(rc),
+		Bridge:        NewV1(rc, append([]V1Option{WithConsensusStaking(staking.NewStakingClient(conns[0]))}, o.v1Options...)...),
+		conns:         conns,
+	}, nil
+}
@@ -0,0 +1,342 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// nodeClient is a runtime client of a single node that counts block requests and transaction
+// submissions and fails them with the configured error.
+type nodeClient struct {
+	client.RuntimeClient
+
+	round        uint64
+	chainContext signature.Context
+	err          error
+	calls        int
+	infoCalls    int
+	submits      int
+}
+
+func (rc *nodeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	rc.infoCalls++
+	if rc.err != nil {
+		return nil, rc.err
+	}
+	return &types.RuntimeInfo{ChainContext: rc.chainContext}, nil
+}
+
+func (rc *nodeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	rc.calls++
+	if rc.err != nil {
+		return nil, rc.err
+	}
+	var blk block.Block
+	blk.Header.Round = rc.round
+	return &blk, nil
+}
+
+func (rc *nodeClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	rc.submits++
+	if rc.err != nil {
+		return nil, rc.err
+	}
+	return cbor.Marshal(rc.round), nil
+}
+
+func (rc *nodeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
+	rc.submits++
+	return rc.err
+}
+
+func newTestFailoverClient(policy FailoverPolicy, states []connectivity.State, rcs []*nodeClient) *failoverClient {
+	f := &failoverClient{policy: policy, logger: logger}
+	for i := range rcs {
+		state := states[i]
+		f.nodes = append(f.nodes, &failoverNode{
+			addr:  "node",
+			state: func() connectivity.State { return state },
+			rc:    rcs[i],
+		})
+	}
+	return f
+}
+
+func TestFailoverPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := &nodeClient{round: 1}, &nodeClient{round: 2}
+	f := newTestFailoverClient(FailoverPrimary,
+		[]connectivity.State{connectivity.Ready, connectivity.Ready},
+		[]*nodeClient{primary, secondary},
+	)
+
+	for i := 0; i < 3; i++ {
+		if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 1 {
+			t.Fatalf("expected primary to be used, got %+v (err: %v)", blk, err)
+		}
+	}
+
+	// Unavailable nodes are failed over.
+	primary.err = status.Error(codes.Unavailable, "connection refused")
+	if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 2 {
+		t.Fatalf("expected failover to secondary, got %+v (err: %v)", blk, err)
+	}
+
+	// Other errors are returned as is.
+	primary.err = errors.New("no such block")
+	if _, err := f.GetBlock(ctx, 0); err != primary.err {
+		t.Fatalf("expected primary error, got %v", err)
+	}
+	if secondary.calls != 1 {
+		t.Fatalf("expected secondary to be used once, got %d", secondary.calls)
+	}
+
+	// Unhealthy nodes are only tried last.
+	primary.err = nil
+	f.nodes[0].state = func() connectivity.State { return connectivity.TransientFailure }
+	if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 2 {
+		t.Fatalf("expected unhealthy primary to be skipped, got %+v (err: %v)", blk, err)
+	}
+
+	secondary.err = status.Error(codes.Unavailable, "connection refused")
+	if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 1 {
+		t.Fatalf("expected unhealthy primary to be tried last, got %+v (err: %v)", blk, err)
+	}
+}
+
+func TestFailoverRoundRobin(t *testing.T) {
+	rcs := []*nodeClient{{round: 1}, {round: 2}, {round: 3}}
+	f := newTestFailoverClient(FailoverRoundRobin,
+		[]connectivity.State{connectivity.Ready, connectivity.Ready, connectivity.Ready},
+		rcs,
+	)
+	for i := 0; i < 6; i++ {
+		if _, err := f.GetBlock(context.Background(), 0); err != nil {
+			t.Fatalf("failed to get block: %s", err)
+		}
+	}
+	for i, rc := range rcs {
+		if rc.calls != 2 {
+			t.Fatalf("expected node %d to be used twice, got %d", i, rc.calls)
+		}
+	}
+}
+
+func TestFailoverChainContext(t *testing.T) {
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	primary := &nodeClient{round: 1, chainContext: "mainnet", err: unavailable}
+	other := &nodeClient{round: 2, chainContext: "testnet"}
+	secondary := &nodeClient{round: 3, chainContext: "mainnet", err: unavailable}
+	f := newTestFailoverClient(FailoverPrimary,
+		[]connectivity.State{connectivity.Ready, connectivity.Ready, connectivity.Ready},
+		[]*nodeClient{primary, other, secondary},
+	)
+	for _, n := range f.nodes {
+		n.expectedChainContext = "mainnet"
+	}
+
+	// Nodes of a different network are never used.
+	if _, err := f.GetBlock(ctx, 0); !isUnavailable(err) {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+	if other.calls != 0 {
+		t.Fatalf("expected the node of a different network not to be used, got %d calls", other.calls)
+	}
+
+	// Nodes unavailable when connecting are verified once they recover.
+	secondary.err = nil
+	if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 3 {
+		t.Fatalf("expected failover to secondary, got %+v (err: %v)", blk, err)
+	}
+	primary.err = nil
+	for i := 0; i < 3; i++ {
+		if blk, err := f.GetBlock(ctx, 0); err != nil || blk.Header.Round != 1 {
+			t.Fatalf("expected recovered primary to be used, got %+v (err: %v)", blk, err)
+		}
+	}
+	if primary.infoCalls != 3 {
+		t.Fatalf("expected primary to be verified only until it succeeded, got %d attempts", primary.infoCalls)
+	}
+	if other.calls != 0 {
+		t.Fatalf("expected the node of a different network not to be used, got %d calls", other.calls)
+	}
+}
+
+func TestFailoverSubmit(t *testing.T) {
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	primary, secondary := &nodeClient{round: 1}, &nodeClient{round: 2}
+	f := newTestFailoverClient(FailoverPrimary,
+		[]connectivity.State{connectivity.Ready, connectivity.Ready},
+		[]*nodeClient{primary, secondary},
+	)
+
+	rsp, err := f.SubmitTx(ctx, &types.UnverifiedTransaction{})
+	if err != nil {
+		t.Fatalf("SubmitTx: %s", err)
+	}
+	var round uint64
+	if err = cbor.Unmarshal(rsp, &round); err != nil || round != 1 {
+		t.Fatalf("expected primary to be used, got %d (err: %v)", round, err)
+	}
+
+	// Submissions are not resent to another node when the node becomes unavailable.
+	primary.err = unavailable
+	if _, err = f.SubmitTx(ctx, &types.UnverifiedTransaction{}); err != unavailable {
+		t.Fatalf("expected primary error, got %v", err)
+	}
+	if err = f.SubmitTxNoWait(ctx, &types.UnverifiedTransaction{}); err != unavailable {
+		t.Fatalf("expected primary error, got %v", err)
+	}
+	if primary.submits != 3 || secondary.submits != 0 {
+		t.Fatalf("expected submissions not to be retried, got %d primary and %d secondary submissions",
+			primary.submits, secondary.submits)
+	}
+
+	// Nodes that cannot be verified are skipped before submitting.
+	for _, n := range f.nodes {
+		n.expectedChainContext = "mainnet"
+	}
+	secondary.chainContext = "mainnet"
+	if err = f.SubmitTxNoWait(ctx, &types.UnverifiedTransaction{}); err != nil {
+		t.Fatalf("SubmitTxNoWait: %s", err)
+	}
+	if primary.submits != 3 || secondary.submits != 1 {
+		t.Fatalf("expected unverified primary to be skipped, got %d primary and %d secondary submissions",
+			primary.submits, secondary.submits)
+	}
+}
+
+// infoClient is a runtime client reporting the configured chain context.
+type infoClient struct {
+	client.RuntimeClient
+
+	chainContext signature.Context
+}
+
+func (rc *infoClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: rc.chainContext}, nil
+}
+
+func TestVerifyChainContext(t *testing.T) {
+	ctx := context.Background()
+	rc := &infoClient{chainContext: "mainnet"}
+
+	if err := VerifyChainContext(ctx, rc, "mainnet"); err != nil {
+		t.Fatalf("VerifyChainContext: %v", err)
+	}
+	err := VerifyChainContext(ctx, rc, "testnet")
+	if !errors.Is(err, ErrChainContextMismatch) {
+		t.Fatalf("expected ErrChainContextMismatch, got %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "mainnet") || !strings.Contains(msg, "testnet") {
+		t.Fatalf("expected the error to name both chain contexts, got '%s'", msg)
+	}
+}
+
+// chainContextBackend is a consensus backend only serving the consensus chain context.
+type chainContextBackend struct {
+	consensus.ClientBackend
+
+	chainContext string
+}
+
+func (b *chainContextBackend) GetChainContext(ctx context.Context) (string, error) {
+	return b.chainContext, nil
+}
+
+// serveNode serves the given consensus chain context over gRPC and returns the node address.
+func serveNode(t *testing.T, chainContext string) string {
+	path := filepath.Join(t.TempDir(), "node.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	srv := grpc.NewServer(grpc.ForceServerCodec(&cmnGrpc.CBORCodec{}))
+	consensus.RegisterService(srv, &chainContextBackend{chainContext: chainContext})
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+	return "unix:" + path
+}
+
+// serveHangingNode accepts connections without ever responding and returns the node address.
+func serveHangingNode(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "node.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	t.Cleanup(func() {
+		l.Close()
+		<-done
+	})
+	return "unix:" + path
+}
+
+func TestConnectMulti(t *testing.T) {
+	var runtimeID common.Namespace
+	expected := signature.DeriveChainContext(runtimeID, "mainnet")
+	primary, hanging := serveNode(t, "mainnet"), serveHangingNode(t)
+
+	// Failover nodes need not be reachable when connecting.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := ConnectMulti(ctx, []string{primary, hanging}, runtimeID, WithExpectedChainContext(expected))
+	if err != nil {
+		t.Fatalf("ConnectMulti: %s", err)
+	}
+	c.Close()
+
+	// The primary node must be of the expected network.
+	_, err = ConnectMulti(ctx, []string{primary}, runtimeID,
+		WithExpectedChainContext(signature.DeriveChainContext(runtimeID, "testnet")),
+	)
+	if !errors.Is(err, ErrChainContextMismatch) {
+		t.Fatalf("expected ErrChainContextMismatch, got %v", err)
+	}
+
+	// Waiting for the primary node is bounded by the context.
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err = ConnectMulti(ctx, []string{hanging, primary}, runtimeID, WithExpectedChainContext(expected)); err == nil {
+		t.Fatalf("expected connecting to a hanging primary node to fail")
+	}
+}
@@ -0,0 +1,112 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrFeePayerNotSupported is the error returned when a transaction fee is to be paid by an
+// account other than the transaction signer which is not yet supported by the runtime.
+var ErrFeePayerNotSupported = errors.New("bridge: separate fee payer is not supported by the runtime")
+
+// ErrUnsupportedFeeDenomination is the error returned when a transaction fee is to be paid in a
+// denomination that is not accepted by the runtime.
+var ErrUnsupportedFeeDenomination = errors.New("bridge: unsupported fee denomination")
+
+// WithGasPrice configures the price per unit of gas used when estimating transaction fees.
+//
+// Passing the option several times configures gas prices in several denominations, the last one
+// being used unless another fee denomination is selected (see WithFeeDenomination). By default the
+// gas price is zero in the native denomination.
+func WithGasPrice(price types.BaseUnits) V1Option {
+	return func(a *v1) {
+		a.gasPrice = price
+		a.gasPrices[price.Denomination] = price
+	}
+}
+
+// WithFee configures the fee attached to the transaction, skipping fee estimation.
+func WithFee(fee *types.Fee) SubmitOption {
+	return func(o *submitOptions) {
+		o.fee = fee
+	}
+}
+
+// WithFeePayer configures the account paying the transaction fee on behalf of the signer (e.g.,
+// a relayer subsidizing the locks of its users).
+//
+// By default the signer pays its own fees. The runtime currently always charges the fee to the
+// transaction signer, so submission fails with ErrFeePayerNotSupported unless the fee payer is
+// the signer itself.
+func WithFeePayer(payer signature.Signer) SubmitOption {
+	return func(o *submitOptions) {
+		o.feePayer = payer
+	}
+}
+
+// WithFeeDenomination configures the denomination in which the transaction fee is paid, e.g. by
+// relayers only holding bridged tokens. The fee is estimated using the gas price configured for
+// the denomination (see WithGasPrice), or a zero gas price in case none is configured.
+//
+// The runtime charges fees to the balance of the signer in the fee denomination, which can only
+// be held in the native denomination or in a denomination supported by the bridge. Submission
+// fails with ErrUnsupportedFeeDenomination for other denominations. The option is ignored in case
+// the fee is configured via WithFee. By default the denomination of the configured gas price is
+// used, i.e. the native denomination unless configured otherwise.
+func WithFeeDenomination(d types.Denomination) SubmitOption {
+	return func(o *submitOptions) {
+		o.feeDenomination = &d
+	}
+}
+
+// validateFeeDenomination checks that the runtime accepts fees in the given denomination.
+func (a *v1) validateFeeDenomination(ctx context.Context, d types.Denomination) error {
+	if d.IsNative() {
+		return nil
+	}
+	params, err := a.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("failed to query parameters: %w", err)
+	}
+	if _, ok := params.ResolveDenomination(d); !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFeeDenomination, d)
+	}
+	return nil
+}
+
+// Implements V1.
+func (a *v1) EstimateFee(ctx context.Context, tx *types.Transaction) (*types.Fee, error) {
+	return a.estimateFee(ctx, tx, a.gasPrice)
+}
+
+// estimateFeeIn estimates the fee required for the given transaction in the given denomination.
+func (a *v1) estimateFeeIn(ctx context.Context, tx *types.Transaction, d types.Denomination) (*types.Fee, error) {
+	price, ok := a.gasPrices[d]
+	if !ok {
+		price = types.NewBaseUnits(*quantity.NewFromUint64(0), d)
+	}
+	return a.estimateFee(ctx, tx, price)
+}
+
+func (a *v1) estimateFee(ctx context.Context, tx *types.Transaction, price types.BaseUnits) (*types.Fee, error) {
+	gas, err := a.core.EstimateGas(ctx, client.RoundLatest, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	amount := price.Amount.Clone()
+	if err = amount.Mul(quantity.NewFromUint64(gas)); err != nil {
+		return nil, fmt.Errorf("failed to compute fee amount: %w", err)
+	}
+	return &types.Fee{
+		Amount: types.NewBaseUnits(*amount, price.Denomination),
+		Gas:    gas,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// feeClient is a runtime client estimating 100 units of gas for every transaction (or failing
+// with the configured error) and recording the number of estimations and the fees of submitted
+// transactions.
+type feeClient struct {
+	client.RuntimeClient
+
+	params    *Parameters
+	gasErr    error
+	estimates int
+	fees      []types.Fee
+}
+
+func (rc *feeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *feeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = *rc.params
+	case "accounts.Nonce":
+		*rsp.(*uint64) = uint64(len(rc.fees))
+	case "core.EstimateGas":
+		rc.estimates++
+		if rc.gasErr != nil {
+			return rc.gasErr
+		}
+		*rsp.(*uint64) = 100
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func (rc *feeClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	var tx types.Transaction
+	if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+		return nil, err
+	}
+	rc.fees = append(rc.fees, tx.AuthInfo.Fee)
+	return cbor.Marshal(nil), nil
+}
+
+func TestFeeDenomination(t *testing.T) {
+	ctx := context.Background()
+	rc := &feeClient{
+		params: &Parameters{
+			LocalDenominations: []types.Denomination{types.NativeDenomination},
+			RemoteDenominations: map[types.Denomination]RemoteDenomination{
+				"oETH": RemoteDenomination("ETH"),
+				"oBTC": RemoteDenomination("BTC"),
+			},
+		},
+	}
+	v := NewV1(rc,
+		WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(3), "oETH")),
+		WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(2), types.NativeDenomination)),
+	)
+	body := &Witness{ID: 1, Signature: make([]byte, WitnessSignatureSize)}
+
+	for _, tc := range []struct {
+		name         string
+		opts         []SubmitOption
+		amount       uint64
+		denomination types.Denomination
+	}{
+		{"Default", nil, 200, types.NativeDenomination},
+		{"Native", []SubmitOption{WithFeeDenomination(types.NativeDenomination)}, 200, types.NativeDenomination},
+		{"NonNative", []SubmitOption{WithFeeDenomination("oETH")}, 300, "oETH"},
+		{"NonNativeWithoutGasPrice", []SubmitOption{WithFeeDenomination("oBTC")}, 0, "oBTC"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := v.SubmitWitness(ctx, sdkTesting.Alice.Signer, body, tc.opts...); err != nil {
+				t.Fatalf("SubmitWitness: %v", err)
+			}
+			fee := rc.fees[len(rc.fees)-1]
+			expected := types.NewBaseUnits(*quantity.NewFromUint64(tc.amount), tc.denomination)
+			if fee.Gas != 100 || !baseUnitsEqual(&fee.Amount, &expected) {
+				t.Fatalf("expected fee of %s for 100 gas, got %s for %d gas", expected, fee.Amount, fee.Gas)
+			}
+		})
+	}
+
+	submitted := len(rc.fees)
+	err := v.SubmitWitness(ctx, sdkTesting.Alice.Signer, body, WithFeeDenomination("FOO"))
+	if !errors.Is(err, ErrUnsupportedFeeDenomination) {
+		t.Fatalf("expected ErrUnsupportedFeeDenomination, got %v", err)
+	}
+	if len(rc.fees) != submitted {
+		t.Fatalf("expected no transaction to be submitted with an unsupported fee denomination")
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	ctx := context.Background()
+	tx := types.NewTransaction(nil, ModuleName+"."+methodWitness, &Witness{ID: 1})
+	tx.AppendAuthSignature(sdkTesting.Alice.Signer.Public(), 0)
+
+	for _, tc := range []struct {
+		name     string
+		opts     []V1Option
+		expected types.BaseUnits
+	}{
+		{"Default", nil, types.NewBaseUnits(*quantity.NewFromUint64(0), types.NativeDenomination)},
+		{"GasPrice", []V1Option{WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(2), types.NativeDenomination))}, types.NewBaseUnits(*quantity.NewFromUint64(200), types.NativeDenomination)},
+		{"GasPriceNonNative", []V1Option{WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(3), "oETH"))}, types.NewBaseUnits(*quantity.NewFromUint64(300), "oETH")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := NewV1(&feeClient{}, tc.opts...).EstimateFee(ctx, tx)
+			if err != nil {
+				t.Fatalf("EstimateFee: %s", err)
+			}
+			if fee.Gas != 100 || !baseUnitsEqual(&fee.Amount, &tc.expected) {
+				t.Fatalf("expected fee of %s for 100 gas, got %s for %d gas", tc.expected, fee.Amount, fee.Gas)
+			}
+		})
+	}
+
+	// Gas estimation failures are returned and prevent submission.
+	errUnavailable := errors.New("node unavailable")
+	rc := &feeClient{gasErr: errUnavailable}
+	v := NewV1(rc)
+	if _, err := v.EstimateFee(ctx, tx); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected gas estimation error, got %v", err)
+	}
+	body := &Witness{ID: 1, Signature: make([]byte, WitnessSignatureSize)}
+	if err := v.SubmitWitness(ctx, sdkTesting.Alice.Signer, body); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected gas estimation error, got %v", err)
+	}
+	if len(rc.fees) != 0 {
+		t.Fatalf("expected no transaction to be submitted without a fee")
+	}
+
+	// A manually configured fee is attached as is without estimating gas, taking precedence over
+	// the fee denomination.
+	rc = &feeClient{}
+	v = NewV1(rc, WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(2), types.NativeDenomination)))
+	fee := &types.Fee{
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(5), types.NativeDenomination),
+		Gas:    50,
+	}
+	if err := v.SubmitWitness(ctx, sdkTesting.Alice.Signer, body, WithFee(fee), WithFeeDenomination("FOO")); err != nil {
+		t.Fatalf("SubmitWitness: %s", err)
+	}
+	if rc.estimates != 0 {
+		t.Fatalf("expected no gas estimation with a manual fee, got %d", rc.estimates)
+	}
+	if len(rc.fees) != 1 || rc.fees[0].Gas != fee.Gas || !baseUnitsEqual(&rc.fees[0].Amount, &fee.Amount) {
+		t.Fatalf("expected fee %+v to be attached, got %+v", fee, rc.fees)
+	}
+}
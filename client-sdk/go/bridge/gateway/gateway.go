@@ -0,0 +1,273 @@
+// Package gateway implements a minimal REST gateway over the bridge client so that non-Go
+// frontends can interact with the bridge.
+//
+// The gateway exposes the following endpoints, all of which return JSON documents:
+//
+//	GET  /parameters      bridge module parameters
+//	GET  /locks/{id}      status of the lock with the given identifier
+//	GET  /releases/{id}   release event with the given incoming sequence number
+//	POST /locks           submit a pre-signed bridge.Lock transaction
+//
+// Errors are reported as {"error": <message>} with an appropriate status code. Authentication
+// is out of scope and should be handled in front of the gateway.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// defaultQueryTimeout is the default timeout of queries made on behalf of a request.
+	defaultQueryTimeout = 10 * time.Second
+
+	// maxRequestBodySize is the maximum size of a request body.
+	maxRequestBodySize = 1 << 20
+)
+
+// Mux is a HTTP request multiplexer the gateway handlers are registered with.
+type Mux interface {
+	// Handle registers the handler for the given pattern.
+	Handle(pattern string, handler http.Handler)
+}
+
+// SubmitLockRequest is the body of a POST /locks request.
+type SubmitLockRequest struct {
+	// Tx is the CBOR-encoded signed bridge.Lock transaction.
+	Tx []byte `json:"tx"`
+}
+
+// SubmitLockResponse is the response to a POST /locks request.
+type SubmitLockResponse struct {
+	// ID is the identifier assigned to the lock.
+	ID uint64 `json:"id"`
+
+	// Round is the runtime round in which the lock has been made or zero in case it could not be
+	// determined.
+	Round uint64 `json:"round"`
+
+	// TxHash is the hash of the lock transaction.
+	TxHash hash.Hash `json:"tx_hash"`
+}
+
+// errorResponse is the response to a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Option is an option for configuring the gateway.
+type Option func(g *Gateway)
+
+// WithLogger configures the logger used by the gateway.
+func WithLogger(l *logging.Logger) Option {
+	return func(g *Gateway) {
+		g.logger = l
+	}
+}
+
+// WithQueryTimeout configures the timeout of queries and submissions made on behalf of a request.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(g *Gateway) {
+		g.queryTimeout = timeout
+	}
+}
+
+// Gateway is a REST gateway over the bridge client.
+type Gateway struct {
+	rc       client.RuntimeClient
+	bridge   bridge.V1
+	observer *bridge.Observer
+
+	queryTimeout time.Duration
+	logger       *logging.Logger
+}
+
+// Register registers the gateway handlers with the given mux.
+func (g *Gateway) Register(mux Mux) {
+	mux.Handle("/parameters", http.HandlerFunc(g.handleParameters))
+	mux.Handle("/locks", http.HandlerFunc(g.handleSubmitLock))
+	mux.Handle("/locks/", http.HandlerFunc(g.handleLock))
+	mux.Handle("/releases/", http.HandlerFunc(g.handleRelease))
+}
+
+// Handler returns a new handler serving the gateway endpoints.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	g.Register(mux)
+	return mux
+}
+
+func (g *Gateway) handleParameters(w http.ResponseWriter, r *http.Request) {
+	if !g.allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), g.queryTimeout)
+	defer cancel()
+
+	params, err := g.bridge.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		g.writeError(w, http.StatusBadGateway, fmt.Errorf("failed to query parameters: %w", err))
+		return
+	}
+	g.writeJSON(w, http.StatusOK, params)
+}
+
+func (g *Gateway) handleLock(w http.ResponseWriter, r *http.Request) {
+	if !g.allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	id, ok := g.parseID(w, r, "/locks/")
+	if !ok {
+		return
+	}
+	status, ok := g.observer.Lock(id)
+	if !ok {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("%w: %d", bridge.ErrLockNotFound, id))
+		return
+	}
+	g.writeJSON(w, http.StatusOK, status)
+}
+
+func (g *Gateway) handleRelease(w http.ResponseWriter, r *http.Request) {
+	if !g.allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	id, ok := g.parseID(w, r, "/releases/")
+	if !ok {
+		return
+	}
+	release, ok := g.observer.Release(id)
+	if !ok {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("release not found: %d", id))
+		return
+	}
+	g.writeJSON(w, http.StatusOK, release)
+}
+
+func (g *Gateway) handleSubmitLock(w http.ResponseWriter, r *http.Request) {
+	if !g.allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req SubmitLockRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("malformed request: %w", err))
+		return
+	}
+	var utx types.UnverifiedTransaction
+	if err := cbor.Unmarshal(req.Tx, &utx); err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("malformed transaction: %w", err))
+		return
+	}
+	var tx types.Transaction
+	if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("malformed transaction body: %w", err))
+		return
+	}
+	if method := g.bridge.ModuleName() + ".Lock"; tx.Call.Method != method {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("unexpected transaction method: %s (expected %s)", tx.Call.Method, method))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), g.queryTimeout)
+	defer cancel()
+
+	receipt, err := bridge.SubmitWithReceipt(ctx, g.rc, &utx)
+	switch {
+	case errors.Is(err, bridge.ErrReceiptNotFound):
+		// The lock has been executed, so reporting a failure could make the client submit it again.
+		g.logger.Warn("failed to determine the round of a submitted lock",
+			"err", err,
+			"tx_hash", receipt.TxHash,
+		)
+	case err != nil:
+		g.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	var result bridge.LockResult
+	if err = receipt.DecodeResult(&result); err != nil {
+		g.writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	g.logger.Info("lock submitted",
+		"id", result.ID,
+		"round", receipt.Round,
+		"tx_hash", receipt.TxHash,
+	)
+	g.writeJSON(w, http.StatusOK, &SubmitLockResponse{
+		ID:     result.ID,
+		Round:  receipt.Round,
+		TxHash: receipt.TxHash,
+	})
+}
+
+func (g *Gateway) allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	g.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+	return false
+}
+
+func (g *Gateway) parseID(w http.ResponseWriter, r *http.Request, prefix string) (uint64, bool) {
+	raw := strings.TrimPrefix(r.URL.Path, prefix)
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("malformed identifier: %q", raw))
+		return 0, false
+	}
+	return id, true
+}
+
+func (g *Gateway) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		g.logger.Warn("failed to write response",
+			"err", err,
+		)
+	}
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		g.logger.Warn("request failed",
+			"err", err,
+		)
+	}
+	g.writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
+
+// New creates a new gateway.
+//
+// Lock and release lookups are served from the index of the given observer, which must be run
+// by the caller.
+func New(rc client.RuntimeClient, v bridge.V1, observer *bridge.Observer, opts ...Option) *Gateway {
+	g := &Gateway{
+		rc:           rc,
+		bridge:       v,
+		observer:     observer,
+		queryTimeout: defaultQueryTimeout,
+		logger:       logging.GetLogger("bridge/gateway"),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
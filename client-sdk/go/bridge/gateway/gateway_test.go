@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+type fakeBridge struct {
+	bridge.V1
+
+	params *bridge.Parameters
+}
+
+func (b *fakeBridge) ModuleName() string {
+	return bridge.ModuleName
+}
+
+func (b *fakeBridge) Parameters(ctx context.Context, round uint64) (*bridge.Parameters, error) {
+	return b.params, nil
+}
+
+func newTestGateway() *Gateway {
+	params := &bridge.Parameters{
+		Threshold:          1,
+		LocalDenominations: []types.Denomination{types.NativeDenomination},
+	}
+	return New(nil, &fakeBridge{params: params}, bridge.NewObserver(nil))
+}
+
+func request(t *testing.T, h http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("failed to encode request body: %s", err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, &buf))
+	return rec
+}
+
+func TestParameters(t *testing.T) {
+	h := newTestGateway().Handler()
+
+	rec := request(t, h, http.MethodGet, "/parameters", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var params bridge.Parameters
+	if err := json.Unmarshal(rec.Body.Bytes(), &params); err != nil {
+		t.Fatalf("failed to decode parameters: %s", err)
+	}
+	if params.Threshold != 1 {
+		t.Fatalf("unexpected parameters: %+v", params)
+	}
+
+	if rec = request(t, h, http.MethodPost, "/parameters", nil); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestLookups(t *testing.T) {
+	h := newTestGateway().Handler()
+
+	for _, tc := range []struct {
+		path   string
+		status int
+	}{
+		{"/locks/1", http.StatusNotFound},
+		{"/locks/abc", http.StatusBadRequest},
+		{"/locks/", http.StatusBadRequest},
+		{"/releases/1", http.StatusNotFound},
+		{"/releases/-1", http.StatusBadRequest},
+	} {
+		rec := request(t, h, http.MethodGet, tc.path, nil)
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d: %s", tc.path, tc.status, rec.Code, rec.Body)
+		}
+		var rsp errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &rsp); err != nil || rsp.Error == "" {
+			t.Fatalf("%s: expected an error response, got %s", tc.path, rec.Body)
+		}
+	}
+}
+
+func TestSubmitLockRejectsInvalidTransactions(t *testing.T) {
+	h := newTestGateway().Handler()
+
+	witness := types.NewTransaction(nil, "bridge.Witness", &bridge.Witness{ID: 1})
+	for _, tc := range []struct {
+		name string
+		tx   []byte
+	}{
+		{"Malformed", []byte{0xff}},
+		{"NotLock", cbor.Marshal(witness.PrepareForSigning().UnverifiedTransaction())},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := request(t, h, http.MethodPost, "/locks", &SubmitLockRequest{Tx: tc.tx})
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body)
+			}
+		})
+	}
+
+	if rec := request(t, h, http.MethodGet, "/locks", nil); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+// lockClient is a runtime client that executes submitted locks but fails to look up the round
+// they have been executed in.
+type lockClient struct {
+	client.RuntimeClient
+
+	submitted bool
+}
+
+func (rc *lockClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if rc.submitted {
+		return nil, errors.New("node unavailable")
+	}
+	return &block.Block{}, nil
+}
+
+func (rc *lockClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	rc.submitted = true
+	return cbor.Marshal(&bridge.LockResult{ID: 7}), nil
+}
+
+func TestSubmitLockUnknownRound(t *testing.T) {
+	rc := &lockClient{}
+	h := New(rc, &fakeBridge{}, bridge.NewObserver(nil)).Handler()
+
+	lock := types.NewTransaction(nil, "bridge.Lock", &bridge.Lock{})
+	utx := lock.PrepareForSigning().UnverifiedTransaction()
+	rec := request(t, h, http.MethodPost, "/locks", &SubmitLockRequest{Tx: cbor.Marshal(utx)})
+
+	// The lock has been executed, so it must be reported as such even without its round.
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var rsp SubmitLockResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	if h := bridge.TxHash(utx); rsp.ID != 7 || rsp.Round != 0 || !rsp.TxHash.Equal(&h) {
+		t.Fatalf("unexpected response: %+v", rsp)
+	}
+}
@@ -0,0 +1,210 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// DefaultInfoCacheTTL is the default duration for which bridge info is cached.
+const DefaultInfoCacheTTL = 10 * time.Second
+
+// ErrUnknownDenominationInfo is the error returned when no metadata is known for a denomination.
+var ErrUnknownDenominationInfo = errors.New("bridge: unknown denomination info")
+
+// DenominationInfo is metadata about a denomination.
+type DenominationInfo struct {
+	// Symbol is the ticker symbol of the denomination.
+	Symbol string `json:"symbol"`
+
+	// Decimals is the number of decimal places of the denomination's base unit.
+	Decimals uint8 `json:"decimals"`
+}
+
+// BridgedDenomination is a denomination supported by the bridge.
+type BridgedDenomination struct {
+	// Denomination is the local denomination.
+	Denomination types.Denomination `json:"denomination"`
+
+	// Remote is the corresponding remote denomination in case the denomination is remote, or nil
+	// if the denomination is local to this side of the bridge.
+	Remote RemoteDenomination `json:"remote,omitempty"`
+
+	// Info is the denomination metadata or nil if no metadata is known for the denomination.
+	Info *DenominationInfo `json:"info,omitempty"`
+}
+
+// IsLocal returns true iff the denomination is local to this side of the bridge.
+func (bd *BridgedDenomination) IsLocal() bool {
+	return bd.Remote == nil
+}
+
+// RuntimeBridgeInfo is aggregated information about the bridge, suitable for rendering a bridge
+// user interface.
+type RuntimeBridgeInfo struct {
+	// Parameters are the bridge module parameters.
+	Parameters *Parameters `json:"parameters"`
+
+	// NativeSymbol is the symbol of the native denomination.
+	NativeSymbol string `json:"native_symbol"`
+
+	// Denominations are all local and remote denominations supported by the bridge.
+	Denominations map[types.Denomination]*BridgedDenomination `json:"denominations"`
+}
+
+type infoCacheEntry struct {
+	info    *RuntimeBridgeInfo
+	expires time.Time
+}
+
+type infoCache struct {
+	sync.Mutex
+
+	clock   Clock
+	ttl     time.Duration
+	entries map[uint64]*infoCacheEntry
+}
+
+func (c *infoCache) get(round uint64) *RuntimeBridgeInfo {
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.clock.Now()
+	for r, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, r)
+		}
+	}
+	if entry, ok := c.entries[round]; ok {
+		return entry.info
+	}
+	return nil
+}
+
+func (c *infoCache) put(round uint64, info *RuntimeBridgeInfo) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.entries[round] = &infoCacheEntry{
+		info:    info,
+		expires: c.clock.Now().Add(c.ttl),
+	}
+}
+
+// WithDenominationInfo configures the metadata of the given denomination.
+//
+// The bridge runtime does not expose denomination metadata so it must be supplied by the client.
+func WithDenominationInfo(denomination types.Denomination, info DenominationInfo) V1Option {
+	return func(a *v1) {
+		a.denominations[denomination] = info
+	}
+}
+
+// WithConsensusStaking configures the consensus staking backend used to resolve the metadata of
+// the native denomination, which corresponds to the consensus layer token.
+//
+// Connect configures the backend automatically.
+func WithConsensusStaking(backend staking.Backend) V1Option {
+	return func(a *v1) {
+		a.staking = backend
+	}
+}
+
+// WithInfoCacheTTL configures the duration for which the results of Info are cached.
+//
+// By default DefaultInfoCacheTTL is used.
+func WithInfoCacheTTL(ttl time.Duration) V1Option {
+	return func(a *v1) {
+		a.infoCache.ttl = ttl
+	}
+}
+
+// Implements V1.
+func (a *v1) Info(ctx context.Context, round uint64) (*RuntimeBridgeInfo, error) {
+	if info := a.infoCache.get(round); info != nil {
+		return info, nil
+	}
+
+	params, err := a.Parameters(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &RuntimeBridgeInfo{
+		Parameters:    params,
+		NativeSymbol:  types.NativeDenomination.String(),
+		Denominations: make(map[types.Denomination]*BridgedDenomination),
+	}
+	if symbol, _, err := a.NativeDenominationInfo(ctx); err == nil {
+		info.NativeSymbol = symbol
+	}
+	addDenomination := func(denomination types.Denomination, remote RemoteDenomination) {
+		bd := &BridgedDenomination{
+			Denomination: denomination,
+			Remote:       remote,
+		}
+		if di, ok := a.denominations[denomination]; ok {
+			bd.Info = &di
+		}
+		info.Denominations[denomination] = bd
+	}
+	for _, local := range params.LocalDenominations {
+		addDenomination(local, nil)
+	}
+	for _, m := range params.SortedRemoteDenominations() {
+		addDenomination(m.Local, m.Remote)
+	}
+
+	a.infoCache.put(round, info)
+	return info, nil
+}
+
+// Implements V1.
+func (a *v1) NativeDenominationInfo(ctx context.Context) (string, uint8, error) {
+	a.nativeInfoLock.Lock()
+	defer a.nativeInfoLock.Unlock()
+
+	if a.nativeInfo != nil {
+		return a.nativeInfo.Symbol, a.nativeInfo.Decimals, nil
+	}
+
+	if a.staking != nil {
+		di, err := fetchConsensusTokenInfo(ctx, a.staking)
+		if err == nil {
+			a.nativeInfo = di
+			return di.Symbol, di.Decimals, nil
+		}
+		if _, ok := a.denominations[types.NativeDenomination]; !ok {
+			return "", 0, err
+		}
+		a.logger.Debug("failed to resolve native denomination info, using configured info",
+			"err", err,
+		)
+	}
+
+	if di, ok := a.denominations[types.NativeDenomination]; ok {
+		return di.Symbol, di.Decimals, nil
+	}
+	return "", 0, fmt.Errorf("%w: %s", ErrUnknownDenominationInfo, types.NativeDenomination)
+}
+
+func fetchConsensusTokenInfo(ctx context.Context, backend staking.Backend) (*DenominationInfo, error) {
+	symbol, err := backend.TokenSymbol(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consensus token symbol: %w", err)
+	}
+	exponent, err := backend.TokenValueExponent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consensus token value exponent: %w", err)
+	}
+	return &DenominationInfo{
+		Symbol:   symbol,
+		Decimals: exponent,
+	}, nil
+}
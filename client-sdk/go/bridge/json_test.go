@@ -0,0 +1,152 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	amount := types.NewBaseUnits(*quantity.NewFromUint64(1000), types.Denomination("oETH"))
+	target := NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314")
+
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"Lock", &Lock{Target: target, Amount: amount}},
+		{"Witness", &Witness{ID: 1, Signature: []byte{0xde, 0xad, 0xbe, 0xef}}},
+		{"Release", &Release{ID: 2, Target: sdkTesting.Alice.Address, Amount: amount}},
+		{"LockEvent", &LockEvent{ID: 3, Owner: sdkTesting.Bob.Address, Target: target, Amount: amount}},
+		{"ReleaseEvent", &ReleaseEvent{ID: 4, Target: sdkTesting.Alice.Address, Amount: amount}},
+		{"WitnessesSignedEvent", &WitnessesSignedEvent{
+			ID:         5,
+			Op:         Operation{Lock: &Lock{Target: target, Amount: amount}},
+			Witnesses:  []uint16{0, 1},
+			Signatures: [][]byte{{1, 2}, {3, 4}},
+		}},
+		{"Parameters", &Parameters{
+			Witnesses:          []types.PublicKey{{PublicKey: sdkTesting.Alice.Signer.Public()}},
+			Threshold:          1,
+			LocalDenominations: []types.Denomination{types.NativeDenomination},
+			RemoteDenominations: map[types.Denomination]RemoteDenomination{
+				"oETH": RemoteDenomination{0xaa, 0xbb},
+			},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			typ := reflect.TypeOf(tc.value).Elem()
+			expected := cbor.Marshal(tc.value)
+
+			// CBOR round-trip.
+			fromCBOR := reflect.New(typ).Interface()
+			if err := cbor.Unmarshal(expected, fromCBOR); err != nil {
+				t.Fatalf("failed to unmarshal CBOR: %s", err)
+			}
+			if !bytes.Equal(cbor.Marshal(fromCBOR), expected) {
+				t.Fatalf("CBOR round-trip mismatch")
+			}
+
+			// JSON round-trip must result in the same value as the CBOR round-trip.
+			raw, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("failed to marshal JSON: %s", err)
+			}
+			fromJSON := reflect.New(typ).Interface()
+			if err = json.Unmarshal(raw, fromJSON); err != nil {
+				t.Fatalf("failed to unmarshal JSON %s: %s", raw, err)
+			}
+			if !bytes.Equal(cbor.Marshal(fromJSON), expected) {
+				t.Fatalf("JSON round-trip mismatch: %s", raw)
+			}
+		})
+	}
+}
+
+func TestAmountDenominationRoundTrip(t *testing.T) {
+	target := NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314")
+
+	for _, denomination := range []types.Denomination{types.NativeDenomination, "oETH"} {
+		amount := types.NewBaseUnits(*quantity.NewFromUint64(10), denomination)
+
+		for _, tc := range []struct {
+			name   string
+			value  interface{}
+			amount func(v interface{}) types.BaseUnits
+		}{
+			{"Lock", &Lock{Target: target, Amount: amount}, func(v interface{}) types.BaseUnits {
+				return v.(*Lock).Amount
+			}},
+			{"Release", &Release{ID: 1, Target: sdkTesting.Alice.Address, Amount: amount}, func(v interface{}) types.BaseUnits {
+				return v.(*Release).Amount
+			}},
+			{"LockOperation", &Operation{Lock: &Lock{Target: target, Amount: amount}}, func(v interface{}) types.BaseUnits {
+				return v.(*Operation).Lock.Amount
+			}},
+			{"ReleaseOperation", &Operation{Release: &Release{ID: 1, Target: sdkTesting.Alice.Address, Amount: amount}}, func(v interface{}) types.BaseUnits {
+				return v.(*Operation).Release.Amount
+			}},
+		} {
+			t.Run(tc.name+"/"+denomination.String(), func(t *testing.T) {
+				decoded := reflect.New(reflect.TypeOf(tc.value).Elem()).Interface()
+				if err := cbor.Unmarshal(cbor.Marshal(tc.value), decoded); err != nil {
+					t.Fatalf("failed to unmarshal CBOR: %s", err)
+				}
+
+				actual := tc.amount(decoded)
+				if actual.Denomination != denomination {
+					t.Fatalf("denomination not preserved: expected %q, got %q", denomination, actual.Denomination)
+				}
+				if actual.Amount.Cmp(&amount.Amount) != 0 {
+					t.Fatalf("amount not preserved: expected %s, got %s", amount.Amount, actual.Amount)
+				}
+			})
+		}
+	}
+
+	// The native and a named denomination must not be encoded identically.
+	native := cbor.Marshal(&Lock{Target: target, Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination)})
+	named := cbor.Marshal(&Lock{Target: target, Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), "oETH")})
+	if bytes.Equal(native, named) {
+		t.Fatalf("native and named denominations are encoded identically")
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	raw, err := json.Marshal(&LockEvent{
+		ID:     1,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal lock event: %s", err)
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		t.Fatalf("failed to unmarshal lock event: %s", err)
+	}
+	if target := fields["target"]; target != "0102030405060708090a0b0c0d0e0f1011121314" {
+		t.Fatalf("expected hex-encoded remote address, got %v", target)
+	}
+
+	raw, err = json.Marshal(map[types.Denomination]RemoteDenomination{"oETH": {0xaa, 0xbb}})
+	if err != nil {
+		t.Fatalf("failed to marshal remote denominations: %s", err)
+	}
+	if string(raw) != `{"oETH":"aabb"}` {
+		t.Fatalf("expected hex-encoded remote denomination, got %s", raw)
+	}
+
+	// Remote denominations are still encoded as CBOR byte strings.
+	if enc := hex.EncodeToString(cbor.Marshal(RemoteDenomination{0xaa, 0xbb})); enc != "42aabb" {
+		t.Fatalf("unexpected CBOR encoding of remote denomination: %s", enc)
+	}
+}
@@ -0,0 +1,195 @@
+package bridge
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+)
+
+// Keyring source prefixes (see LoadKeyring).
+const (
+	// KeyringSourceTest is the prefix of keyring sources using the SDK test keys.
+	KeyringSourceTest = "test:"
+	// KeyringSourceFile is the prefix of keyring sources loading keys from a file.
+	KeyringSourceFile = "file:"
+	// KeyringSourceEnv is the prefix of keyring sources loading keys from an environment variable.
+	KeyringSourceEnv = "env:"
+)
+
+var (
+	// ErrUnknownKey is the error returned when a keyring does not contain a key with the given
+	// name.
+	ErrUnknownKey = errors.New("bridge: unknown key")
+
+	// ErrMalformedKeyring is the error returned when keys cannot be loaded into a keyring.
+	ErrMalformedKeyring = errors.New("bridge: malformed keyring")
+)
+
+// testKeys are the SDK test keys by name.
+var testKeys = map[string]sdkTesting.TestKey{
+	"alice":   sdkTesting.Alice,
+	"bob":     sdkTesting.Bob,
+	"charlie": sdkTesting.Charlie,
+	"dave":    sdkTesting.Dave,
+}
+
+// KeyringEntry is a named key held by a keyring.
+type KeyringEntry struct {
+	// Name is the name of the key.
+	Name string
+
+	// Signer is the signer of the key.
+	Signer signature.Signer
+}
+
+// Keyring is a named set of signers, e.g. the witness keys managed by a witness daemon.
+type Keyring interface {
+	// Entries returns all keys held by the keyring, ordered by name.
+	Entries() []KeyringEntry
+
+	// Signer returns the signer of the key with the given name or ErrUnknownKey.
+	Signer(name string) (signature.Signer, error)
+}
+
+type memoryKeyring struct {
+	entries []KeyringEntry
+}
+
+// Implements Keyring.
+func (kr *memoryKeyring) Entries() []KeyringEntry {
+	return append([]KeyringEntry{}, kr.entries...)
+}
+
+// Implements Keyring.
+func (kr *memoryKeyring) Signer(name string) (signature.Signer, error) {
+	for _, e := range kr.entries {
+		if e.Name == name {
+			return e.Signer, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: '%s'", ErrUnknownKey, name)
+}
+
+func newMemoryKeyring(entries []KeyringEntry) *memoryKeyring {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return &memoryKeyring{entries: entries}
+}
+
+// NewTestKeyring creates a keyring holding the SDK test keys with the given (case-insensitive)
+// names, i.e. alice, bob, charlie and dave. If no names are given, all test keys are held.
+//
+// Test keys are publicly known and must only be used for testing.
+func NewTestKeyring(names ...string) (Keyring, error) {
+	if len(names) == 0 {
+		for name := range testKeys {
+			names = append(names, name)
+		}
+	}
+
+	var entries []KeyringEntry
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		key, ok := testKeys[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown test key '%s'", ErrUnknownKey, name)
+		}
+		entries = append(entries, KeyringEntry{
+			Name:   name,
+			Signer: key.Signer,
+		})
+	}
+	return newMemoryKeyring(entries), nil
+}
+
+// NewKeyringFromJSON creates a keyring holding the Ed25519 keys encoded in the given JSON object,
+// which maps key names to base64-encoded 32-byte private key seeds.
+func NewKeyringFromJSON(data []byte) (Keyring, error) {
+	var seeds map[string]string
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformedKeyring, err)
+	}
+
+	var entries []KeyringEntry
+	for name, encoded := range seeds {
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key '%s': %s", ErrMalformedKeyring, name, err)
+		}
+		signer, err := memorySigner.NewFromSeed(seed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key '%s': %s", ErrMalformedKeyring, name, err)
+		}
+		entries = append(entries, KeyringEntry{
+			Name:   name,
+			Signer: ed25519.WrapSigner(signer),
+		})
+	}
+	return newMemoryKeyring(entries), nil
+}
+
+// LoadKeyring loads a keyring from the given source, which is one of:
+//
+//   - test:NAME[,NAME...] for the SDK test keys with the given names (see NewTestKeyring),
+//   - file:PATH for the keys in the JSON file at the given path (see NewKeyringFromJSON),
+//   - env:VAR for the keys in the JSON-encoded environment variable (see NewKeyringFromJSON).
+func LoadKeyring(source string) (Keyring, error) {
+	switch {
+	case strings.HasPrefix(source, KeyringSourceTest):
+		names := strings.TrimPrefix(source, KeyringSourceTest)
+		if names == "" {
+			return nil, fmt.Errorf("%w: no test keys given", ErrMalformedKeyring)
+		}
+		return NewTestKeyring(strings.Split(names, ",")...)
+	case strings.HasPrefix(source, KeyringSourceFile):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(source, KeyringSourceFile))
+		if err != nil {
+			return nil, fmt.Errorf("bridge: failed to read keyring: %w", err)
+		}
+		return NewKeyringFromJSON(data)
+	case strings.HasPrefix(source, KeyringSourceEnv):
+		name := strings.TrimPrefix(source, KeyringSourceEnv)
+		data, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: environment variable %s not set", ErrMalformedKeyring, name)
+		}
+		return NewKeyringFromJSON([]byte(data))
+	default:
+		return nil, fmt.Errorf("%w: unsupported source '%s'", ErrMalformedKeyring, source)
+	}
+}
+
+// WitnessKey is a keyring key that is an authorized witness.
+type WitnessKey struct {
+	KeyringEntry
+
+	// Index is the index of the key in the list of authorized witnesses.
+	Index uint16
+}
+
+// AuthorizedWitnessKeys returns the keys held by the given keyring that are authorized witnesses
+// according to the given parameters together with their witness indices, ordered by name.
+func AuthorizedWitnessKeys(params *Parameters, kr Keyring) []WitnessKey {
+	var keys []WitnessKey
+	for _, e := range kr.Entries() {
+		if index, ok := params.WitnessIndex(e.Signer.Public()); ok {
+			keys = append(keys, WitnessKey{
+				KeyringEntry: e,
+				Index:        index,
+			})
+		}
+	}
+	return keys
+}
@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestTestKeyring(t *testing.T) {
+	kr, err := LoadKeyring("test:Dave, bob")
+	if err != nil {
+		t.Fatalf("failed to load test keyring: %s", err)
+	}
+	entries := kr.Entries()
+	if len(entries) != 2 || entries[0].Name != "bob" || entries[1].Name != "dave" {
+		t.Fatalf("unexpected keyring entries: %+v", entries)
+	}
+	signer, err := kr.Signer("bob")
+	if err != nil || !signer.Public().Equal(sdkTesting.Bob.Signer.Public()) {
+		t.Fatalf("expected Bob's signer, got %v (err: %v)", signer, err)
+	}
+	if _, err = kr.Signer("alice"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+	if _, err = NewTestKeyring("eve"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+
+	params := &Parameters{
+		Witnesses: []types.PublicKey{
+			{PublicKey: sdkTesting.Alice.Signer.Public()},
+			{PublicKey: sdkTesting.Dave.Signer.Public()},
+		},
+	}
+	keys := AuthorizedWitnessKeys(params, kr)
+	if len(keys) != 1 || keys[0].Name != "dave" || keys[0].Index != 1 {
+		t.Fatalf("unexpected authorized witness keys: %+v", keys)
+	}
+}
+
+func TestFileKeyring(t *testing.T) {
+	seed := make([]byte, 32)
+	seed[0] = 1
+	path := filepath.Join(t.TempDir(), "keys.json")
+	data := fmt.Sprintf(`{"witness": "%s"}`, base64.StdEncoding.EncodeToString(seed))
+	if err := ioutil.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write keyring: %s", err)
+	}
+
+	kr, err := LoadKeyring(KeyringSourceFile + path)
+	if err != nil {
+		t.Fatalf("failed to load file keyring: %s", err)
+	}
+	if _, err = kr.Signer("witness"); err != nil {
+		t.Fatalf("failed to get signer: %s", err)
+	}
+
+	t.Setenv("TEST_BRIDGE_KEYRING", data)
+	envKr, err := LoadKeyring(KeyringSourceEnv + "TEST_BRIDGE_KEYRING")
+	if err != nil {
+		t.Fatalf("failed to load environment keyring: %s", err)
+	}
+	if !envKr.Entries()[0].Signer.Public().Equal(kr.Entries()[0].Signer.Public()) {
+		t.Fatalf("expected the same key from the file and the environment")
+	}
+
+	for _, source := range []string{
+		`foo:bar`,
+		KeyringSourceEnv + "TEST_BRIDGE_KEYRING_UNSET",
+		KeyringSourceTest,
+	} {
+		if _, err = LoadKeyring(source); !errors.Is(err, ErrMalformedKeyring) {
+			t.Fatalf("expected ErrMalformedKeyring for %s, got %v", source, err)
+		}
+	}
+	if _, err = NewKeyringFromJSON([]byte(`{"witness": "AAEC"}`)); !errors.Is(err, ErrMalformedKeyring) {
+		t.Fatalf("expected ErrMalformedKeyring for a short seed, got %v", err)
+	}
+}
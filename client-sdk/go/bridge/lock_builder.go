@@ -0,0 +1,104 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrInvalidLockAmount is the error returned when the amount to lock is zero.
+var ErrInvalidLockAmount = errors.New("bridge: lock amount must be positive")
+
+// LockBuilder accumulates the target, amount and denomination of a lock and validates them
+// against the bridge parameters before a lock is produced, so that invalid locks are rejected
+// before signing rather than failing on-chain.
+type LockBuilder struct {
+	target       RemoteAddress
+	amount       quantity.Quantity
+	denomination types.Denomination
+}
+
+// NewLockBuilder creates a new lock builder for locking an amount of the native denomination.
+func NewLockBuilder() *LockBuilder {
+	return &LockBuilder{
+		denomination: types.NativeDenomination,
+	}
+}
+
+// Target sets the remote address to lock funds for.
+func (b *LockBuilder) Target(target RemoteAddress) *LockBuilder {
+	b.target = target
+	return b
+}
+
+// Amount sets the amount to lock in base units.
+func (b *LockBuilder) Amount(amount quantity.Quantity) *LockBuilder {
+	b.amount = amount
+	return b
+}
+
+// Denomination sets the denomination of the amount to lock.
+func (b *LockBuilder) Denomination(denomination types.Denomination) *LockBuilder {
+	b.denomination = denomination
+	return b
+}
+
+// Build validates the lock against the given bridge parameters and remote address codec and
+// returns the body of the corresponding bridge.Lock call.
+//
+// ErrMalformedRemoteAddress is returned in case the target is not valid according to the codec,
+// ErrInvalidLockAmount in case the amount is zero and ErrUnsupportedDenomination in case the
+// denomination is not supported by the bridge.
+func (b *LockBuilder) Build(params *Parameters, codec RemoteAddressCodec) (*Lock, error) {
+	if err := codec.Validate(b.target); err != nil {
+		return nil, fmt.Errorf("malformed lock target: %w", err)
+	}
+	if b.amount.IsZero() {
+		return nil, ErrInvalidLockAmount
+	}
+	if _, ok := params.ResolveDenomination(b.denomination); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDenomination, b.denomination)
+	}
+	return &Lock{
+		Target: b.target,
+		Amount: types.NewBaseUnits(b.amount, b.denomination),
+	}, nil
+}
+
+// Implements V1.
+func (a *v1) PrepareLock(
+	ctx context.Context,
+	pk signature.PublicKey,
+	b *LockBuilder,
+	opts ...SubmitOption,
+) (*types.TransactionSigner, signature.Context, error) {
+	params, err := a.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query parameters: %w", err)
+	}
+	body, err := b.Build(params, a.codec)
+	if err != nil {
+		return nil, "", err
+	}
+
+	o := newSubmitOptions(opts...)
+	if o.balancePrecheck {
+		if err = a.precheckBalance(ctx, AddressOfPublicKey(pk), &body.Amount); err != nil {
+			return nil, "", err
+		}
+	}
+	tx, chainContext, err := prepareTx(ctx, a, pk, a.method(methodLock), body, o)
+	if err != nil {
+		if o.nonce != nil {
+			o.nonce.Reset()
+		}
+		return nil, "", err
+	}
+	return tx.PrepareForSigning(), chainContext, nil
+}
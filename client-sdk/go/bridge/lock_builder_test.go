@@ -0,0 +1,68 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestLockBuilder(t *testing.T) {
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{types.NativeDenomination},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			"oETH": RemoteDenomination("eth"),
+		},
+	}
+	target := NewRemoteAddressFromHex("0000000000000000000000000000000000000001")
+
+	lock, err := NewLockBuilder().
+		Target(target).
+		Amount(*quantity.NewFromUint64(10)).
+		Denomination("oETH").
+		Build(params, DefaultRemoteAddressCodec)
+	if err != nil {
+		t.Fatalf("failed to build valid lock: %s", err)
+	}
+	if !lock.Target.Equal(target) {
+		t.Fatalf("expected target %s, got %s", target, lock.Target)
+	}
+	if lock.Amount.Denomination != "oETH" || lock.Amount.Amount.Cmp(quantity.NewFromUint64(10)) != 0 {
+		t.Fatalf("unexpected lock amount: %s", lock.Amount)
+	}
+
+	for _, tc := range []struct {
+		name string
+		b    *LockBuilder
+		err  error
+	}{
+		{
+			"MissingTarget",
+			NewLockBuilder().Amount(*quantity.NewFromUint64(10)),
+			ErrMalformedRemoteAddress,
+		},
+		{
+			"ShortTarget",
+			NewLockBuilder().Target(RemoteAddress{1, 2, 3}).Amount(*quantity.NewFromUint64(10)),
+			ErrMalformedRemoteAddress,
+		},
+		{
+			"ZeroAmount",
+			NewLockBuilder().Target(target),
+			ErrInvalidLockAmount,
+		},
+		{
+			"UnsupportedDenomination",
+			NewLockBuilder().Target(target).Amount(*quantity.NewFromUint64(10)).Denomination("FOO"),
+			ErrUnsupportedDenomination,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.b.Build(params, DefaultRemoteAddressCodec); !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
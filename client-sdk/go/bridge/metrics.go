@@ -0,0 +1,131 @@
+package bridge
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	witnessFeeBalance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_bridge_witness_fee_balance",
+			Help: "Balance of the witness account in the fee denomination (base units).",
+		},
+		[]string{"witness"},
+	)
+	witnessLowFeeBalance = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_bridge_witness_low_fee_balance",
+			Help: "Number of times the witness account balance was below the minimum fee balance.",
+		},
+		[]string{"witness"},
+	)
+	witnessSubmissions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_bridge_witness_submissions",
+			Help: "Number of witness transaction submissions by outcome and denomination of the witnessed lock.",
+		},
+		[]string{"witness", "outcome", "denomination"},
+	)
+	droppedEvents = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_bridge_dropped_events",
+			Help: "Number of events dropped by event watchers as the consumer was not keeping up.",
+		},
+	)
+	parametersCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_bridge_parameters_cache_lookups",
+			Help: "Number of bridge parameters cache lookups by result (hit or miss).",
+		},
+		[]string{"result"},
+	)
+	relayQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_bridge_relay_queue_depth",
+			Help: "Number of signed operations waiting to be relayed to the remote side.",
+		},
+	)
+	relayLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "oasis_bridge_relay_latency_seconds",
+			Help:    "Time between queueing a signed operation and successfully relaying it.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+
+	bridgeCollectors = []prometheus.Collector{
+		witnessFeeBalance,
+		witnessLowFeeBalance,
+		witnessSubmissions,
+		droppedEvents,
+		parametersCacheLookups,
+		relayQueueDepth,
+		relayLatency,
+	}
+
+	metricsOnce sync.Once
+)
+
+// Outcomes of witness transaction submissions (see SubmissionOutcome).
+const (
+	// SubmissionSuccess means that the witness transaction has been successfully executed.
+	SubmissionSuccess = "success"
+	// SubmissionRetryableError means that the witness transaction could not be submitted or its
+	// result is unknown, e.g. due to a connection failure, so submitting it again may succeed.
+	SubmissionRetryableError = "retryable_error"
+	// SubmissionPermanentError means that the witness transaction has been executed and failed.
+	SubmissionPermanentError = "permanent_error"
+	// SubmissionAlreadyWitnessed means that the witness has already signed the lock before.
+	SubmissionAlreadyWitnessed = "already_witnessed"
+)
+
+// otherDenominationLabel is the metric label used for denominations not supported by the bridge,
+// so that the number of label values is bounded by the bridge parameters.
+const otherDenominationLabel = "other"
+
+// SubmissionOutcome classifies the result of submitting a witness transaction to the bridge
+// module with the given name.
+//
+// The classification is based on the error types and module error codes, a nil error being a
+// success.
+func SubmissionOutcome(module string, err error) string {
+	var failed *types.FailedCallResult
+	switch {
+	case err == nil:
+		return SubmissionSuccess
+	case IsModuleErrorFor(err, module, ErrAlreadySubmittedSignatureCode):
+		return SubmissionAlreadyWitnessed
+	case errors.As(err, &failed):
+		return SubmissionPermanentError
+	default:
+		return SubmissionRetryableError
+	}
+}
+
+// denominationLabel returns the metric label for the given denomination.
+func denominationLabel(params *Parameters, denomination types.Denomination) string {
+	if _, ok := params.ResolveDenomination(denomination); !ok {
+		return otherDenominationLabel
+	}
+	return denomination.String()
+}
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(bridgeCollectors...)
+	})
+}
+
+// quantityToFloat converts a quantity into a (possibly imprecise) float suitable for metrics.
+func quantityToFloat(q *quantity.Quantity) float64 {
+	f, _ := new(big.Float).SetInt(q.ToBigInt()).Float64()
+	return f
+}
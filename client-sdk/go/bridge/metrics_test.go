@@ -0,0 +1,61 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSubmissionOutcome(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		err     error
+		outcome string
+	}{
+		{"Success", nil, SubmissionSuccess},
+		{
+			"AlreadyWitnessed",
+			fmt.Errorf("bridge.Witness: %w", &types.FailedCallResult{Module: ModuleName, Code: ErrAlreadySubmittedSignatureCode}),
+			SubmissionAlreadyWitnessed,
+		},
+		{
+			"OtherModuleError",
+			&types.FailedCallResult{Module: ModuleName, Code: ErrNotAuthorizedCode},
+			SubmissionPermanentError,
+		},
+		{
+			"AlreadySubmittedOtherModule",
+			&types.FailedCallResult{Module: "accounts", Code: ErrAlreadySubmittedSignatureCode},
+			SubmissionPermanentError,
+		},
+		{"Transport", errors.New("connection refused"), SubmissionRetryableError},
+		{"Canceled", context.Canceled, SubmissionRetryableError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if outcome := SubmissionOutcome(ModuleName, tc.err); outcome != tc.outcome {
+				t.Fatalf("expected outcome %s, got %s", tc.outcome, outcome)
+			}
+		})
+	}
+}
+
+func TestDenominationLabel(t *testing.T) {
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{types.NativeDenomination},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			"oETH": RemoteDenomination("eth"),
+		},
+	}
+	for denomination, label := range map[types.Denomination]string{
+		types.NativeDenomination: "<native>",
+		"oETH":                   "oETH",
+		"FOO":                    otherDenominationLabel,
+	} {
+		if l := denominationLabel(params, denomination); l != label {
+			t.Fatalf("expected label %s for %s, got %s", label, denomination, l)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamEventsNDJSON tails bridge events and writes each of them to the given writer as a JSON
+// object on its own line (newline-delimited JSON). Each object includes the round and index of
+// the event.
+//
+// Events are watched as in WatchEvents with the given options. It returns once the context is
+// canceled, the subscription fails or writing fails.
+func StreamEventsNDJSON(ctx context.Context, v V1, w io.Writer, opts ...WatchOption) error {
+	evCh, evSub, err := v.WatchEvents(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to subscribe to bridge events: %w", err)
+	}
+	defer evSub.Close()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-evCh:
+			if !ok {
+				return evSub.Err()
+			}
+			if err = enc.Encode(ev); err != nil {
+				return fmt.Errorf("bridge: failed to write event: %w", err)
+			}
+		}
+	}
+}
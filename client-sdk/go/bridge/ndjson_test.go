@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// failingWriter is a writer failing all writes with the configured error.
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestStreamEventsNDJSON(t *testing.T) {
+	rc := &eventsClient{
+		events: map[uint64][]*coreClient.Event{
+			1: {
+				{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 1})},
+				{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: 2})},
+			},
+			3: {
+				{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 3})},
+			},
+		},
+	}
+	v := NewV1(rc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- StreamEventsNDJSON(ctx, v, w)
+		w.Close()
+	}()
+	go func() {
+		for round := uint64(1); round <= 3; round++ {
+			rc.produce(round)
+		}
+	}()
+
+	// Each event is written as a JSON object on its own line.
+	scanner := bufio.NewScanner(r)
+	for _, expected := range []struct {
+		round uint64
+		index uint32
+		lock  uint64
+	}{
+		{1, 0, 1},
+		{1, 1, 0},
+		{3, 0, 3},
+	} {
+		if !scanner.Scan() {
+			t.Fatalf("expected event line, got %v", scanner.Err())
+		}
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("malformed event line '%s': %s", scanner.Text(), err)
+		}
+		if ev.Round != expected.round || ev.Index != expected.index {
+			t.Fatalf("expected event %d of round %d, got event %d of round %d", expected.index, expected.round, ev.Index, ev.Round)
+		}
+		switch {
+		case expected.lock != 0 && (ev.Lock == nil || ev.Lock.ID != expected.lock):
+			t.Fatalf("expected lock %d, got '%s'", expected.lock, scanner.Text())
+		case expected.lock == 0 && (ev.Release == nil || ev.Release.ID != 2):
+			t.Fatalf("expected release 2, got '%s'", scanner.Text())
+		}
+	}
+
+	cancel()
+	go func() {
+		// Drain any output written until the stream noticed the cancellation.
+		_, _ = io.Copy(io.Discard, r)
+	}()
+	if err := <-streamErr; err != context.Canceled {
+		t.Fatalf("expected stream to be canceled, got %v", err)
+	}
+
+	// Write failures terminate the stream.
+	errWrite := errors.New("broken pipe")
+	rc = &eventsClient{events: rc.events}
+	go func() {
+		rc.produce(1)
+	}()
+	err := StreamEventsNDJSON(context.Background(), NewV1(rc), &failingWriter{err: errWrite})
+	if !errors.Is(err, errWrite) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
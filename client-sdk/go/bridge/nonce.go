@@ -0,0 +1,93 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// NonceManager tracks the next transaction nonce of a single account so that consecutive
+// transactions can be submitted without querying the nonce each time.
+type NonceManager struct {
+	sync.Mutex
+
+	accounts accounts.V1
+	address  types.Address
+
+	nonce uint64
+	valid bool
+}
+
+// Next returns the nonce to use for the next transaction and advances the tracked nonce.
+//
+// The nonce is fetched from the runtime on first use and after a call to Reset.
+func (nm *NonceManager) Next(ctx context.Context) (uint64, error) {
+	nm.Lock()
+	defer nm.Unlock()
+
+	if !nm.valid {
+		nonce, err := nm.accounts.Nonce(ctx, client.RoundLatest, nm.address)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch account nonce: %w", err)
+		}
+		nm.nonce = nonce
+		nm.valid = true
+	}
+
+	nonce := nm.nonce
+	nm.nonce++
+	return nonce, nil
+}
+
+// Reset discards the tracked nonce so that it is fetched from the runtime again on next use.
+//
+// It should be called whenever a transaction using a nonce obtained from Next fails, as the
+// tracked nonce may no longer match the account state.
+func (nm *NonceManager) Reset() {
+	nm.Lock()
+	defer nm.Unlock()
+
+	nm.valid = false
+}
+
+// NewNonceManager creates a new nonce manager for the given account.
+func NewNonceManager(accounts accounts.V1, address types.Address) *NonceManager {
+	return &NonceManager{
+		accounts: accounts,
+		address:  address,
+	}
+}
+
+// NoncePool tracks the next transaction nonces of several accounts. Each account has its own
+// NonceManager so that the nonce sequences of different accounts are isolated from each other.
+type NoncePool struct {
+	sync.Mutex
+
+	accounts accounts.V1
+	managers map[types.Address]*NonceManager
+}
+
+// Get returns the nonce manager of the given account, creating it on first use.
+func (np *NoncePool) Get(address types.Address) *NonceManager {
+	np.Lock()
+	defer np.Unlock()
+
+	nm, ok := np.managers[address]
+	if !ok {
+		nm = NewNonceManager(np.accounts, address)
+		np.managers[address] = nm
+	}
+	return nm
+}
+
+// NewNoncePool creates a new nonce pool for accounts of the given accounts module.
+func NewNoncePool(accounts accounts.V1) *NoncePool {
+	return &NoncePool{
+		accounts: accounts,
+		managers: make(map[types.Address]*NonceManager),
+	}
+}
@@ -0,0 +1,281 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// ObserverOption is an option for configuring an observer.
+type ObserverOption func(o *Observer)
+
+// WithObserverCheckpointStore configures the store used to persist observer progress.
+//
+// By default progress is only kept in memory.
+func WithObserverCheckpointStore(store CheckpointStore) ObserverOption {
+	return func(o *Observer) {
+		o.checkpoints = store
+	}
+}
+
+// WithObserverOnCheckpoint configures a callback invoked each time the observer has persisted a
+// new last indexed round in its checkpoint store.
+//
+// The callback is invoked synchronously from the indexing loop after the checkpoint has been
+// successfully stored and should not block.
+func WithObserverOnCheckpoint(fn func(round uint64)) ObserverOption {
+	return func(o *Observer) {
+		o.onCheckpoint = fn
+	}
+}
+
+// WithObserverLogger configures the logger used by the observer.
+//
+// By default a logger for the "bridge" module is used.
+func WithObserverLogger(l *logging.Logger) ObserverOption {
+	return func(o *Observer) {
+		o.logger = l
+	}
+}
+
+// LockStatus is the status of a lock tracked by an observer.
+type LockStatus struct {
+	// Round is the round in which the lock was made.
+	Round uint64 `json:"round"`
+
+	// Lock is the lock event.
+	Lock *LockEvent `json:"lock"`
+
+	// Signed is the witnesses signed event emitted once enough witnesses signed the lock or nil
+	// in case the lock is still awaiting signatures.
+	Signed *WitnessesSignedEvent `json:"signed,omitempty"`
+
+	// SignedRound is the round in which enough witnesses signed the lock.
+	SignedRound uint64 `json:"signed_round,omitempty"`
+}
+
+// IsPending returns true iff the lock is still awaiting witness signatures.
+func (s *LockStatus) IsPending() bool {
+	return s.Signed == nil
+}
+
+// Observer is a read-only bridge observer. It follows the events emitted by the bridge module
+// and maintains an in-memory index of locks and releases without requiring any keys.
+type Observer struct {
+	rc     client.RuntimeClient
+	bridge V1
+
+	checkpoints  CheckpointStore
+	onCheckpoint func(round uint64)
+
+	logger *logging.Logger
+
+	indexLock      sync.RWMutex
+	locks          map[uint64]*LockStatus
+	releases       map[uint64]*ReleaseEvent
+	lastRound      uint64
+	lastRoundValid bool
+}
+
+// Lock returns the status of the lock with the given identifier.
+func (o *Observer) Lock(id uint64) (*LockStatus, bool) {
+	o.indexLock.RLock()
+	defer o.indexLock.RUnlock()
+
+	status, ok := o.locks[id]
+	if !ok {
+		return nil, false
+	}
+	s := *status
+	return &s, true
+}
+
+// PendingLocks returns all locks awaiting witness signatures, ordered by identifier.
+func (o *Observer) PendingLocks() []*LockStatus {
+	o.indexLock.RLock()
+	defer o.indexLock.RUnlock()
+
+	var pending []*LockStatus
+	for _, status := range o.locks {
+		if status.IsPending() {
+			s := *status
+			pending = append(pending, &s)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Lock.ID < pending[j].Lock.ID
+	})
+	return pending
+}
+
+// Release returns the release event with the given incoming sequence number.
+func (o *Observer) Release(id uint64) (*ReleaseEvent, bool) {
+	o.indexLock.RLock()
+	defer o.indexLock.RUnlock()
+
+	release, ok := o.releases[id]
+	return release, ok
+}
+
+// LastProcessedRound returns the last round that has been fully indexed and true, or false if no
+// round has been indexed yet.
+func (o *Observer) LastProcessedRound() (uint64, bool) {
+	o.indexLock.RLock()
+	defer o.indexLock.RUnlock()
+
+	return o.lastRound, o.lastRoundValid
+}
+
+func (o *Observer) index(ev *Event) {
+	o.indexLock.Lock()
+	defer o.indexLock.Unlock()
+
+	switch {
+	case ev.Lock != nil:
+		if _, ok := o.locks[ev.Lock.ID]; !ok {
+			o.locks[ev.Lock.ID] = &LockStatus{
+				Round: ev.Round,
+				Lock:  ev.Lock,
+			}
+		}
+	case ev.WitnessesSigned != nil:
+		if status, ok := o.locks[ev.WitnessesSigned.ID]; ok && ev.WitnessesSigned.IsLock() {
+			status.Signed = ev.WitnessesSigned
+			status.SignedRound = ev.Round
+		}
+	case ev.Release != nil:
+		o.releases[ev.Release.ID] = ev.Release
+	}
+}
+
+func (o *Observer) completeRound(round uint64) error {
+	if err := o.checkpoints.Store(round); err != nil {
+		return fmt.Errorf("bridge: failed to store checkpoint: %w", err)
+	}
+
+	o.indexLock.Lock()
+	o.lastRound = round
+	o.lastRoundValid = true
+	o.indexLock.Unlock()
+
+	if o.onCheckpoint != nil {
+		o.onCheckpoint(round)
+	}
+	return nil
+}
+
+// Run indexes bridge events until the context is canceled or a fatal error occurs.
+//
+// Indexing starts with the runtime genesis round or, if a checkpoint is available, with the
+// round following it. Note that in the latter case the index only contains events emitted after
+// the checkpoint. The checkpoint advances with every indexed round, including rounds without any
+// bridge events.
+func (o *Observer) Run(ctx context.Context) error {
+	// Subscribe before backfilling so that no rounds are missed in between.
+	blkCh, blkSub, err := o.bridge.WatchBlocks(ctx)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to subscribe to runtime blocks: %w", err)
+	}
+	defer blkSub.Close()
+
+	var start uint64
+	lastRound, err := o.checkpoints.Load()
+	switch {
+	case err == nil:
+		o.logger.Info("resuming from checkpoint",
+			"round", lastRound,
+		)
+		start = lastRound + 1
+
+		o.indexLock.Lock()
+		o.lastRound = lastRound
+		o.lastRoundValid = true
+		o.indexLock.Unlock()
+	case errors.Is(err, ErrNoCheckpoint):
+		genesis, gerr := o.rc.GetGenesisBlock(ctx)
+		if gerr != nil {
+			return fmt.Errorf("bridge: failed to fetch genesis block: %w", gerr)
+		}
+		start = genesis.Header.Round
+	default:
+		return err
+	}
+
+	// Backfill up to the latest round.
+	latest, err := o.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to fetch latest block: %w", err)
+	}
+	backfilled := latest.Header.Round
+	for from := start; from <= backfilled; from += defaultScanChunkSize {
+		to := from + defaultScanChunkSize - 1
+		if to > backfilled {
+			to = backfilled
+		}
+		events, err := o.bridge.GetEventsRange(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		for r := from; r <= to; r++ {
+			for _, ev := range events[r] {
+				o.index(ev)
+			}
+		}
+		if err = o.completeRound(to); err != nil {
+			return err
+		}
+	}
+
+	// Tail new rounds, indexing any rounds missed since the last indexed one. The checkpoint may
+	// be ahead of the latest round in case the node lags behind.
+	last := backfilled
+	if start > last+1 {
+		last = start - 1
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case blk, ok := <-blkCh:
+			if !ok {
+				return blkSub.Err()
+			}
+			for r := last + 1; r <= blk.Block.Header.Round; r++ {
+				events, err := o.bridge.GetEvents(ctx, r)
+				if err != nil {
+					return err
+				}
+				for _, ev := range events {
+					o.index(ev)
+				}
+				if err = o.completeRound(r); err != nil {
+					return err
+				}
+				last = r
+			}
+		}
+	}
+}
+
+// NewObserver creates a new bridge observer.
+func NewObserver(rc client.RuntimeClient, opts ...ObserverOption) *Observer {
+	o := &Observer{
+		rc:          rc,
+		checkpoints: NewMemoryCheckpointStore(),
+		logger:      logger,
+		locks:       make(map[uint64]*LockStatus),
+		releases:    make(map[uint64]*ReleaseEvent),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.bridge = NewV1(rc, WithLogger(o.logger))
+	o.logger = o.logger.With("side", "observer")
+	return o
+}
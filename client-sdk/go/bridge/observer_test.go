@@ -0,0 +1,200 @@
+package bridge
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// observerClient is a runtime client producing blocks on request whose rounds contain the
+// configured lock events, recording the rounds whose events are fetched.
+type observerClient struct {
+	watchClient
+
+	genesis uint64
+	latest  uint64
+	// locks maps rounds to the identifier of the lock made in them.
+	locks map[uint64]uint64
+
+	fetchLock sync.Mutex
+	fetched   []uint64
+}
+
+func (rc *observerClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.genesis
+	return &blk, nil
+}
+
+func (rc *observerClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *observerClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.fetchLock.Lock()
+	rc.fetched = append(rc.fetched, round)
+	rc.fetchLock.Unlock()
+
+	id, ok := rc.locks[round]
+	if !ok {
+		return nil, nil
+	}
+	return []*coreClient.Event{{
+		Key: LockEventKey,
+		Value: cbor.Marshal(&LockEvent{
+			ID:     id,
+			Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+		}),
+	}}, nil
+}
+
+// takeFetched returns the sorted rounds whose events have been fetched since the last call.
+func (rc *observerClient) takeFetched() []uint64 {
+	rc.fetchLock.Lock()
+	defer rc.fetchLock.Unlock()
+
+	fetched := rc.fetched
+	rc.fetched = nil
+	sort.Slice(fetched, func(i, j int) bool { return fetched[i] < fetched[j] })
+	return fetched
+}
+
+// runObserver runs the given observer until the test completes, returning the channel the
+// checkpointed rounds are sent to.
+func runObserver(t *testing.T, rc *observerClient, opts ...ObserverOption) (*Observer, <-chan uint64) {
+	checkpointCh := make(chan uint64, 16)
+	opts = append(opts, WithObserverOnCheckpoint(func(round uint64) {
+		checkpointCh <- round
+	}))
+	o := NewObserver(rc, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- o.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-runErr; err != context.Canceled {
+			t.Errorf("Run: %v", err)
+		}
+	})
+	return o, checkpointCh
+}
+
+// expectCheckpoints waits for the given rounds to be checkpointed in order.
+func expectCheckpoints(t *testing.T, checkpointCh <-chan uint64, rounds ...uint64) {
+	for _, expected := range rounds {
+		select {
+		case round := <-checkpointCh:
+			if round != expected {
+				t.Fatalf("expected checkpoint of round %d, got %d", expected, round)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for checkpoint of round %d", expected)
+		}
+	}
+	select {
+	case round := <-checkpointCh:
+		t.Fatalf("unexpected checkpoint of round %d", round)
+	default:
+	}
+}
+
+func TestObserverBackfill(t *testing.T) {
+	rc := &observerClient{
+		genesis: 1,
+		latest:  250,
+		locks:   map[uint64]uint64{1: 1, 100: 2, 101: 3, 250: 4},
+	}
+	o, checkpointCh := runObserver(t, rc)
+
+	// Rounds are backfilled in chunks, checkpointing the last round of each chunk.
+	expectCheckpoints(t, checkpointCh, 100, 200, 250)
+	fetched := rc.takeFetched()
+	if len(fetched) != 250 || fetched[0] != 1 || fetched[249] != 250 {
+		t.Fatalf("expected rounds 1 to 250 to be fetched once, got %d rounds", len(fetched))
+	}
+	for i := 1; i < len(fetched); i++ {
+		if fetched[i] != fetched[i-1]+1 {
+			t.Fatalf("expected rounds 1 to 250 to be fetched once, got round %d after %d", fetched[i], fetched[i-1])
+		}
+	}
+	for round, id := range rc.locks {
+		if status, ok := o.Lock(id); !ok || status.Round != round {
+			t.Fatalf("expected lock %d to be indexed at round %d, got %+v", id, round, status)
+		}
+	}
+	if round, ok := o.LastProcessedRound(); !ok || round != 250 {
+		t.Fatalf("expected last processed round 250, got %d (ok: %t)", round, ok)
+	}
+}
+
+func TestObserverTail(t *testing.T) {
+	rc := &observerClient{
+		genesis: 1,
+		latest:  3,
+		locks:   map[uint64]uint64{2: 1, 5: 2},
+	}
+	o, checkpointCh := runObserver(t, rc)
+	expectCheckpoints(t, checkpointCh, 3)
+	rc.takeFetched()
+
+	// Rounds already backfilled are not indexed again.
+	rc.produce(3)
+	// Rounds without events advance the checkpoint and missed rounds are caught up with.
+	rc.produce(4)
+	expectCheckpoints(t, checkpointCh, 4)
+	rc.produce(6)
+	expectCheckpoints(t, checkpointCh, 5, 6)
+
+	if expected := []uint64{4, 5, 6}; !reflect.DeepEqual(rc.takeFetched(), expected) {
+		t.Fatalf("expected rounds %v to be fetched after backfilling", expected)
+	}
+	for round, id := range rc.locks {
+		if status, ok := o.Lock(id); !ok || status.Round != round {
+			t.Fatalf("expected lock %d to be indexed at round %d, got %+v", id, round, status)
+		}
+	}
+}
+
+func TestObserverResume(t *testing.T) {
+	checkpoints := NewMemoryCheckpointStore()
+	if err := checkpoints.Store(5); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+	// The node lags behind the checkpoint.
+	rc := &observerClient{
+		genesis: 1,
+		latest:  3,
+		locks:   map[uint64]uint64{4: 1, 6: 2},
+	}
+	o, checkpointCh := runObserver(t, rc, WithObserverCheckpointStore(checkpoints))
+
+	rc.produce(4)
+	rc.produce(6)
+	expectCheckpoints(t, checkpointCh, 6)
+
+	if expected := []uint64{6}; !reflect.DeepEqual(rc.takeFetched(), expected) {
+		t.Fatalf("expected only rounds after the checkpoint to be fetched")
+	}
+	if _, ok := o.Lock(1); ok {
+		t.Fatalf("expected lock made before the checkpoint not to be indexed")
+	}
+	if _, ok := o.Lock(2); !ok {
+		t.Fatalf("expected lock made after the checkpoint to be indexed")
+	}
+}
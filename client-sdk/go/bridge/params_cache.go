@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Results of parameters cache lookups (see WithParametersCache).
+const (
+	parametersCacheHit  = "hit"
+	parametersCacheMiss = "miss"
+)
+
+type parametersCacheEntry struct {
+	round  uint64
+	params *Parameters
+}
+
+// parametersCache is a bounded cache of the bridge parameters by round which evicts the least
+// recently used round once full.
+type parametersCache struct {
+	sync.Mutex
+
+	size    int
+	order   *list.List
+	entries map[uint64]*list.Element
+}
+
+func newParametersCache(size int) *parametersCache {
+	return &parametersCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[uint64]*list.Element),
+	}
+}
+
+func (c *parametersCache) get(round uint64) (*Parameters, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[round]
+	if !ok {
+		parametersCacheLookups.WithLabelValues(parametersCacheMiss).Inc()
+		return nil, false
+	}
+	parametersCacheLookups.WithLabelValues(parametersCacheHit).Inc()
+	c.order.MoveToFront(elem)
+	return elem.Value.(*parametersCacheEntry).params, true
+}
+
+func (c *parametersCache) put(round uint64, params *Parameters) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[round]; ok {
+		elem.Value.(*parametersCacheEntry).params = params
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[round] = c.order.PushFront(&parametersCacheEntry{
+		round:  round,
+		params: params,
+	})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parametersCacheEntry).round)
+	}
+}
+
+// WithParametersCache configures the bridge module client to cache the parameters of up to the
+// given number of rounds, evicting the least recently used round once full, so that helpers
+// looking up the parameters for every event of a round only query them once.
+//
+// The runtime does not emit an event when the parameters change, so parameters are cached by
+// round, which is safe as the parameters at a given round never change. Queries for the latest
+// round are resolved to a concrete round first (see WithRoundStrategy). Cached parameters are
+// shared between callers and must not be modified.
+//
+// Cache hits and misses are counted by the oasis_bridge_parameters_cache_lookups metric. By
+// default parameters are not cached.
+func WithParametersCache(size int) V1Option {
+	return func(a *v1) {
+		if size <= 0 {
+			a.paramsCache = nil
+			return
+		}
+		initMetrics()
+		a.paramsCache = newParametersCache(size)
+	}
+}
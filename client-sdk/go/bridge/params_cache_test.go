@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// paramsClient is a runtime client whose parameters at round n have a threshold of n and which
+// counts parameter queries.
+type paramsClient struct {
+	client.RuntimeClient
+
+	queries int
+}
+
+func (rc *paramsClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	rc.queries++
+	*rsp.(*Parameters) = Parameters{Threshold: round}
+	return nil
+}
+
+func TestParametersCache(t *testing.T) {
+	ctx := context.Background()
+	rc := &paramsClient{}
+	v := NewV1(rc, WithParametersCache(2))
+
+	hits := testutil.ToFloat64(parametersCacheLookups.WithLabelValues(parametersCacheHit))
+	misses := testutil.ToFloat64(parametersCacheLookups.WithLabelValues(parametersCacheMiss))
+
+	for _, tc := range []struct {
+		round   uint64
+		queries int
+	}{
+		{1, 1},
+		{1, 1},
+		{2, 2},
+		{1, 2},
+		// Round 2 is the least recently used round and is evicted.
+		{3, 3},
+		{1, 3},
+		{2, 4},
+	} {
+		params, err := v.Parameters(ctx, tc.round)
+		if err != nil {
+			t.Fatalf("Parameters(%d): %s", tc.round, err)
+		}
+		if params.Threshold != tc.round {
+			t.Fatalf("expected parameters of round %d, got those of round %d", tc.round, params.Threshold)
+		}
+		if rc.queries != tc.queries {
+			t.Fatalf("expected %d queries after round %d, got %d", tc.queries, tc.round, rc.queries)
+		}
+	}
+
+	if d := testutil.ToFloat64(parametersCacheLookups.WithLabelValues(parametersCacheHit)) - hits; d != 3 {
+		t.Fatalf("expected 3 cache hits, got %v", d)
+	}
+	if d := testutil.ToFloat64(parametersCacheLookups.WithLabelValues(parametersCacheMiss)) - misses; d != 4 {
+		t.Fatalf("expected 4 cache misses, got %v", d)
+	}
+
+	// Without a cache all lookups are queried.
+	rc = &paramsClient{}
+	v = NewV1(rc)
+	for i := 0; i < 3; i++ {
+		if _, err := v.Parameters(ctx, 1); err != nil {
+			t.Fatalf("Parameters: %s", err)
+		}
+	}
+	if rc.queries != 3 {
+		t.Fatalf("expected 3 queries without a cache, got %d", rc.queries)
+	}
+}
@@ -0,0 +1,64 @@
+package bridge
+
+import (
+	"context"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrInvalidExternalSignature is the error returned when an externally produced transaction
+// signature does not verify.
+var ErrInvalidExternalSignature = errors.New("bridge: invalid external signature")
+
+// AssembleFunc assembles a signed transaction from a signature produced by an external signer
+// over the preimage returned together with it.
+type AssembleFunc func(sig []byte) (types.UnverifiedTransaction, error)
+
+// Implements V1.
+func (a *v1) PrepareWitness(
+	ctx context.Context,
+	pk signature.PublicKey,
+	body *Witness,
+	opts ...SubmitOption,
+) ([]byte, AssembleFunc, error) {
+	if _, ok := pk.(ed25519.PublicKey); !ok {
+		return nil, nil, fmt.Errorf("bridge: unsupported external signer public key type %T", pk)
+	}
+
+	o := newSubmitOptions(opts...)
+
+	method := a.method(methodWitness)
+	tx, chainContext, err := prepareTx(ctx, a, pk, method, body, o)
+	if err != nil {
+		if o.nonce != nil {
+			o.nonce.Reset()
+		}
+		return nil, nil, err
+	}
+
+	ut := tx.PrepareForSigning().UnverifiedTransaction()
+	sigCtx := chainContext.New(types.SignatureContextBase)
+
+	// Ed25519 signers sign the SHA-512/256 digest of the domain separation context and the
+	// message, so this is what a plain Ed25519 signer (e.g., an HSM) needs to be handed.
+	h := sha512.New512_256()
+	_, _ = h.Write(sigCtx)
+	_, _ = h.Write(ut.Body)
+	preimage := h.Sum(nil)
+
+	assemble := func(sig []byte) (types.UnverifiedTransaction, error) {
+		if !pk.Verify(sigCtx, ut.Body, sig) {
+			return types.UnverifiedTransaction{}, fmt.Errorf("%s: %w", method, ErrInvalidExternalSignature)
+		}
+		return types.UnverifiedTransaction{
+			Body:       ut.Body,
+			AuthProofs: []types.AuthProof{{Signature: sig}},
+		}, nil
+	}
+	return preimage, assemble, nil
+}
@@ -0,0 +1,86 @@
+package bridge
+
+import (
+	"context"
+	stdEd25519 "crypto/ed25519"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// prepareClient is a runtime client for preparing transactions of a runtime with the given chain
+// context.
+type prepareClient struct {
+	watchClient
+
+	chainContext signature.Context
+}
+
+func (rc *prepareClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: rc.chainContext}, nil
+}
+
+func (rc *prepareClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = Parameters{LocalDenominations: []types.Denomination{types.NativeDenomination}}
+	case "accounts.Nonce", "core.EstimateGas":
+		*rsp.(*uint64) = 0
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func TestPrepareWitness(t *testing.T) {
+	ctx := context.Background()
+	chainContext := signature.Context("test")
+
+	rawPk, privateKey, err := stdEd25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	var pk ed25519.PublicKey
+	if err = pk.UnmarshalBinary(rawPk); err != nil {
+		t.Fatalf("failed to unmarshal public key: %s", err)
+	}
+	body := &Witness{ID: 7, Signature: []byte("signature")}
+
+	// The preimage is signed with plain Ed25519 as an external signer would.
+	preimage, assemble, err := NewV1(&prepareClient{chainContext: chainContext}).PrepareWitness(ctx, pk, body)
+	if err != nil {
+		t.Fatalf("PrepareWitness: %s", err)
+	}
+	utx, err := assemble(stdEd25519.Sign(privateKey, preimage))
+	if err != nil {
+		t.Fatalf("failed to assemble transaction: %s", err)
+	}
+
+	// The transaction verifies the way the runtime verifies it.
+	tx, err := utx.Verify(chainContext)
+	if err != nil {
+		t.Fatalf("failed to verify transaction: %s", err)
+	}
+	if tx.Call.Method != ModuleName+"."+methodWitness {
+		t.Fatalf("unexpected method: %s", tx.Call.Method)
+	}
+	if len(tx.AuthInfo.SignerInfo) != 1 || !tx.AuthInfo.SignerInfo[0].AddressSpec.Signature.Equal(pk) {
+		t.Fatalf("unexpected signers: %+v", tx.AuthInfo.SignerInfo)
+	}
+	if _, err = utx.Verify("other"); err == nil {
+		t.Fatalf("transaction verified under another chain context")
+	}
+
+	// A signature over the preimage of another runtime is rejected.
+	otherPreimage, _, err := NewV1(&prepareClient{chainContext: "other"}).PrepareWitness(ctx, pk, body)
+	if err != nil {
+		t.Fatalf("PrepareWitness: %s", err)
+	}
+	if _, err = assemble(stdEd25519.Sign(privateKey, otherPreimage)); !errors.Is(err, ErrInvalidExternalSignature) {
+		t.Fatalf("expected ErrInvalidExternalSignature, got %v", err)
+	}
+}
@@ -0,0 +1,858 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+const (
+	// MaxSubmitJitter is the maximum delay that can be configured via WithSubmitJitter.
+	MaxSubmitJitter = 5 * time.Second
+
+	// DefaultParametersRefreshInterval is the default interval at which the event processor
+	// queries the bridge parameters to detect changes (see WithParametersRefreshInterval).
+	DefaultParametersRefreshInterval = time.Minute
+)
+
+// ProcessorOption is an option for configuring an event processor.
+type ProcessorOption func(p *EventProcessor)
+
+// WithDryRun configures whether the event processor runs in dry-run mode.
+//
+// In dry-run mode lock events are processed and signed as usual, but instead of submitting the
+// bridge.Witness transactions the processor only logs what it would have submitted. Progress is
+// persisted in the store configured via WithDryRunCheckpointStore so that a dry run can be
+// resumed without affecting the checkpoint of a live witness.
+func WithDryRun(enabled bool) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.dryRun = enabled
+	}
+}
+
+// WithCheckpointStore configures the store used to persist processing progress.
+//
+// By default progress is only kept in memory.
+func WithCheckpointStore(store CheckpointStore) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.checkpoints = store
+	}
+}
+
+// WithDryRunCheckpointStore configures the store used to persist processing progress while in
+// dry-run mode.
+//
+// By default progress is only kept in memory.
+func WithDryRunCheckpointStore(store CheckpointStore) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.dryRunCheckpoints = store
+	}
+}
+
+// WithMinFeeBalance configures the minimum balance (in the fee denomination) that witness
+// accounts should hold. Before witnessing a lock, a warning is emitted in case the balance of the
+// witness account is below the minimum or does not cover the estimated transaction fee.
+//
+// By default a warning is only emitted when the balance does not cover the estimated fee.
+func WithMinFeeBalance(q quantity.Quantity) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.minFeeBalance = q.Clone()
+	}
+}
+
+// WithOnWitnessed configures a callback invoked each time a bridge.Witness transaction for a lock
+// has been successfully executed on behalf of one of the managed witnesses.
+//
+// The callback is invoked synchronously from the processing loop and should not block. As locks
+// are witnessed concurrently on behalf of different witnesses, it must be safe for concurrent use.
+// It is not invoked in dry-run mode or for locks that have already been witnessed.
+func WithOnWitnessed(fn func(ev *LockEvent, txHash hash.Hash)) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.onWitnessed = fn
+	}
+}
+
+// WithOnCheckpoint configures a callback invoked each time the event processor has persisted a
+// new last processed round in its checkpoint store (e.g., to export the processing lag).
+//
+// The callback is invoked synchronously from the processing loop after the checkpoint has been
+// successfully stored and should not block. It is also invoked when the checkpoint is moved back
+// after a reorganization.
+func WithOnCheckpoint(fn func(round uint64)) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.onCheckpoint = fn
+	}
+}
+
+// WithOnParametersChanged configures a callback invoked each time the event processor observes
+// bridge parameters that differ from the previously observed ones (e.g., after a witness rotation
+// or a threshold change), together with the round at which the new parameters were observed.
+//
+// The callback is invoked synchronously from the processing loop and should not block. It is not
+// invoked for the first observed parameters.
+func WithOnParametersChanged(fn func(round uint64, old, new *Parameters)) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.onParamsChanged = fn
+	}
+}
+
+// WithParametersRefreshInterval configures how often the event processor queries the bridge
+// parameters at the latest seen round to detect changes (see WithOnParametersChanged).
+//
+// The bridge module does not emit an event when its parameters change, so in addition to the
+// parameters queried while witnessing locks they are periodically queried so that witness
+// rotations are noticed even while no locks are witnessed. Zero disables the periodic queries. By
+// default DefaultParametersRefreshInterval is used.
+func WithParametersRefreshInterval(d time.Duration) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.paramsRefresh = d
+	}
+}
+
+// WithProcessorLogger configures the logger used by the event processor.
+//
+// By default a logger for the "bridge" module is used.
+func WithProcessorLogger(l *logging.Logger) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.logger = l
+	}
+}
+
+// WithSubmitJitter configures the event processor to delay the submission of each witness
+// transaction by a random duration of up to the given maximum so that witnesses sharing
+// infrastructure do not all submit at the same instant.
+//
+// The maximum is capped at MaxSubmitJitter so that witnessing is not meaningfully delayed. By
+// default transactions are submitted without delay.
+func WithSubmitJitter(max time.Duration) ProcessorOption {
+	return func(p *EventProcessor) {
+		if max > MaxSubmitJitter {
+			max = MaxSubmitJitter
+		}
+		p.submitJitter = max
+	}
+}
+
+// WithProcessorBridgeOptions configures additional options for the bridge module client used by
+// the event processor (see NewV1).
+func WithProcessorBridgeOptions(opts ...V1Option) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.bridgeOpts = append(p.bridgeOpts, opts...)
+	}
+}
+
+// WithGetEventsRetryPolicy configures how fetching the events of a round is retried after it
+// timed out (see WithGetEventsTimeout). Other errors are not retried.
+//
+// By default DefaultResubscribePolicy is used.
+func WithGetEventsRetryPolicy(policy ResubscribePolicy) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.getEventsRetry = policy
+	}
+}
+
+// WithProcessorClock configures the clock used by the event processor.
+//
+// By default RealClock is used.
+func WithProcessorClock(clock Clock) ProcessorOption {
+	return func(p *EventProcessor) {
+		p.clock = clock
+	}
+}
+
+// WitnessIdentity is an identity of a witness managed by the event processor.
+type WitnessIdentity struct {
+	// Signer is the signer used to sign bridge.Witness transactions. Its public key must be one
+	// of the authorized witnesses.
+	Signer signature.Signer
+
+	// WitnessSigner produces the witness signatures submitted in bridge.Witness transactions.
+	WitnessSigner WitnessSigner
+}
+
+type processorWitness struct {
+	WitnessIdentity
+
+	address types.Address
+	nonces  *NonceManager
+	logger  *logging.Logger
+
+	// submitLock serializes the submissions of all witness identities sharing an account so that
+	// their transactions are submitted in nonce order.
+	submitLock *sync.Mutex
+
+	// index and authorized are the index of the witness in the list of authorized witnesses and
+	// whether it is authorized according to the last observed parameters.
+	index      uint16
+	authorized bool
+}
+
+// EventProcessor is a witness event processor. It follows the events emitted by the bridge
+// module and for each observed lock submits a bridge.Witness transaction on behalf of every
+// managed witness identity that is authorized at the time.
+//
+// Each witness account uses its own nonce sequence. Submissions on behalf of different accounts
+// proceed concurrently while submissions on behalf of the same account are serialized.
+//
+// The processor is the event cursor of the witness: it persists the last fully processed round
+// in its checkpoint store and resumes after it. It can be shut down gracefully via Stop, which
+// drains the round being processed instead of aborting it like canceling the context does.
+type EventProcessor struct {
+	rc         client.RuntimeClient
+	bridge     V1
+	bridgeOpts []V1Option
+	accounts   accounts.V1
+	witnesses  []*processorWitness
+
+	dryRun            bool
+	checkpoints       CheckpointStore
+	dryRunCheckpoints CheckpointStore
+	minFeeBalance     *quantity.Quantity
+	onWitnessed       func(ev *LockEvent, txHash hash.Hash)
+	onCheckpoint      func(round uint64)
+	onParamsChanged   func(round uint64, old, new *Parameters)
+	paramsRefresh     time.Duration
+	submitJitter      time.Duration
+	getEventsRetry    ResubscribePolicy
+	clock             Clock
+	rngLock           sync.Mutex
+	rng               *rand.Rand
+
+	logger *logging.Logger
+
+	statusLock     sync.RWMutex
+	lastRound      uint64
+	lastRoundValid bool
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	runDone  chan struct{}
+
+	// params and paramsRound are the last observed parameters and the round they were observed
+	// at. They are only accessed from the processing loop.
+	params      *Parameters
+	paramsRound uint64
+}
+
+// LastProcessedRound returns the last round that has been fully processed and true, or false if
+// no round has been processed yet.
+func (p *EventProcessor) LastProcessedRound() (uint64, bool) {
+	p.statusLock.RLock()
+	defer p.statusLock.RUnlock()
+
+	return p.lastRound, p.lastRoundValid
+}
+
+func (p *EventProcessor) setLastProcessedRound(round uint64) {
+	p.statusLock.Lock()
+	defer p.statusLock.Unlock()
+
+	p.lastRound = round
+	p.lastRoundValid = true
+}
+
+// Stop stops the event processor gracefully: no further rounds are processed, the round being
+// processed (if any) is finished and its checkpoint persisted, after which Run returns nil.
+//
+// Stop waits until Run has returned so that no events are processed after Stop returns, and the
+// checkpoint then reflects the last fully processed round. If the context is canceled before
+// then, Stop returns the context error without waiting any further, in which case the context
+// passed to Run should be canceled to abort processing. Stopping a processor that is not running
+// makes subsequent calls to Run return immediately.
+func (p *EventProcessor) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+
+	p.statusLock.RLock()
+	done := p.runDone
+	p.statusLock.RUnlock()
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// stopping returns true iff the event processor has been stopped via Stop.
+func (p *EventProcessor) stopping() bool {
+	select {
+	case <-p.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run processes bridge events until the context is canceled, the processor is stopped via Stop
+// or a fatal error occurs. It returns nil once the processor has been stopped.
+//
+// If a checkpoint is available, processing resumes with the round following it.
+func (p *EventProcessor) Run(ctx context.Context) error {
+	done := p.startRun()
+	defer close(done)
+
+	if p.stopping() {
+		return nil
+	}
+
+	checkpoints := p.checkpointStore()
+	lastRound, resume, err := p.loadCheckpoint(checkpoints)
+	if err != nil {
+		return err
+	}
+
+	// Reorganizations are reported before the block of the round the runtime went back to is
+	// delivered so that the affected rounds can be processed again. The handler is invoked by
+	// the subscription worker, so it must give up once Run returns as closing the subscription
+	// waits for the worker to terminate.
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	reorgCh := make(chan *ReorgEvent)
+	onReorg := func(ev *ReorgEvent) {
+		select {
+		case <-watchCtx.Done():
+		case reorgCh <- ev:
+		}
+	}
+	blkCh, blkSub, err := p.bridge.WatchBlocks(watchCtx, WithReorgHandler(onReorg))
+	if err != nil {
+		cancelWatch()
+		return fmt.Errorf("bridge: failed to subscribe to runtime blocks: %w", err)
+	}
+	defer func() {
+		cancelWatch()
+		blkSub.Close()
+	}()
+
+	var nextParamsRefresh time.Time
+	for {
+		// Give stopping precedence over pending blocks.
+		if p.stopping() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.stopCh:
+			return nil
+		case ev := <-reorgCh:
+			if !resume || ev.ToRound > lastRound {
+				continue
+			}
+			p.logger.Warn("reprocessing rounds after reorganization",
+				"from_round", ev.FromRound,
+				"to_round", ev.ToRound,
+			)
+			if ev.ToRound == 0 {
+				resume = false
+				continue
+			}
+			lastRound = ev.ToRound - 1
+			if err = p.storeCheckpoint(checkpoints, lastRound); err != nil {
+				return err
+			}
+		case blk, ok := <-blkCh:
+			if !ok {
+				return blkSub.Err()
+			}
+			round := blk.Block.Header.Round
+			p.logger.Debug("seen new block",
+				"round", round,
+			)
+
+			// Process any rounds that have been missed since the last checkpoint.
+			start := round
+			if resume {
+				if round <= lastRound {
+					continue
+				}
+				start = lastRound + 1
+			}
+			for r := start; r <= round; r++ {
+				if p.stopping() {
+					return nil
+				}
+				if err = p.processRound(ctx, r); err != nil {
+					return err
+				}
+				if err = p.storeCheckpoint(checkpoints, r); err != nil {
+					return err
+				}
+			}
+			lastRound = round
+			resume = true
+
+			if p.paramsRefresh > 0 && !p.clock.Now().Before(nextParamsRefresh) {
+				p.refreshParameters(ctx, round)
+				nextParamsRefresh = p.clock.Now().Add(p.paramsRefresh)
+			}
+		}
+	}
+}
+
+// RunOnce processes the rounds from the one following the checkpoint up to the latest round and
+// then returns instead of waiting for new rounds, e.g. for batch jobs run periodically. Once the
+// latest round has been processed, the latest round is queried again and processing continues
+// until no new rounds have been produced in the meantime, so that no events produced during the
+// run are missed.
+//
+// It returns the last processed round, which is persisted in the checkpoint store. Without a
+// checkpoint, processing starts with the latest round as in Run. In case the processor is
+// stopped via Stop, RunOnce returns the last round processed until then and a nil error.
+//
+// Unlike Run, RunOnce does not follow reorganizations while running.
+func (p *EventProcessor) RunOnce(ctx context.Context) (uint64, error) {
+	done := p.startRun()
+	defer close(done)
+
+	checkpoints := p.checkpointStore()
+	lastRound, resume, err := p.loadCheckpoint(checkpoints)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		if p.stopping() {
+			return lastRound, nil
+		}
+		blk, err := p.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return lastRound, fmt.Errorf("bridge: failed to fetch latest block: %w", err)
+		}
+		head := blk.Header.Round
+
+		start := head
+		if resume {
+			if head <= lastRound {
+				p.logger.Info("caught up with the latest round",
+					"round", lastRound,
+				)
+				return lastRound, nil
+			}
+			start = lastRound + 1
+		}
+		for r := start; r <= head; r++ {
+			if p.stopping() {
+				return lastRound, nil
+			}
+			if err = p.processRound(ctx, r); err != nil {
+				return lastRound, err
+			}
+			if err = p.storeCheckpoint(checkpoints, r); err != nil {
+				return lastRound, err
+			}
+			lastRound = r
+			resume = true
+		}
+	}
+}
+
+// startRun records that the processor is running and returns the channel to close once it is
+// done (see Stop).
+func (p *EventProcessor) startRun() chan struct{} {
+	done := make(chan struct{})
+	p.statusLock.Lock()
+	p.runDone = done
+	p.statusLock.Unlock()
+	return done
+}
+
+// loadCheckpoint loads the last processed round from the given checkpoint store, returning false
+// if no checkpoint has been stored yet.
+func (p *EventProcessor) loadCheckpoint(checkpoints CheckpointStore) (uint64, bool, error) {
+	lastRound, err := checkpoints.Load()
+	switch {
+	case err == nil:
+		p.logger.Info("resuming from checkpoint",
+			"round", lastRound,
+		)
+		return lastRound, true, nil
+	case errors.Is(err, ErrNoCheckpoint):
+		return 0, false, nil
+	default:
+		return 0, false, err
+	}
+}
+
+// storeCheckpoint persists the given round as the last processed round.
+func (p *EventProcessor) storeCheckpoint(checkpoints CheckpointStore, round uint64) error {
+	if err := checkpoints.Store(round); err != nil {
+		return err
+	}
+	p.setLastProcessedRound(round)
+	if p.onCheckpoint != nil {
+		p.onCheckpoint(round)
+	}
+	return nil
+}
+
+func (p *EventProcessor) checkpointStore() CheckpointStore {
+	if p.dryRun {
+		return p.dryRunCheckpoints
+	}
+	return p.checkpoints
+}
+
+// getEvents fetches the events of the given round, retrying calls that timed out.
+func (p *EventProcessor) getEvents(ctx context.Context, round uint64) ([]*Event, error) {
+	var events []*Event
+	err := backoff.RetryNotifyWithTimer(func() error {
+		var err error
+		events, err = p.bridge.GetEvents(ctx, round)
+		if err != nil && !errors.Is(err, ErrGetEventsTimeout) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, p.getEventsRetry.backOff(ctx, p.clock), func(err error, d time.Duration) {
+		p.logger.Warn("fetching events timed out, retrying",
+			"err", err,
+			"round", round,
+			"retry_in", d,
+		)
+	}, &backoffTimer{clock: p.clock})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (p *EventProcessor) processRound(ctx context.Context, round uint64) error {
+	events, err := p.getEvents(ctx, round)
+	if err != nil {
+		return err
+	}
+
+	var locks []*LockEvent
+	for _, ev := range events {
+		if ev.Lock == nil {
+			continue
+		}
+		p.logger.Debug("got lock event",
+			"id", ev.Lock.ID,
+			"owner", ev.Lock.Owner,
+			"target", ev.Lock.Target,
+			"amount", ev.Lock.Amount,
+		)
+		locks = append(locks, ev.Lock)
+	}
+	if len(locks) == 0 {
+		return nil
+	}
+
+	params, err := p.bridge.Parameters(ctx, round)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to query parameters at round %d: %w", round, err)
+	}
+	if err = params.ValidateBasic(); err != nil {
+		return fmt.Errorf("bridge: invalid parameters at round %d: %w", round, err)
+	}
+	p.observeParameters(round, params)
+
+	// Witness the locks on behalf of all witnesses concurrently, each witness witnessing them in
+	// order.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	for _, w := range p.witnesses {
+		if _, ok := params.WitnessIndex(w.Signer.Public()); !ok {
+			for _, ev := range locks {
+				w.logger.Warn("skipping lock as witness is not authorized",
+					"id", ev.ID,
+					"round", round,
+				)
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(w *processorWitness) {
+			defer wg.Done()
+
+			for _, ev := range locks {
+				if err := p.witnessLock(ctx, w, params, round, ev); err != nil {
+					lock.Lock()
+					defer lock.Unlock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// refreshParameters queries the parameters at the given round to detect changes. Failures are only
+// logged as the parameters are queried again while witnessing locks.
+func (p *EventProcessor) refreshParameters(ctx context.Context, round uint64) {
+	params, err := p.bridge.Parameters(ctx, round)
+	if err != nil {
+		p.logger.Warn("failed to refresh parameters",
+			"err", err,
+			"round", round,
+		)
+		return
+	}
+	p.observeParameters(round, params)
+}
+
+// observeParameters records the parameters observed at the given round. In case they differ from
+// the previously observed parameters, the witness indices are recomputed and the change is
+// reported. Parameters observed at rounds older than the previously observed ones are ignored.
+func (p *EventProcessor) observeParameters(round uint64, params *Parameters) {
+	old := p.params
+	if old != nil && round < p.paramsRound {
+		return
+	}
+	p.params, p.paramsRound = params, round
+	if old != nil && bytes.Equal(cbor.Marshal(old), cbor.Marshal(params)) {
+		return
+	}
+
+	if old != nil {
+		logArgs := []interface{}{
+			"round", round,
+			"added_witnesses", witnessesMissingFrom(params, old),
+			"removed_witnesses", witnessesMissingFrom(old, params),
+		}
+		if old.Threshold != params.Threshold {
+			logArgs = append(logArgs, "old_threshold", old.Threshold, "threshold", params.Threshold)
+		}
+		p.logger.Info("bridge parameters changed", logArgs...)
+	}
+
+	for _, w := range p.witnesses {
+		index, authorized := params.WitnessIndex(w.Signer.Public())
+		switch {
+		case old == nil && !authorized:
+			w.logger.Warn("witness is not authorized",
+				"round", round,
+			)
+		case old == nil:
+		case w.authorized && !authorized:
+			w.logger.Warn("witness has been removed from the authorized witnesses",
+				"round", round,
+			)
+		case !w.authorized && authorized:
+			w.logger.Info("witness has been authorized",
+				"round", round,
+				"index", index,
+			)
+		case authorized && w.index != index:
+			w.logger.Info("witness index changed",
+				"round", round,
+				"old_index", w.index,
+				"index", index,
+			)
+		}
+		w.index, w.authorized = index, authorized
+	}
+
+	if old != nil && p.onParamsChanged != nil {
+		p.onParamsChanged(round, old, params)
+	}
+}
+
+func (p *EventProcessor) witnessLock(
+	ctx context.Context,
+	w *processorWitness,
+	params *Parameters,
+	round uint64,
+	ev *LockEvent,
+) error {
+	op := NewLockOperation(Lock{
+		Target: ev.Target,
+		Amount: ev.Amount,
+	})
+	sig, err := w.WitnessSigner.SignOperation(round, ev.ID, &op)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to sign lock %d: %w", ev.ID, err)
+	}
+
+	if p.dryRun {
+		w.logger.Info("dry run: would submit witness transaction",
+			"id", ev.ID,
+			"sig", hex.EncodeToString(sig),
+		)
+		return nil
+	}
+
+	body := &Witness{
+		ID:        ev.ID,
+		Signature: EncodeWitnessSignature(sig),
+	}
+	p.checkFeeBalance(ctx, w, body)
+	if err = p.waitSubmitJitter(ctx); err != nil {
+		return err
+	}
+
+	w.logger.Info("submitting witness transaction",
+		"id", ev.ID,
+	)
+	var txHash hash.Hash
+	w.submitLock.Lock()
+	err = p.bridge.SubmitWitness(ctx, w.Signer, body, WithNonceManager(w.nonces), WithSubmittedTxHash(&txHash))
+	w.submitLock.Unlock()
+	outcome := SubmissionOutcome(p.bridge.ModuleName(), err)
+	witnessSubmissions.With(prometheus.Labels{
+		"witness":      w.address.String(),
+		"outcome":      outcome,
+		"denomination": denominationLabel(params, ev.Amount.Denomination),
+	}).Inc()
+	switch outcome {
+	case SubmissionSuccess:
+		w.logger.Info("lock witnessed",
+			"id", ev.ID,
+			"tx_hash", txHash,
+		)
+		if p.onWitnessed != nil {
+			p.onWitnessed(ev, txHash)
+		}
+	case SubmissionAlreadyWitnessed:
+		// This can happen when resuming from a checkpoint.
+		w.logger.Info("lock already witnessed",
+			"id", ev.ID,
+		)
+	default:
+		return err
+	}
+	return nil
+}
+
+// waitSubmitJitter waits for a random duration of up to the configured submit jitter.
+func (p *EventProcessor) waitSubmitJitter(ctx context.Context) error {
+	if p.submitJitter <= 0 {
+		return nil
+	}
+
+	p.rngLock.Lock()
+	delay := time.Duration(p.rng.Int63n(int64(p.submitJitter)))
+	p.rngLock.Unlock()
+
+	timer := p.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// checkFeeBalance checks that the witness account has enough balance to pay for the fee of the
+// given witness transaction and warns otherwise. Failures to perform the check are only logged.
+func (p *EventProcessor) checkFeeBalance(ctx context.Context, w *processorWitness, body *Witness) {
+	tx := types.NewTransaction(nil, p.bridge.ModuleName()+"."+methodWitness, body)
+	tx.AppendAuthSignature(w.Signer.Public(), 0)
+	fee, err := p.bridge.EstimateFee(ctx, tx)
+	if err != nil {
+		w.logger.Warn("failed to estimate witness transaction fee",
+			"err", err,
+		)
+		return
+	}
+
+	balances, err := p.accounts.Balances(ctx, client.RoundLatest, AddressOf(w.Signer))
+	if err != nil {
+		w.logger.Warn("failed to fetch witness account balances",
+			"err", err,
+		)
+		return
+	}
+	balance := balances.Balances[fee.Amount.Denomination]
+	witnessFeeBalance.With(prometheus.Labels{"witness": w.address.String()}).Set(quantityToFloat(&balance))
+
+	required := &fee.Amount.Amount
+	if p.minFeeBalance != nil && p.minFeeBalance.Cmp(required) > 0 {
+		required = p.minFeeBalance
+	}
+	if balance.Cmp(required) >= 0 {
+		return
+	}
+	witnessLowFeeBalance.With(prometheus.Labels{"witness": w.address.String()}).Inc()
+	w.logger.Warn("witness account balance is low",
+		"balance", &balance,
+		"required", required,
+		"estimated_fee", fee.Amount,
+	)
+}
+
+// NewEventProcessor creates a new witness event processor for the given witness identities.
+//
+// Each identity maintains its own transaction nonce sequence.
+func NewEventProcessor(
+	rc client.RuntimeClient,
+	witnesses []WitnessIdentity,
+	opts ...ProcessorOption,
+) *EventProcessor {
+	initMetrics()
+
+	acc := accounts.NewV1(rc)
+	p := &EventProcessor{
+		rc:                rc,
+		accounts:          acc,
+		checkpoints:       NewMemoryCheckpointStore(),
+		dryRunCheckpoints: NewMemoryCheckpointStore(),
+		getEventsRetry:    DefaultResubscribePolicy,
+		paramsRefresh:     DefaultParametersRefreshInterval,
+		clock:             RealClock,
+		rng:               rand.New(rand.NewSource(time.Now().UnixNano())),
+		stopCh:            make(chan struct{}),
+		logger:            logger,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.bridge = NewV1(rc, append([]V1Option{WithLogger(p.logger)}, p.bridgeOpts...)...)
+	p.logger = p.logger.With("side", "witness")
+
+	nonces := NewNoncePool(acc)
+	submitLocks := make(map[types.Address]*sync.Mutex)
+	for _, wi := range witnesses {
+		address := AddressOf(wi.Signer)
+		if submitLocks[address] == nil {
+			submitLocks[address] = new(sync.Mutex)
+		}
+		p.witnesses = append(p.witnesses, &processorWitness{
+			WitnessIdentity: wi,
+			address:         address,
+			nonces:          nonces.Get(address),
+			logger:          p.logger.With("witness", address),
+			submitLock:      submitLocks[address],
+		})
+	}
+	return p
+}
@@ -0,0 +1,728 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// submissionClient is a runtime client whose rounds each contain the configured number of lock
+// events and which records the nonces and hashes of the submitted transactions, failing
+// transactions that are not submitted in nonce order and witness transactions for locks that
+// have already been witnessed.
+type submissionClient struct {
+	client.RuntimeClient
+
+	params    *Parameters
+	locks     int
+	gas       uint64
+	balances  map[types.Address]uint64
+	witnessed map[uint64]bool
+
+	lock        sync.Mutex
+	nonces      map[types.Address]uint64
+	submitted   map[types.Address][]uint64
+	txHashes    map[uint64][]hash.Hash
+	inFlight    map[types.Address]int
+	concurrent  int
+	maxInFlight int
+}
+
+func newSubmissionClient(params *Parameters, locks int, nonces map[types.Address]uint64) *submissionClient {
+	return &submissionClient{
+		params:    params,
+		locks:     locks,
+		nonces:    nonces,
+		submitted: make(map[types.Address][]uint64),
+		txHashes:  make(map[uint64][]hash.Hash),
+		inFlight:  make(map[types.Address]int),
+	}
+}
+
+func (rc *submissionClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *submissionClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	var events []*coreClient.Event
+	for i := 0; i < rc.locks; i++ {
+		events = append(events, &coreClient.Event{
+			Key: LockEventKey,
+			Value: cbor.Marshal(&LockEvent{
+				ID:     uint64(i),
+				Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+				Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+			}),
+		})
+	}
+	return events, nil
+}
+
+func (rc *submissionClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = *rc.params
+	case "accounts.Nonce":
+		*rsp.(*uint64) = rc.nonces[args.(*accounts.NonceQuery).Address]
+	case "accounts.Balances":
+		*rsp.(*accounts.AccountBalances) = accounts.AccountBalances{
+			Balances: map[types.Denomination]quantity.Quantity{
+				types.NativeDenomination: *quantity.NewFromUint64(rc.balances[args.(*accounts.BalancesQuery).Address]),
+			},
+		}
+	case "core.EstimateGas":
+		*rsp.(*uint64) = rc.gas
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func (rc *submissionClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	var tx types.Transaction
+	if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+		return nil, err
+	}
+	si := tx.AuthInfo.SignerInfo[0]
+	address := types.NewAddress(*si.AddressSpec.Signature.PublicKey.(*ed25519.PublicKey))
+
+	rc.lock.Lock()
+	rc.inFlight[address]++
+	rc.concurrent++
+	if rc.concurrent > rc.maxInFlight {
+		rc.maxInFlight = rc.concurrent
+	}
+	sameAccount := rc.inFlight[address]
+	rc.lock.Unlock()
+	defer func() {
+		rc.lock.Lock()
+		rc.inFlight[address]--
+		rc.concurrent--
+		rc.lock.Unlock()
+	}()
+
+	time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	switch {
+	case sameAccount > 1:
+		return nil, fmt.Errorf("concurrent submissions for account %s", address)
+	case si.Nonce != rc.nonces[address]:
+		return nil, fmt.Errorf("invalid nonce %d for account %s, expected %d", si.Nonce, address, rc.nonces[address])
+	}
+	rc.nonces[address]++
+	rc.submitted[address] = append(rc.submitted[address], si.Nonce)
+
+	var body Witness
+	if err := cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+		return nil, err
+	}
+	if rc.witnessed[body.ID] {
+		return nil, &types.FailedCallResult{Module: ModuleName, Code: ErrAlreadySubmittedSignatureCode}
+	}
+	rc.txHashes[body.ID] = append(rc.txHashes[body.ID], TxHash(utx))
+	return cbor.Marshal(nil), nil
+}
+
+func TestProcessorWitnessNonceIsolation(t *testing.T) {
+	const locks = 20
+
+	signers := []sdkTesting.TestKey{sdkTesting.Alice, sdkTesting.Bob, sdkTesting.Charlie}
+	params := &Parameters{Threshold: 1}
+	initialNonces := make(map[types.Address]uint64)
+	var witnesses []WitnessIdentity
+	for i, key := range signers {
+		params.Witnesses = append(params.Witnesses, types.PublicKey{PublicKey: key.Signer.Public()})
+		initialNonces[key.Address] = uint64(i * 100)
+		witnesses = append(witnesses, WitnessIdentity{
+			Signer:        key.Signer,
+			WitnessSigner: NewWitnessSigner(key.Signer, "test"),
+		})
+	}
+	nonces := make(map[types.Address]uint64)
+	for address, nonce := range initialNonces {
+		nonces[address] = nonce
+	}
+
+	rc := newSubmissionClient(params, locks, nonces)
+	p := NewEventProcessor(rc, witnesses)
+	if err := p.processRound(context.Background(), 1); err != nil {
+		t.Fatalf("processRound: %v", err)
+	}
+
+	for _, key := range signers {
+		submitted := rc.submitted[key.Address]
+		if len(submitted) != locks {
+			t.Fatalf("expected %d transactions for %s, got %d", locks, key.Address, len(submitted))
+		}
+		for i, nonce := range submitted {
+			if expected := initialNonces[key.Address] + uint64(i); nonce != expected {
+				t.Fatalf("expected nonce %d for %s, got %d", expected, key.Address, nonce)
+			}
+		}
+	}
+	if rc.maxInFlight < 2 {
+		t.Fatalf("expected submissions for different accounts to proceed concurrently")
+	}
+}
+
+// rotatingClient is a runtime client returning the configured parameters at each round.
+type rotatingClient struct {
+	client.RuntimeClient
+
+	params map[uint64]*Parameters
+}
+
+func (rc *rotatingClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*Parameters) = *rc.params[round]
+	return nil
+}
+
+func TestProcessorParametersChanged(t *testing.T) {
+	alice := types.PublicKey{PublicKey: sdkTesting.Alice.Signer.Public()}
+	bob := types.PublicKey{PublicKey: sdkTesting.Bob.Signer.Public()}
+	rc := &rotatingClient{
+		params: map[uint64]*Parameters{
+			1: {Witnesses: []types.PublicKey{alice, bob}, Threshold: 1},
+			2: {Witnesses: []types.PublicKey{alice, bob}, Threshold: 1},
+			3: {Witnesses: []types.PublicKey{bob, alice}, Threshold: 2},
+			4: {Witnesses: []types.PublicKey{bob}, Threshold: 1},
+		},
+	}
+
+	type change struct {
+		round          uint64
+		oldThreshold   uint64
+		newThreshold   uint64
+		witnessesAfter int
+	}
+	var changes []change
+	p := NewEventProcessor(rc, []WitnessIdentity{{
+		Signer:        sdkTesting.Alice.Signer,
+		WitnessSigner: NewWitnessSigner(sdkTesting.Alice.Signer, "test"),
+	}}, WithOnParametersChanged(func(round uint64, old, new *Parameters) {
+		changes = append(changes, change{round, old.Threshold, new.Threshold, len(new.Witnesses)})
+	}))
+	w := p.witnesses[0]
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		round      uint64
+		changes    int
+		index      uint16
+		authorized bool
+	}{
+		{1, 0, 0, true},
+		{2, 0, 0, true},
+		{3, 1, 1, true},
+		// Parameters observed at older rounds are ignored.
+		{1, 1, 1, true},
+		{4, 2, 1, false},
+	} {
+		p.refreshParameters(ctx, tc.round)
+		if len(changes) != tc.changes {
+			t.Fatalf("expected %d changes after round %d, got %d", tc.changes, tc.round, len(changes))
+		}
+		if w.authorized != tc.authorized {
+			t.Fatalf("expected authorized %t after round %d", tc.authorized, tc.round)
+		}
+		if tc.authorized && w.index != tc.index {
+			t.Fatalf("expected index %d after round %d, got %d", tc.index, tc.round, w.index)
+		}
+	}
+
+	expected := []change{{3, 1, 2, 2}, {4, 2, 1, 1}}
+	for i, c := range changes {
+		if c != expected[i] {
+			t.Fatalf("expected change %+v, got %+v", expected[i], c)
+		}
+	}
+}
+
+// drainClient is a runtime client producing blocks on request whose GetEvents calls for the
+// blocked round do not return until released.
+type drainClient struct {
+	watchClient
+
+	blockedRound uint64
+	entered      chan struct{}
+	release      chan struct{}
+
+	lock   sync.Mutex
+	rounds []uint64
+}
+
+func (rc *drainClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.lock.Lock()
+	rc.rounds = append(rc.rounds, round)
+	rc.lock.Unlock()
+
+	if round == rc.blockedRound {
+		close(rc.entered)
+		<-rc.release
+	}
+	return nil, nil
+}
+
+func TestProcessorStop(t *testing.T) {
+	rc := &drainClient{
+		blockedRound: 2,
+		entered:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	checkpoints := NewMemoryCheckpointStore()
+	p := NewEventProcessor(rc, nil, WithCheckpointStore(checkpoints), WithParametersRefreshInterval(0))
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- p.Run(context.Background())
+	}()
+	rc.produce(1)
+	rc.produce(2)
+	<-rc.entered
+
+	stopErr := make(chan error, 1)
+	go func() {
+		stopErr <- p.Stop(context.Background())
+	}()
+	select {
+	case err := <-stopErr:
+		t.Fatalf("Stop returned before the in-flight round was processed: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(rc.release)
+	if err := <-stopErr; err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	default:
+		t.Fatalf("Run has not returned after Stop returned")
+	}
+
+	if round, err := checkpoints.Load(); err != nil || round != 2 {
+		t.Fatalf("expected checkpoint of round 2, got %d (err: %v)", round, err)
+	}
+	if len(rc.rounds) != 2 {
+		t.Fatalf("expected events of 2 rounds to be fetched, got %v", rc.rounds)
+	}
+
+	// Running a stopped processor returns immediately.
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run after Stop: %v", err)
+	}
+}
+
+func TestProcessorStopDuringReorg(t *testing.T) {
+	rc := &drainClient{
+		blockedRound: 2,
+		entered:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	p := NewEventProcessor(rc, nil, WithParametersRefreshInterval(0))
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- p.Run(context.Background())
+	}()
+	rc.produce(1)
+	rc.produce(2)
+	<-rc.entered
+
+	// Going back to round 1 while round 2 is being processed leaves the reorganization pending
+	// when Run returns.
+	rc.produce(1)
+
+	stopErr := make(chan error, 1)
+	go func() {
+		stopErr <- p.Stop(context.Background())
+	}()
+	for !p.stopping() {
+		time.Sleep(time.Millisecond)
+	}
+	close(rc.release)
+
+	select {
+	case err := <-stopErr:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stop did not return while a reorganization was pending")
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+// catchUpClient is a runtime client whose latest round advances to the next of the given heads
+// each time it is queried, recording the rounds whose events are fetched.
+type catchUpClient struct {
+	watchClient
+
+	heads  []uint64
+	rounds []uint64
+}
+
+func (rc *catchUpClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.heads[0]
+	if len(rc.heads) > 1 {
+		rc.heads = rc.heads[1:]
+	}
+	return &blk, nil
+}
+
+func (rc *catchUpClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.rounds = append(rc.rounds, round)
+	return nil, nil
+}
+
+func TestProcessorRunOnce(t *testing.T) {
+	checkpoints := NewMemoryCheckpointStore()
+	if err := checkpoints.Store(2); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+	// Rounds 6 and 7 are produced while catching up.
+	rc := &catchUpClient{heads: []uint64{5, 7}}
+	p := NewEventProcessor(rc, nil, WithCheckpointStore(checkpoints), WithParametersRefreshInterval(0))
+
+	round, err := p.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %s", err)
+	}
+	if round != 7 {
+		t.Fatalf("expected to process up to round 7, got %d", round)
+	}
+	if expected := []uint64{3, 4, 5, 6, 7}; !reflect.DeepEqual(rc.rounds, expected) {
+		t.Fatalf("expected rounds %v to be processed, got %v", expected, rc.rounds)
+	}
+	if round, err = checkpoints.Load(); err != nil || round != 7 {
+		t.Fatalf("expected checkpoint of round 7, got %d (err: %v)", round, err)
+	}
+
+	// Running again without new rounds processes nothing.
+	rc.rounds = nil
+	if round, err = p.RunOnce(context.Background()); err != nil || round != 7 {
+		t.Fatalf("expected to remain at round 7, got %d (err: %v)", round, err)
+	}
+	if len(rc.rounds) != 0 {
+		t.Fatalf("expected no rounds to be processed, got %v", rc.rounds)
+	}
+}
+
+// headClient is a submission client whose latest round is the configured head and which counts
+// all transaction submissions.
+type headClient struct {
+	*submissionClient
+
+	head    uint64
+	submits int
+}
+
+func (rc *headClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	rc.lock.Lock()
+	rc.submits++
+	rc.lock.Unlock()
+	return rc.submissionClient.SubmitTx(ctx, utx)
+}
+
+func (rc *headClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.head
+	return &blk, nil
+}
+
+func TestProcessorDryRun(t *testing.T) {
+	params := &Parameters{
+		Witnesses: []types.PublicKey{{PublicKey: sdkTesting.Alice.Signer.Public()}},
+		Threshold: 1,
+	}
+	rc := &headClient{
+		submissionClient: newSubmissionClient(params, 2, make(map[types.Address]uint64)),
+		head:             3,
+	}
+	checkpoints, dryRunCheckpoints := NewMemoryCheckpointStore(), NewMemoryCheckpointStore()
+	if err := checkpoints.Store(1); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+	if err := dryRunCheckpoints.Store(1); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+	var witnessed int
+	p := NewEventProcessor(rc, []WitnessIdentity{{
+		Signer:        sdkTesting.Alice.Signer,
+		WitnessSigner: NewWitnessSigner(sdkTesting.Alice.Signer, "test"),
+	}},
+		WithDryRun(true),
+		WithCheckpointStore(checkpoints),
+		WithDryRunCheckpointStore(dryRunCheckpoints),
+		WithParametersRefreshInterval(0),
+		WithOnWitnessed(func(*LockEvent, hash.Hash) { witnessed++ }),
+	)
+
+	round, err := p.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %s", err)
+	}
+	if round != 3 {
+		t.Fatalf("expected to process up to round 3, got %d", round)
+	}
+	if rc.submits != 0 {
+		t.Fatalf("expected no transactions to be submitted, got %d", rc.submits)
+	}
+	if witnessed != 0 {
+		t.Fatalf("expected no locks to be reported as witnessed, got %d", witnessed)
+	}
+
+	// Only the dry-run checkpoint advances.
+	if round, err = dryRunCheckpoints.Load(); err != nil || round != 3 {
+		t.Fatalf("expected dry-run checkpoint of round 3, got %d (err: %v)", round, err)
+	}
+	if round, err = checkpoints.Load(); err != nil || round != 1 {
+		t.Fatalf("expected checkpoint to remain at round 1, got %d (err: %v)", round, err)
+	}
+}
+
+func TestProcessorFeeBalance(t *testing.T) {
+	params := &Parameters{
+		Witnesses: []types.PublicKey{{PublicKey: sdkTesting.Alice.Signer.Public()}},
+		Threshold: 1,
+	}
+	labels := prometheus.Labels{"witness": sdkTesting.Alice.Address.String()}
+
+	// The witness transaction is estimated to cost a fee of 20.
+	for _, tc := range []struct {
+		name          string
+		balance       uint64
+		minFeeBalance uint64
+		low           bool
+	}{
+		{"Sufficient", 20, 0, false},
+		{"BelowFee", 19, 0, true},
+		{"BelowMinimum", 50, 100, true},
+		{"AtMinimum", 100, 100, false},
+		// The estimated fee is required even when above the minimum.
+		{"BelowFeeAboveMinimum", 15, 10, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := newSubmissionClient(params, 0, make(map[types.Address]uint64))
+			rc.gas = 10
+			rc.balances = map[types.Address]uint64{sdkTesting.Alice.Address: tc.balance}
+			opts := []ProcessorOption{
+				WithProcessorBridgeOptions(WithGasPrice(types.NewBaseUnits(*quantity.NewFromUint64(2), types.NativeDenomination))),
+			}
+			if tc.minFeeBalance > 0 {
+				opts = append(opts, WithMinFeeBalance(*quantity.NewFromUint64(tc.minFeeBalance)))
+			}
+			p := NewEventProcessor(rc, []WitnessIdentity{{
+				Signer:        sdkTesting.Alice.Signer,
+				WitnessSigner: NewWitnessSigner(sdkTesting.Alice.Signer, "test"),
+			}}, opts...)
+
+			// The warning is logged whenever the low balance counter is incremented.
+			before := testutil.ToFloat64(witnessLowFeeBalance.With(labels))
+			p.checkFeeBalance(context.Background(), p.witnesses[0], &Witness{ID: 1})
+			var expected float64
+			if tc.low {
+				expected = 1
+			}
+			if low := testutil.ToFloat64(witnessLowFeeBalance.With(labels)) - before; low != expected {
+				t.Fatalf("expected low balance counter to increase by %v, got %v", expected, low)
+			}
+			if balance := testutil.ToFloat64(witnessFeeBalance.With(labels)); balance != float64(tc.balance) {
+				t.Fatalf("expected fee balance gauge %d, got %v", tc.balance, balance)
+			}
+		})
+	}
+}
+
+func TestProcessorOnWitnessed(t *testing.T) {
+	signers := []sdkTesting.TestKey{sdkTesting.Alice, sdkTesting.Bob}
+	params := &Parameters{Threshold: 1}
+	var witnesses []WitnessIdentity
+	for _, key := range signers {
+		params.Witnesses = append(params.Witnesses, types.PublicKey{PublicKey: key.Signer.Public()})
+		witnesses = append(witnesses, WitnessIdentity{
+			Signer:        key.Signer,
+			WitnessSigner: NewWitnessSigner(key.Signer, "test"),
+		})
+	}
+	rc := newSubmissionClient(params, 3, make(map[types.Address]uint64))
+	// Lock 1 has already been witnessed, e.g. before resuming from a checkpoint.
+	rc.witnessed = map[uint64]bool{1: true}
+
+	var (
+		lock      sync.Mutex
+		witnessed = make(map[uint64][]hash.Hash)
+	)
+	p := NewEventProcessor(rc, witnesses, WithOnWitnessed(func(ev *LockEvent, txHash hash.Hash) {
+		lock.Lock()
+		defer lock.Unlock()
+		witnessed[ev.ID] = append(witnessed[ev.ID], txHash)
+	}))
+	if err := p.processRound(context.Background(), 1); err != nil {
+		t.Fatalf("processRound: %v", err)
+	}
+
+	// Each witness reports its own witness transaction of the locks that were not yet witnessed.
+	if len(witnessed) != 2 {
+		t.Fatalf("expected 2 witnessed locks, got %d", len(witnessed))
+	}
+	for _, id := range []uint64{0, 2} {
+		expected := make(map[hash.Hash]bool)
+		for _, h := range rc.txHashes[id] {
+			expected[h] = true
+		}
+		if len(witnessed[id]) != len(signers) || len(expected) != len(signers) {
+			t.Fatalf("expected lock %d to be witnessed by %d witnesses, got %d", id, len(signers), len(witnessed[id]))
+		}
+		for _, h := range witnessed[id] {
+			if !expected[h] {
+				t.Fatalf("unexpected transaction hash %s reported for lock %d", h, id)
+			}
+		}
+	}
+}
+
+// recordingClock is a fake clock recording the durations of the created timers, which fire
+// immediately.
+type recordingClock struct {
+	*FakeClock
+
+	lock   sync.Mutex
+	delays []time.Duration
+}
+
+func (c *recordingClock) NewTimer(d time.Duration) Timer {
+	c.lock.Lock()
+	c.delays = append(c.delays, d)
+	c.lock.Unlock()
+	return c.FakeClock.NewTimer(0)
+}
+
+func TestProcessorSubmitJitter(t *testing.T) {
+	const jitter = 100 * time.Millisecond
+	ctx := context.Background()
+
+	clock := &recordingClock{FakeClock: NewFakeClock(time.Unix(0, 0))}
+	p := NewEventProcessor(nil, nil, WithSubmitJitter(jitter), WithProcessorClock(clock))
+	for i := 0; i < 1000; i++ {
+		if err := p.waitSubmitJitter(ctx); err != nil {
+			t.Fatalf("waitSubmitJitter: %s", err)
+		}
+	}
+	var lowest, highest time.Duration = jitter, 0
+	for _, d := range clock.delays {
+		if d < 0 || d >= jitter {
+			t.Fatalf("expected jitter within [0, %s), got %s", jitter, d)
+		}
+		if d < lowest {
+			lowest = d
+		}
+		if d > highest {
+			highest = d
+		}
+	}
+	if lowest > jitter/10 || highest < jitter*9/10 {
+		t.Fatalf("expected jitter to spread over [0, %s), got [%s, %s]", jitter, lowest, highest)
+	}
+
+	// Without jitter no timer is used.
+	clock = &recordingClock{FakeClock: NewFakeClock(time.Unix(0, 0))}
+	p = NewEventProcessor(nil, nil, WithProcessorClock(clock))
+	if err := p.waitSubmitJitter(ctx); err != nil || len(clock.delays) != 0 {
+		t.Fatalf("expected no jitter, got %v (err: %v)", clock.delays, err)
+	}
+
+	// The jitter is capped and waiting is aborted once the context is canceled.
+	p = NewEventProcessor(nil, nil, WithSubmitJitter(time.Hour), WithProcessorClock(NewFakeClock(time.Unix(0, 0))))
+	if p.submitJitter != MaxSubmitJitter {
+		t.Fatalf("expected jitter to be capped at %s, got %s", MaxSubmitJitter, p.submitJitter)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := p.waitSubmitJitter(cctx); err != context.Canceled {
+		t.Fatalf("expected waiting to be canceled, got %v", err)
+	}
+}
+
+// failingCheckpointStore is a checkpoint store failing to store the configured round.
+type failingCheckpointStore struct {
+	CheckpointStore
+
+	failRound uint64
+}
+
+func (s *failingCheckpointStore) Store(round uint64) error {
+	if round == s.failRound {
+		return errors.New("disk full")
+	}
+	return s.CheckpointStore.Store(round)
+}
+
+func TestProcessorOnCheckpoint(t *testing.T) {
+	checkpoints := &failingCheckpointStore{CheckpointStore: NewMemoryCheckpointStore(), failRound: 5}
+	if err := checkpoints.Store(2); err != nil {
+		t.Fatalf("failed to store checkpoint: %s", err)
+	}
+
+	var rounds []uint64
+	rc := &catchUpClient{heads: []uint64{6}}
+	p := NewEventProcessor(rc, nil,
+		WithCheckpointStore(checkpoints),
+		WithParametersRefreshInterval(0),
+		WithOnCheckpoint(func(round uint64) {
+			// The callback is only invoked once the checkpoint has been stored.
+			if stored, err := checkpoints.Load(); err != nil || stored != round {
+				t.Errorf("expected checkpoint of round %d to be stored, got %d (err: %v)", round, stored, err)
+			}
+			rounds = append(rounds, round)
+		}),
+	)
+
+	// Rounds whose checkpoint fails to be stored are not reported.
+	if _, err := p.RunOnce(context.Background()); err == nil {
+		t.Fatalf("expected storing the checkpoint of round 5 to fail")
+	}
+	if expected := []uint64{3, 4}; !reflect.DeepEqual(rounds, expected) {
+		t.Fatalf("expected checkpoints of rounds %v to be reported, got %v", expected, rounds)
+	}
+
+	checkpoints.failRound = 0
+	rounds = nil
+	if _, err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %s", err)
+	}
+	if expected := []uint64{5, 6}; !reflect.DeepEqual(rounds, expected) {
+		t.Fatalf("expected checkpoints of rounds %v to be reported, got %v", expected, rounds)
+	}
+}
@@ -0,0 +1,165 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrLockNotFound is the error returned when a lock with the given identifier does not exist.
+var ErrLockNotFound = errors.New("bridge: lock not found")
+
+// Implements V1.
+func (a *v1) WitnessProgress(ctx context.Context, round, lockID uint64) (uint64, uint64, error) {
+	if round == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	params, err := a.Parameters(ctx, round)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query parameters: %w", err)
+	}
+	lockRound, err := a.findLockRound(ctx, round, lockID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	method := a.method(methodWitness)
+	witnesses := make(map[uint16]struct{})
+	for r := lockRound; r <= round; r++ {
+		events, err := a.GetEvents(ctx, r)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, ev := range events {
+			if ws := ev.WitnessesSigned; ws != nil && ws.IsLock() && ws.ID == lockID {
+				return uint64(len(ws.Witnesses)), params.Threshold, nil
+			}
+		}
+
+		txs, err := a.rc.GetTransactions(ctx, r)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to fetch transactions for round %d: %w", r, err)
+		}
+		for _, utx := range txs {
+			var tx types.Transaction
+			if err = cbor.Unmarshal(utx.Body, &tx); err != nil || tx.Call.Method != method {
+				continue
+			}
+			var body Witness
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil || body.ID != lockID {
+				continue
+			}
+			for _, si := range tx.AuthInfo.SignerInfo {
+				if si.AddressSpec.Signature == nil {
+					continue
+				}
+				if index, ok := params.WitnessIndex(si.AddressSpec.Signature.PublicKey); ok {
+					witnesses[index] = struct{}{}
+				}
+			}
+		}
+	}
+	return uint64(len(witnesses)), params.Threshold, nil
+}
+
+// errStopReplay is returned by replay callbacks to stop replaying events early.
+var errStopReplay = errors.New("bridge: stop replay")
+
+// Implements V1.
+func (a *v1) IsLockReleased(ctx context.Context, round, lockID uint64) (bool, error) {
+	if round == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	lockRound, err := a.findLockRound(ctx, round, lockID)
+	if err != nil {
+		return false, err
+	}
+
+	var released bool
+	err = a.ReplayEvents(ctx, lockRound, round, func(_ uint64, events []*Event) error {
+		for _, ev := range events {
+			if ws := ev.WitnessesSigned; ws != nil && ws.IsLock() && ws.ID == lockID {
+				released = true
+				return errStopReplay
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		return false, err
+	}
+	return released, nil
+}
+
+// findLockRound returns the round in which the lock with the given identifier has been created,
+// searching rounds up to and including the given round.
+func (a *v1) findLockRound(ctx context.Context, round, lockID uint64) (uint64, error) {
+	created := func(seq *NextSequenceNumbers) bool {
+		return seq.Outgoing > lockID
+	}
+	lockRound, ok, err := a.findSequenceRound(ctx, round, created)
+	switch {
+	case err != nil:
+		return 0, err
+	case !ok:
+		return 0, fmt.Errorf("%w: %d", ErrLockNotFound, lockID)
+	default:
+		return lockRound, nil
+	}
+}
+
+// findSequenceRound returns the first round up to and including the given round at which the next
+// sequence numbers satisfy the given condition, which must keep holding once it holds. False is
+// returned in case the condition does not hold at the given round.
+func (a *v1) findSequenceRound(
+	ctx context.Context,
+	round uint64,
+	cond func(seq *NextSequenceNumbers) bool,
+) (uint64, bool, error) {
+	holds := func(r uint64) (bool, error) {
+		seq, err := a.NextSequenceNumbers(ctx, r)
+		if err != nil {
+			return false, fmt.Errorf("failed to query next sequence numbers: %w", err)
+		}
+		return cond(seq), nil
+	}
+
+	ok, err := holds(round)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	genesis, err := a.rc.GetGenesisBlock(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch genesis block: %w", err)
+	}
+	// Find the first round at which the sequence numbers satisfy the condition.
+	lo, hi := genesis.Header.Round, round
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if ok, err = holds(mid); err != nil {
+			return 0, false, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, true, nil
+}
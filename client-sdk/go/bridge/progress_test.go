@@ -0,0 +1,188 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// chainClient is a runtime client serving a fixed history of rounds starting at genesis round 0
+// in which the lock with identifier lockID has been created in lockRound.
+type chainClient struct {
+	client.RuntimeClient
+
+	latest    uint64
+	lockID    uint64
+	lockRound uint64
+	params    Parameters
+	events    map[uint64][]*coreClient.Event
+	txs       map[uint64][]*types.UnverifiedTransaction
+
+	// eventsErr is returned when fetching events.
+	eventsErr error
+}
+
+func (rc *chainClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	return &block.Block{}, nil
+}
+
+func (rc *chainClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *chainClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	if rc.eventsErr != nil {
+		return nil, rc.eventsErr
+	}
+	return rc.events[round], nil
+}
+
+func (rc *chainClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	return rc.txs[round], nil
+}
+
+func (rc *chainClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = rc.params
+	case ModuleName + "." + methodNextSequenceNumbers:
+		seq := NextSequenceNumbers{Outgoing: rc.lockID}
+		if round >= rc.lockRound {
+			seq.Outgoing++
+		}
+		*rsp.(*NextSequenceNumbers) = seq
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+// witnessTx returns a bridge.Witness transaction for the given lock signed by the given key.
+func witnessTx(pk signature.PublicKey, lockID uint64) *types.UnverifiedTransaction {
+	tx := types.NewTransaction(nil, ModuleName+"."+methodWitness, &Witness{ID: lockID})
+	tx.AppendAuthSignature(pk, 0)
+	return tx.PrepareForSigning().UnverifiedTransaction()
+}
+
+func newChainClient() *chainClient {
+	return &chainClient{
+		latest:    8,
+		lockID:    5,
+		lockRound: 3,
+		params: Parameters{
+			Witnesses: []types.PublicKey{
+				{PublicKey: sdkTesting.Alice.Signer.Public()},
+				{PublicKey: sdkTesting.Bob.Signer.Public()},
+				{PublicKey: sdkTesting.Charlie.Signer.Public()},
+			},
+			Threshold: 2,
+		},
+		events: map[uint64][]*coreClient.Event{
+			6: {{
+				Key: WitnessesSignedEventKey,
+				Value: cbor.Marshal(&WitnessesSignedEvent{
+					ID:         5,
+					Op:         NewLockOperation(Lock{}),
+					Witnesses:  []uint16{0, 1},
+					Signatures: [][]byte{{0x01}, {0x02}},
+				}),
+			}},
+		},
+		txs: map[uint64][]*types.UnverifiedTransaction{
+			4: {
+				witnessTx(sdkTesting.Alice.Signer.Public(), 5),
+				// Signatures of other locks and of unauthorized signers are not counted.
+				witnessTx(sdkTesting.Bob.Signer.Public(), 6),
+				witnessTx(sdkTesting.Dave.Signer.Public(), 5),
+			},
+			// Repeated signatures of the same witness are only counted once.
+			5: {witnessTx(sdkTesting.Alice.Signer.Public(), 5)},
+			6: {witnessTx(sdkTesting.Bob.Signer.Public(), 5)},
+		},
+	}
+}
+
+func TestWitnessProgress(t *testing.T) {
+	ctx := context.Background()
+	v := NewV1(newChainClient())
+
+	for _, tc := range []struct {
+		name      string
+		round     uint64
+		collected uint64
+	}{
+		{"Created", 3, 0},
+		{"Partial", 5, 1},
+		{"Complete", client.RoundLatest, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			collected, threshold, err := v.WitnessProgress(ctx, tc.round, 5)
+			if err != nil {
+				t.Fatalf("WitnessProgress: %s", err)
+			}
+			if collected != tc.collected || threshold != 2 {
+				t.Fatalf("expected %d of 2 signatures, got %d of %d", tc.collected, collected, threshold)
+			}
+		})
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		if _, _, err := v.WitnessProgress(ctx, client.RoundLatest, 9); !errors.Is(err, ErrLockNotFound) {
+			t.Fatalf("expected ErrLockNotFound, got %v", err)
+		}
+		// The lock does not exist yet before the round in which it has been created.
+		if _, _, err := v.WitnessProgress(ctx, 2, 5); !errors.Is(err, ErrLockNotFound) {
+			t.Fatalf("expected ErrLockNotFound, got %v", err)
+		}
+	})
+}
+
+func TestIsLockReleased(t *testing.T) {
+	ctx := context.Background()
+	rc := newChainClient()
+	v := NewV1(rc)
+
+	for _, tc := range []struct {
+		name     string
+		round    uint64
+		expected bool
+	}{
+		{"NotReleased", 5, false},
+		{"Released", 6, true},
+		{"Latest", client.RoundLatest, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			released, err := v.IsLockReleased(ctx, tc.round, 5)
+			if err != nil {
+				t.Fatalf("IsLockReleased: %s", err)
+			}
+			if released != tc.expected {
+				t.Fatalf("expected released to be %t, got %t", tc.expected, released)
+			}
+		})
+	}
+
+	t.Run("LookupError", func(t *testing.T) {
+		if _, err := v.IsLockReleased(ctx, client.RoundLatest, 9); !errors.Is(err, ErrLockNotFound) {
+			t.Fatalf("expected ErrLockNotFound, got %v", err)
+		}
+
+		failing := newChainClient()
+		failing.eventsErr = errors.New("unavailable")
+		if _, err := NewV1(failing).IsLockReleased(ctx, client.RoundLatest, 5); !errors.Is(err, failing.eventsErr) {
+			t.Fatalf("expected the error fetching events, got %v", err)
+		}
+	})
+}
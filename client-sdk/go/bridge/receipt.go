@@ -0,0 +1,180 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrReceiptNotFound is the error returned when the round in which a submitted transaction has
+// been executed cannot be found. The transaction has been executed nonetheless.
+var ErrReceiptNotFound = errors.New("bridge: transaction not found in any round")
+
+// Receipt is the receipt of an executed transaction.
+type Receipt struct {
+	// Round is the runtime round in which the transaction has been executed or zero in case it
+	// could not be determined (see ErrReceiptNotFound).
+	Round uint64 `json:"round"`
+
+	// Result is the CBOR-encoded call result.
+	Result cbor.RawMessage `json:"result"`
+
+	// TxHash is the hash of the transaction. It matches the transaction hash of any events
+	// emitted by the transaction.
+	TxHash hash.Hash `json:"tx_hash"`
+}
+
+// DecodeResult decodes the CBOR-encoded call result into the given value.
+func (r *Receipt) DecodeResult(result interface{}) error {
+	if len(r.Result) == 0 {
+		return nil
+	}
+	if err := cbor.Unmarshal(r.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal call result: %w", err)
+	}
+	return nil
+}
+
+// TxHash returns the hash of the given transaction.
+func TxHash(tx *types.UnverifiedTransaction) hash.Hash {
+	return hash.NewFromBytes(cbor.Marshal(tx))
+}
+
+// SubmitWithReceipt submits the given transaction, waits for its result and returns a receipt
+// describing where it has been executed.
+//
+// As the runtime does not report the round in which a transaction has been executed, the rounds
+// finalized during submission are searched for the transaction which requires additional queries.
+// In case the transaction has been executed but its round cannot be determined, the receipt is
+// returned with a zero Round together with an error wrapping ErrReceiptNotFound. As the
+// transaction has been executed in that case, it must not be submitted again.
+func SubmitWithReceipt(ctx context.Context, rc client.RuntimeClient, tx *types.UnverifiedTransaction) (*Receipt, error) {
+	before, err := rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+
+	raw, err := rc.SubmitTx(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+	receipt := &Receipt{
+		Result: raw,
+		TxHash: TxHash(tx),
+	}
+	if receipt.Round, err = findTxRound(ctx, rc, before.Header.Round+1, receipt.TxHash); err != nil {
+		return receipt, err
+	}
+	return receipt, nil
+}
+
+// findTxRound returns the round starting with the given round up to the latest round that
+// contains the transaction with the given hash.
+func findTxRound(ctx context.Context, rc client.RuntimeClient, fromRound uint64, txHash hash.Hash) (uint64, error) {
+	latest, err := rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: failed to fetch latest block: %v", ErrReceiptNotFound, txHash, err)
+	}
+	for round := fromRound; round <= latest.Header.Round; round++ {
+		txs, err := rc.GetTransactions(ctx, round)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: failed to fetch transactions for round %d: %v", ErrReceiptNotFound, txHash, round, err)
+		}
+		for _, rtx := range txs {
+			if h := TxHash(rtx); h.Equal(&txHash) {
+				return round, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrReceiptNotFound, txHash)
+}
+
+// Implements V1.
+func (a *v1) LockByTxHash(ctx context.Context, fromRound uint64, txHash hash.Hash) (*LockEvent, error) {
+	latest, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+
+	for round := fromRound; round <= latest.Header.Round; round++ {
+		events, err := a.GetEvents(ctx, round)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if ev.Lock != nil && ev.TxHash.Equal(&txHash) {
+				return ev.Lock, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no lock emitted by transaction %s since round %d", ErrLockNotFound, txHash, fromRound)
+}
+
+// Implements V1.
+func (a *v1) SubmitLockAndWait(
+	ctx context.Context,
+	signer signature.Signer,
+	body *Lock,
+	opts ...SubmitOption,
+) (*LockResult, *LockEvent, error) {
+	before, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+
+	var txHash hash.Hash
+	result, err := a.SubmitLock(ctx, signer, body, append(opts, WithSubmittedTxHash(&txHash))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	ev, err := a.waitForLockEvent(ctx, before.Header.Round+1, result.ID, txHash)
+	if err != nil {
+		return result, nil, err
+	}
+	return result, ev, nil
+}
+
+// waitForLockEvent searches the rounds starting with the given round for the event of the lock
+// with the given identifier emitted by the given transaction, waiting for new rounds as needed.
+func (a *v1) waitForLockEvent(ctx context.Context, fromRound, id uint64, txHash hash.Hash) (*LockEvent, error) {
+	timeout := func(err error) error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: lock %d", ErrLockEventTimeout, id)
+		}
+		return err
+	}
+
+	// Only wait for rounds past the head so that existing rounds are searched without subscribing.
+	head, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, timeout(fmt.Errorf("failed to fetch latest block: %w", err))
+	}
+	latest := head.Header.Round
+	for round := fromRound; ; round++ {
+		if round > latest {
+			if err = a.WaitForRound(ctx, round); err != nil {
+				return nil, timeout(err)
+			}
+			if head, err = a.rc.GetBlock(ctx, client.RoundLatest); err != nil {
+				return nil, timeout(fmt.Errorf("failed to fetch latest block: %w", err))
+			}
+			latest = head.Header.Round
+		}
+		events, err := a.GetEvents(ctx, round)
+		if err != nil {
+			return nil, timeout(err)
+		}
+		for _, ev := range events {
+			if ev.Lock != nil && ev.Lock.ID == id && ev.TxHash.Equal(&txHash) {
+				return ev.Lock, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// lockTxClient is a runtime client whose rounds each contain a single lock event with the round
+// as its identifier, emitted by a transaction whose hash is derived from the round. It counts the
+// rounds whose events are fetched.
+type lockTxClient struct {
+	client.RuntimeClient
+
+	latest  uint64
+	fetched int
+}
+
+func lockTxHash(round uint64) hash.Hash {
+	return hash.NewFromBytes(cbor.Marshal(round))
+}
+
+func (rc *lockTxClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *lockTxClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.fetched++
+	return []*coreClient.Event{{
+		Key:    LockEventKey,
+		Value:  cbor.Marshal(&LockEvent{ID: round}),
+		TxHash: lockTxHash(round),
+	}}, nil
+}
+
+func TestLockByTxHash(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name      string
+		fromRound uint64
+		txRound   uint64
+		found     bool
+		fetched   int
+	}{
+		{"ReceiptRound", 7, 7, true, 1},
+		{"LaterRound", 5, 9, true, 5},
+		{"LatestRound", 20, 20, true, 1},
+		{"Genesis", 0, 0, true, 1},
+		{"BeforeFromRound", 15, 7, false, 6},
+		{"Unknown", 18, 21, false, 3},
+		{"FromRoundPastLatest", 25, 21, false, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &lockTxClient{latest: 20}
+			ev, err := NewV1(rc).LockByTxHash(ctx, tc.fromRound, lockTxHash(tc.txRound))
+			if !tc.found {
+				if !errors.Is(err, ErrLockNotFound) {
+					t.Fatalf("expected ErrLockNotFound, got %v", err)
+				}
+			} else if err != nil || ev.ID != tc.txRound {
+				t.Fatalf("expected lock %d emitted in round %d, got %+v (err: %v)", tc.txRound, tc.txRound, ev, err)
+			}
+			if rc.fetched != tc.fetched {
+				t.Fatalf("expected events of %d rounds to be fetched, got %d", tc.fetched, rc.fetched)
+			}
+		})
+	}
+}
+
+// receiptClient is a runtime client that executes each submitted transaction in a new round.
+type receiptClient struct {
+	client.RuntimeClient
+
+	latest    uint64
+	txs       map[uint64][]*types.UnverifiedTransaction
+	submitted bool
+
+	submitErr error
+	lookupErr error
+	omit      bool
+}
+
+func (rc *receiptClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if rc.submitted && rc.lookupErr != nil {
+		return nil, rc.lookupErr
+	}
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *receiptClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	return rc.txs[round], nil
+}
+
+func (rc *receiptClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	if rc.submitErr != nil {
+		return nil, rc.submitErr
+	}
+	rc.submitted = true
+	rc.latest += 2
+	other := &types.UnverifiedTransaction{Body: []byte("other")}
+	rc.txs[rc.latest-1] = []*types.UnverifiedTransaction{other}
+	if !rc.omit {
+		rc.txs[rc.latest] = []*types.UnverifiedTransaction{other, tx}
+	}
+	return cbor.Marshal(&LockResult{ID: 3}), nil
+}
+
+func TestSubmitWithReceipt(t *testing.T) {
+	ctx := context.Background()
+	tx := &types.UnverifiedTransaction{Body: []byte("lock")}
+
+	rc := &receiptClient{latest: 5, txs: make(map[uint64][]*types.UnverifiedTransaction)}
+	receipt, err := SubmitWithReceipt(ctx, rc, tx)
+	if err != nil {
+		t.Fatalf("SubmitWithReceipt: %s", err)
+	}
+	if h := TxHash(tx); receipt.Round != 7 || !receipt.TxHash.Equal(&h) {
+		t.Fatalf("expected receipt for round 7, got %+v", receipt)
+	}
+	var result LockResult
+	if err = receipt.DecodeResult(&result); err != nil || result.ID != 3 {
+		t.Fatalf("expected lock result 3, got %+v (err: %v)", result, err)
+	}
+
+	// Failed submissions return no receipt.
+	rc = &receiptClient{submitErr: errors.New("rejected"), txs: make(map[uint64][]*types.UnverifiedTransaction)}
+	if receipt, err = SubmitWithReceipt(ctx, rc, tx); err == nil || receipt != nil {
+		t.Fatalf("expected submission to fail, got %+v (err: %v)", receipt, err)
+	}
+
+	// Executed transactions whose round cannot be determined return a receipt without a round.
+	for _, tc := range []struct {
+		name string
+		rc   *receiptClient
+	}{
+		{"LookupFailure", &receiptClient{lookupErr: errors.New("unavailable")}},
+		{"NotIncluded", &receiptClient{omit: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.rc.latest = 5
+			tc.rc.txs = make(map[uint64][]*types.UnverifiedTransaction)
+			receipt, err := SubmitWithReceipt(ctx, tc.rc, tx)
+			if !errors.Is(err, ErrReceiptNotFound) {
+				t.Fatalf("expected ErrReceiptNotFound, got %v", err)
+			}
+			if receipt == nil || receipt.Round != 0 || len(receipt.Result) == 0 {
+				t.Fatalf("expected receipt with the result and without a round, got %+v", receipt)
+			}
+			if h := TxHash(tx); !receipt.TxHash.Equal(&h) {
+				t.Fatalf("expected transaction hash %s, got %s", h, receipt.TxHash)
+			}
+		})
+	}
+}
+
+// submitLockClient is a runtime client that executes each submitted lock in a new round, which is
+// followed by the given number of empty rounds. Unless withholding events, the round contains the
+// lock event next to an unrelated lock.
+type submitLockClient struct {
+	watchClient
+
+	lock     sync.Mutex
+	latest   uint64
+	events   map[uint64][]*coreClient.Event
+	txs      map[uint64][]*types.UnverifiedTransaction
+	nextID   uint64
+	withhold bool
+	empty    uint64
+	balances map[types.Address]uint64
+}
+
+func (rc *submitLockClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *submitLockClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *submitLockClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	return rc.events[round], nil
+}
+
+func (rc *submitLockClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	return rc.txs[round], nil
+}
+
+func (rc *submitLockClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = Parameters{LocalDenominations: []types.Denomination{types.NativeDenomination}}
+	case "accounts.Nonce", "core.EstimateGas":
+		*rsp.(*uint64) = 0
+	case "accounts.Balances":
+		*rsp.(*accounts.AccountBalances) = accounts.AccountBalances{
+			Balances: map[types.Denomination]quantity.Quantity{
+				types.NativeDenomination: *quantity.NewFromUint64(rc.balances[args.(*accounts.BalancesQuery).Address]),
+			},
+		}
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func (rc *submitLockClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	id := rc.nextID
+	rc.nextID += 2
+	rc.latest++
+	rc.txs[rc.latest] = []*types.UnverifiedTransaction{utx}
+	if !rc.withhold {
+		rc.events[rc.latest] = []*coreClient.Event{
+			{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: id + 1}), TxHash: lockTxHash(id + 1)},
+			{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: id}), TxHash: TxHash(utx)},
+		}
+	}
+	rc.latest += rc.empty
+	return cbor.Marshal(&LockResult{ID: id}), nil
+}
+
+func newSubmitLockClient() *submitLockClient {
+	return &submitLockClient{
+		latest: 10,
+		events: make(map[uint64][]*coreClient.Event),
+		txs:    make(map[uint64][]*types.UnverifiedTransaction),
+		nextID: 4,
+	}
+}
+
+func TestSubmitLockWithReceipt(t *testing.T) {
+	v := NewV1(newSubmitLockClient())
+	body := &Lock{
+		Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+	}
+
+	var (
+		receipt Receipt
+		txHash  hash.Hash
+	)
+	result, err := v.SubmitLock(context.Background(), sdkTesting.Alice.Signer, body, WithReceipt(&receipt), WithSubmittedTxHash(&txHash))
+	if err != nil {
+		t.Fatalf("SubmitLock: %s", err)
+	}
+	if receipt.Round != 11 || !receipt.TxHash.Equal(&txHash) {
+		t.Fatalf("expected receipt for round 11, got %+v", receipt)
+	}
+	var decoded LockResult
+	if err = receipt.DecodeResult(&decoded); err != nil || decoded.ID != result.ID {
+		t.Fatalf("expected receipt of lock %d, got %+v (err: %v)", result.ID, decoded, err)
+	}
+}
+
+func TestSubmitLockAndWait(t *testing.T) {
+	rc := newSubmitLockClient()
+	v := NewV1(rc)
+	body := &Lock{
+		Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+	}
+
+	result, ev, err := v.SubmitLockAndWait(context.Background(), sdkTesting.Alice.Signer, body)
+	if err != nil {
+		t.Fatalf("SubmitLockAndWait: %v", err)
+	}
+	if result.ID != 4 || ev == nil || ev.ID != 4 {
+		t.Fatalf("expected lock 4 and its event, got %+v and %+v", result, ev)
+	}
+
+	// Rounds that already exist are searched without waiting for new blocks.
+	rc.empty = 5
+	if result, ev, err = v.SubmitLockAndWait(context.Background(), sdkTesting.Alice.Signer, body); err != nil {
+		t.Fatalf("SubmitLockAndWait: %v", err)
+	}
+	if result.ID != 6 || ev == nil || ev.ID != 6 {
+		t.Fatalf("expected lock 6 and its event, got %+v and %+v", result, ev)
+	}
+	rc.watchClient.lock.Lock()
+	subs := len(rc.watchClient.subs)
+	rc.watchClient.lock.Unlock()
+	if subs != 0 {
+		t.Fatalf("expected no block subscriptions for existing rounds, got %d", subs)
+	}
+
+	// In case the event is not observed in time, the result is returned with a timeout error.
+	rc.withhold = true
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, ev, err = v.SubmitLockAndWait(ctx, sdkTesting.Alice.Signer, body)
+	if !errors.Is(err, ErrLockEventTimeout) {
+		t.Fatalf("expected ErrLockEventTimeout, got %v", err)
+	}
+	if result == nil || result.ID != 8 || ev != nil {
+		t.Fatalf("expected result of lock 8 without an event, got %+v and %+v", result, ev)
+	}
+}
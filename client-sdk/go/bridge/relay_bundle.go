@@ -0,0 +1,54 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// RelayBundleVersion is the current version of the relay bundle encoding.
+const RelayBundleVersion = 1
+
+var (
+	// ErrMalformedRelayBundle is the error returned when a relay bundle cannot be decoded.
+	ErrMalformedRelayBundle = errors.New("bridge: malformed relay bundle")
+
+	// ErrUnsupportedRelayBundleVersion is the error returned when a relay bundle uses an
+	// unsupported encoding version.
+	ErrUnsupportedRelayBundleVersion = errors.New("bridge: unsupported relay bundle version")
+)
+
+// MarshalRelayBundle encodes the signed operation, witness indices and signatures into a relay
+// bundle that can be handed to a relayer process.
+//
+// A relay bundle consists of a single version byte (RelayBundleVersion) followed by the
+// canonical CBOR encoding of the event.
+func (e *WitnessesSignedEvent) MarshalRelayBundle() ([]byte, error) {
+	if len(e.Witnesses) != len(e.Signatures) {
+		return nil, fmt.Errorf("bridge: mismatched number of witnesses (%d) and signatures (%d)",
+			len(e.Witnesses), len(e.Signatures),
+		)
+	}
+	return append([]byte{RelayBundleVersion}, cbor.Marshal(e)...), nil
+}
+
+// UnmarshalRelayBundle decodes a relay bundle produced by MarshalRelayBundle.
+func (e *WitnessesSignedEvent) UnmarshalRelayBundle(data []byte) error {
+	if len(data) == 0 {
+		return ErrMalformedRelayBundle
+	}
+	if data[0] != RelayBundleVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedRelayBundleVersion, data[0])
+	}
+
+	var ev WitnessesSignedEvent
+	if err := cbor.Unmarshal(data[1:], &ev); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedRelayBundle, err)
+	}
+	if len(ev.Witnesses) != len(ev.Signatures) {
+		return fmt.Errorf("%w: mismatched number of witnesses and signatures", ErrMalformedRelayBundle)
+	}
+	*e = ev
+	return nil
+}
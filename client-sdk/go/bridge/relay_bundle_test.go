@@ -0,0 +1,82 @@
+package bridge
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// relayBundleVector is a known relay bundle encoding that relayers written in other languages
+// can be checked against.
+const relayBundleVector = "01a4626964182a626f70a1646c6f636ba266616d6f756e74824203e840667461726765745400112233445566778899aabbccddeeff0011223364736967738242aabb42ccdd6477697473820002"
+
+func testRelayBundleEvent() *WitnessesSignedEvent {
+	return &WitnessesSignedEvent{
+		ID: 42,
+		Op: NewLockOperation(Lock{
+			Target: NewRemoteAddressFromHex("00112233445566778899aabbccddeeff00112233"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(1000), types.NativeDenomination),
+		}),
+		Witnesses:  []uint16{0, 2},
+		Signatures: [][]byte{{0xaa, 0xbb}, {0xcc, 0xdd}},
+	}
+}
+
+func TestRelayBundleRoundTrip(t *testing.T) {
+	ev := testRelayBundleEvent()
+	data, err := ev.MarshalRelayBundle()
+	if err != nil {
+		t.Fatalf("MarshalRelayBundle: %s", err)
+	}
+	if data[0] != RelayBundleVersion {
+		t.Fatalf("expected version byte %d, got %d", RelayBundleVersion, data[0])
+	}
+	if expected := mustDecodeHex(t, relayBundleVector); !bytes.Equal(data, expected) {
+		t.Fatalf("relay bundle encoding mismatch: expected %x, got %x", expected, data)
+	}
+
+	var decoded WitnessesSignedEvent
+	if err = decoded.UnmarshalRelayBundle(data); err != nil {
+		t.Fatalf("UnmarshalRelayBundle: %s", err)
+	}
+	if !decoded.Equal(ev) {
+		t.Fatalf("relay bundle decoding mismatch: expected %+v, got %+v", ev, decoded)
+	}
+}
+
+func TestRelayBundleRejected(t *testing.T) {
+	mismatched := testRelayBundleEvent()
+	mismatched.Signatures = mismatched.Signatures[:1]
+	if _, err := mismatched.MarshalRelayBundle(); err == nil {
+		t.Fatalf("expected marshaling mismatched witnesses and signatures to fail")
+	}
+
+	data, err := testRelayBundleEvent().MarshalRelayBundle()
+	if err != nil {
+		t.Fatalf("MarshalRelayBundle: %s", err)
+	}
+	unsupported := append([]byte{RelayBundleVersion + 1}, data[1:]...)
+
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"Empty", nil, ErrMalformedRelayBundle},
+		{"VersionOnly", []byte{RelayBundleVersion}, ErrMalformedRelayBundle},
+		{"UnsupportedVersion", unsupported, ErrUnsupportedRelayBundleVersion},
+		{"MismatchedSignatures", append([]byte{RelayBundleVersion}, cbor.Marshal(mismatched)...), ErrMalformedRelayBundle},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ev WitnessesSignedEvent
+			if err := ev.UnmarshalRelayBundle(tc.data); !errors.Is(err, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
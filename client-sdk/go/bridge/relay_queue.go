@@ -0,0 +1,275 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// ErrRelayRejected is the error a Relayer wraps to signal that a signed operation can never be
+// relayed (e.g., because the remote side considers it invalid), so it is dropped from the relay
+// queue instead of being retried.
+var ErrRelayRejected = errors.New("bridge: relay rejected")
+
+// Relayer relays signed operations to the remote side.
+type Relayer interface {
+	// Relay relays the signed operation and its witness signatures to the remote side.
+	//
+	// As a signed operation may be relayed again after a restart, relaying an operation that
+	// has already been relayed must succeed.
+	Relay(ctx context.Context, ev *WitnessesSignedEvent) error
+}
+
+// RelayRetryPolicy configures how relaying a signed operation is retried. Relaying is retried
+// until it succeeds or the relay queue is stopped.
+type RelayRetryPolicy struct {
+	// InitialInterval is the delay before the first retry. Subsequent retries are delayed
+	// exponentially.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum delay between retries.
+	MaxInterval time.Duration
+}
+
+// DefaultRelayRetryPolicy is the default relay retry policy.
+var DefaultRelayRetryPolicy = RelayRetryPolicy{
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     1 * time.Minute,
+}
+
+func (p *RelayRetryPolicy) backOff(ctx context.Context, clock Clock) backoff.BackOff {
+	eb := backoff.NewExponentialBackOff()
+	eb.Clock = clock
+	eb.InitialInterval = p.InitialInterval
+	eb.MaxInterval = p.MaxInterval
+	eb.MaxElapsedTime = 0
+	return backoff.WithContext(eb, ctx)
+}
+
+// RelayQueueOption is an option for configuring a relay queue.
+type RelayQueueOption func(q *RelayQueue)
+
+// WithRelayRetryPolicy configures how failed relays are retried.
+//
+// By default DefaultRelayRetryPolicy is used.
+func WithRelayRetryPolicy(policy RelayRetryPolicy) RelayQueueOption {
+	return func(q *RelayQueue) {
+		q.retry = policy
+	}
+}
+
+// WithRelayQueueClock configures the clock used to delay retries and measure relay latency.
+//
+// By default RealClock is used.
+func WithRelayQueueClock(clock Clock) RelayQueueOption {
+	return func(q *RelayQueue) {
+		q.clock = clock
+	}
+}
+
+// WithRelayQueueLogger configures the logger used by the relay queue.
+//
+// By default a logger for the "bridge" module is used.
+func WithRelayQueueLogger(l *logging.Logger) RelayQueueOption {
+	return func(q *RelayQueue) {
+		q.logger = l
+	}
+}
+
+type queuedRelay struct {
+	ev     *WitnessesSignedEvent
+	queued time.Time
+}
+
+// RelayQueue decouples observing signed operations from relaying them to the remote side. Signed
+// operations are persisted in a SignatureStore when queued and only deleted once they have been
+// relayed, so that queued operations survive restarts.
+//
+// Operations are relayed one at a time in the order of their identifiers. Locks and releases are
+// numbered by separate sequences and queued independently (see SignedOperationKey).
+type RelayQueue struct {
+	store   SignatureStore
+	relayer Relayer
+	retry   RelayRetryPolicy
+	clock   Clock
+	logger  *logging.Logger
+
+	lock    sync.Mutex
+	pending map[SignedOperationKey]*queuedRelay
+
+	notifyCh chan struct{}
+}
+
+// Enqueue persists the signed operation and queues it for relaying. Queueing an operation that
+// is still queued replaces its signatures, while queueing it again once it has been relayed
+// relays it again.
+func (q *RelayQueue) Enqueue(ev *WitnessesSignedEvent) error {
+	if err := q.store.Put(ev); err != nil {
+		return fmt.Errorf("bridge: failed to queue signed operation %s: %w", ev.Key(), err)
+	}
+	q.add(ev, q.clock.Now())
+
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Watch queues each signed operation observed via V1.WatchEventsFiltered with the given options
+// until the context is canceled or the subscription fails.
+func (q *RelayQueue) Watch(ctx context.Context, v V1, opts ...WatchOption) error {
+	ch, sub, err := v.WatchEventsFiltered(ctx, OnlyWitnessesSigned(), opts...)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to subscribe to signed operations: %w", err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-ch:
+			if !ok {
+				return sub.Err()
+			}
+			if err = q.Enqueue(ev.WitnessesSigned); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Len returns the number of signed operations waiting to be relayed.
+func (q *RelayQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return len(q.pending)
+}
+
+// Run relays queued signed operations, including those persisted before a restart, until the
+// context is canceled. Failed relays are retried according to the retry policy; operations
+// rejected by the relayer (see ErrRelayRejected) are dropped.
+//
+// Run must not be called concurrently.
+func (q *RelayQueue) Run(ctx context.Context) error {
+	stored, err := q.store.List()
+	if err != nil {
+		return fmt.Errorf("bridge: failed to load queued signed operations: %w", err)
+	}
+	now := q.clock.Now()
+	for _, ev := range stored {
+		q.add(ev, now)
+	}
+
+	for {
+		next := q.next()
+		if next == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-q.notifyCh:
+				continue
+			}
+		}
+
+		if err = q.relay(ctx, next.ev); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			q.logger.Error("dropping rejected signed operation",
+				"err", err,
+				"kind", next.ev.Kind(),
+				"id", next.ev.ID,
+			)
+		} else {
+			relayLatency.Observe(q.clock.Now().Sub(next.queued).Seconds())
+			q.logger.Info("relayed signed operation",
+				"kind", next.ev.Kind(),
+				"id", next.ev.ID,
+			)
+		}
+
+		if err = q.store.Delete(next.ev.Key()); err != nil {
+			return fmt.Errorf("bridge: failed to remove relayed signed operation %s: %w", next.ev.Key(), err)
+		}
+		q.remove(next.ev.Key())
+	}
+}
+
+func (q *RelayQueue) relay(ctx context.Context, ev *WitnessesSignedEvent) error {
+	return backoff.RetryNotifyWithTimer(func() error {
+		err := q.relayer.Relay(ctx, ev)
+		if errors.Is(err, ErrRelayRejected) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, q.retry.backOff(ctx, q.clock), func(err error, d time.Duration) {
+		q.logger.Warn("failed to relay signed operation, retrying",
+			"err", err,
+			"kind", ev.Kind(),
+			"id", ev.ID,
+			"retry_in", d,
+		)
+	}, &backoffTimer{clock: q.clock})
+}
+
+func (q *RelayQueue) add(ev *WitnessesSignedEvent, queued time.Time) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	key := ev.Key()
+	if existing, ok := q.pending[key]; ok {
+		queued = existing.queued
+	}
+	q.pending[key] = &queuedRelay{ev: ev, queued: queued}
+	relayQueueDepth.Set(float64(len(q.pending)))
+}
+
+// next returns the queued operation with the lowest key or nil if the queue is empty.
+func (q *RelayQueue) next() *queuedRelay {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var next *queuedRelay
+	for _, r := range q.pending {
+		if next == nil || r.ev.Key().less(next.ev.Key()) {
+			next = r
+		}
+	}
+	return next
+}
+
+func (q *RelayQueue) remove(key SignedOperationKey) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.pending, key)
+	relayQueueDepth.Set(float64(len(q.pending)))
+}
+
+// NewRelayQueue creates a relay queue persisting signed operations in the given store and
+// relaying them with the given relayer once Run is called.
+func NewRelayQueue(store SignatureStore, relayer Relayer, opts ...RelayQueueOption) *RelayQueue {
+	q := &RelayQueue{
+		store:    store,
+		relayer:  relayer,
+		retry:    DefaultRelayRetryPolicy,
+		clock:    RealClock,
+		logger:   logger,
+		pending:  make(map[SignedOperationKey]*queuedRelay),
+		notifyCh: make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	initMetrics()
+	return q
+}
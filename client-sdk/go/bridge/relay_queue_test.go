@@ -0,0 +1,248 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// testRelayer is a relayer that fails the first attempt of each lock in flaky, rejects the locks
+// in rejected and records the successfully relayed operations.
+type testRelayer struct {
+	sync.Mutex
+
+	flaky    map[uint64]bool
+	rejected map[uint64]bool
+	relayed  []SignedOperationKey
+	done     chan struct{}
+}
+
+func (r *testRelayer) Relay(ctx context.Context, ev *WitnessesSignedEvent) error {
+	r.Lock()
+	defer r.Unlock()
+
+	switch {
+	case r.flaky[ev.ID] && ev.IsLock():
+		r.flaky[ev.ID] = false
+		return fmt.Errorf("remote chain unavailable")
+	case r.rejected[ev.ID] && ev.IsLock():
+		r.done <- struct{}{}
+		return fmt.Errorf("%w: invalid signatures", ErrRelayRejected)
+	default:
+		r.relayed = append(r.relayed, ev.Key())
+		r.done <- struct{}{}
+		return nil
+	}
+}
+
+func newTestSignedOperation(id uint64) *WitnessesSignedEvent {
+	return &WitnessesSignedEvent{
+		ID: id,
+		Op: NewLockOperation(Lock{
+			Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(id), types.NativeDenomination),
+		}),
+		Witnesses:  []uint16{0},
+		Signatures: [][]byte{{byte(id)}},
+	}
+}
+
+func newTestSignedRelease(id uint64) *WitnessesSignedEvent {
+	return &WitnessesSignedEvent{
+		ID: id,
+		Op: NewReleaseOperation(Release{
+			ID:     id,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(id), types.NativeDenomination),
+		}),
+		Witnesses:  []uint16{1},
+		Signatures: [][]byte{{byte(id), 0xff}},
+	}
+}
+
+func lockKey(id uint64) SignedOperationKey {
+	return SignedOperationKey{Kind: OperationLock, ID: id}
+}
+
+func releaseKey(id uint64) SignedOperationKey {
+	return SignedOperationKey{Kind: OperationRelease, ID: id}
+}
+
+func TestFileSignatureStore(t *testing.T) {
+	store, err := NewFileSignatureStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create signature store: %s", err)
+	}
+	for _, ev := range []*WitnessesSignedEvent{
+		newTestSignedOperation(10),
+		newTestSignedRelease(2),
+		newTestSignedOperation(2),
+		newTestSignedOperation(1),
+	} {
+		if err = store.Put(ev); err != nil {
+			t.Fatalf("Put(%s): %s", ev.Key(), err)
+		}
+	}
+	if err = store.Delete(lockKey(1)); err != nil {
+		t.Fatalf("Delete: %s", err)
+	}
+	if err = store.Delete(lockKey(1)); err != nil {
+		t.Fatalf("deleting a missing operation failed: %s", err)
+	}
+
+	// A lock and a release with the same identifier are stored separately.
+	events, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %s", err)
+	}
+	expected := []*WitnessesSignedEvent{
+		newTestSignedOperation(2),
+		newTestSignedRelease(2),
+		newTestSignedOperation(10),
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("unexpected stored operations: %+v", events)
+	}
+}
+
+func TestRelayQueue(t *testing.T) {
+	store, err := NewFileSignatureStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create signature store: %s", err)
+	}
+	policy := WithRelayRetryPolicy(RelayRetryPolicy{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	})
+
+	// Operations queued before a restart are relayed once the queue runs again.
+	q := NewRelayQueue(store, &testRelayer{}, policy)
+	if err = q.Enqueue(newTestSignedOperation(3)); err != nil {
+		t.Fatalf("Enqueue: %s", err)
+	}
+
+	relayer := &testRelayer{
+		flaky:    map[uint64]bool{1: true},
+		rejected: map[uint64]bool{2: true},
+		done:     make(chan struct{}, 4),
+	}
+	q = NewRelayQueue(store, relayer, policy)
+	for _, id := range []uint64{2, 1} {
+		if err = q.Enqueue(newTestSignedOperation(id)); err != nil {
+			t.Fatalf("Enqueue: %s", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- q.Run(ctx)
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-relayer.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for relays")
+		}
+	}
+
+	// A later operation is relayed while running.
+	if err = q.Enqueue(newTestSignedOperation(4)); err != nil {
+		t.Fatalf("Enqueue: %s", err)
+	}
+	select {
+	case <-relayer.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for relay")
+	}
+
+	cancel()
+	if err = <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	relayer.Lock()
+	defer relayer.Unlock()
+	if expected := []SignedOperationKey{lockKey(1), lockKey(3), lockKey(4)}; !reflect.DeepEqual(relayer.relayed, expected) {
+		t.Fatalf("expected relayed operations %v, got %v", expected, relayer.relayed)
+	}
+	if n := q.Len(); n != 0 {
+		t.Fatalf("expected an empty queue, got %d operations", n)
+	}
+	events, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %s", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected relayed and rejected operations to be removed from the store, got %d", len(events))
+	}
+}
+
+func TestRelayQueueLockAndRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		store func(t *testing.T) SignatureStore
+	}{
+		{"Memory", func(t *testing.T) SignatureStore { return NewMemorySignatureStore() }},
+		{"File", func(t *testing.T) SignatureStore {
+			store, err := NewFileSignatureStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create signature store: %s", err)
+			}
+			return store
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tc.store(t)
+			relayer := &testRelayer{done: make(chan struct{}, 2)}
+			q := NewRelayQueue(store, relayer)
+
+			// Release #7 must not replace lock #7.
+			for _, ev := range []*WitnessesSignedEvent{newTestSignedOperation(7), newTestSignedRelease(7)} {
+				if err := q.Enqueue(ev); err != nil {
+					t.Fatalf("Enqueue(%s): %s", ev.Key(), err)
+				}
+			}
+			if n := q.Len(); n != 2 {
+				t.Fatalf("expected 2 queued operations, got %d", n)
+			}
+			events, err := store.List()
+			if err != nil {
+				t.Fatalf("List: %s", err)
+			}
+			if expected := []*WitnessesSignedEvent{newTestSignedOperation(7), newTestSignedRelease(7)}; !reflect.DeepEqual(events, expected) {
+				t.Fatalf("unexpected stored operations: %+v", events)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- q.Run(ctx)
+			}()
+			for i := 0; i < 2; i++ {
+				select {
+				case <-relayer.done:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for relays")
+				}
+			}
+			cancel()
+			<-errCh
+
+			relayer.Lock()
+			defer relayer.Unlock()
+			if expected := []SignedOperationKey{lockKey(7), releaseKey(7)}; !reflect.DeepEqual(relayer.relayed, expected) {
+				t.Fatalf("expected relayed operations %v, got %v", expected, relayer.relayed)
+			}
+		})
+	}
+}
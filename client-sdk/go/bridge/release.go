@@ -0,0 +1,126 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+)
+
+// ReleaseStatus is the outcome of submitting a release.
+type ReleaseStatus uint8
+
+const (
+	// ReleasePending means that the signature has been recorded, but the submitted transaction
+	// did not execute the release as not enough witnesses had signed it yet. The release may
+	// since have been executed by the signature of another witness.
+	ReleasePending ReleaseStatus = iota
+	// ReleaseCreated means that the submitted signature reached the threshold and the submitted
+	// transaction executed the release, i.e. it emitted the ReleaseEvent.
+	ReleaseCreated
+	// ReleaseAlreadySigned means that the signer has already signed the release before and
+	// nothing has been submitted.
+	ReleaseAlreadySigned
+	// ReleaseAlreadyProcessed means that the release with the given sequence number has already
+	// been executed and nothing has been submitted.
+	ReleaseAlreadyProcessed
+)
+
+// String returns a string representation of the release status.
+func (s ReleaseStatus) String() string {
+	switch s {
+	case ReleasePending:
+		return "pending"
+	case ReleaseCreated:
+		return "created"
+	case ReleaseAlreadySigned:
+		return "already signed"
+	case ReleaseAlreadyProcessed:
+		return "already processed"
+	default:
+		return fmt.Sprintf("[unknown release status: %d]", uint8(s))
+	}
+}
+
+// isReleaseProcessed returns true iff the release with the given incoming sequence number has
+// already been executed.
+func (a *v1) isReleaseProcessed(ctx context.Context, id uint64) (bool, error) {
+	seq, err := a.NextSequenceNumbers(ctx, client.RoundLatest)
+	if err != nil {
+		return false, fmt.Errorf("failed to query next sequence numbers: %w", err)
+	}
+	return seq.Incoming > id, nil
+}
+
+// releaseEmittedBy returns true iff the transaction with the given hash, executed in one of the
+// rounds since the given round, emitted the release event for the given sequence number.
+func (a *v1) releaseEmittedBy(ctx context.Context, fromRound, id uint64, txHash hash.Hash) (bool, error) {
+	latest, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+	for round := fromRound; round <= latest.Header.Round; round++ {
+		events, err := a.GetEvents(ctx, round)
+		if err != nil {
+			return false, err
+		}
+		for _, ev := range events {
+			if ev.Release != nil && ev.Release.ID == id && ev.TxHash.Equal(&txHash) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// Implements V1.
+func (a *v1) SubmitRelease(
+	ctx context.Context,
+	signer signature.Signer,
+	body *Release,
+	opts ...SubmitOption,
+) (ReleaseStatus, error) {
+	processed, err := a.isReleaseProcessed(ctx, body.ID)
+	if err != nil {
+		return 0, err
+	}
+	if processed {
+		return ReleaseAlreadyProcessed, nil
+	}
+
+	before, err := a.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+	var txHash hash.Hash
+	opts = append(opts, WithSubmittedTxHash(&txHash))
+	_, err = signAndSubmit[struct{}](ctx, a, signer, a.method(methodRelease), body, opts...)
+	switch {
+	case err == nil:
+	case IsModuleErrorFor(err, a.module, ErrAlreadySubmittedSignatureCode):
+		return ReleaseAlreadySigned, nil
+	case IsModuleErrorFor(err, a.module, ErrInvalidSequenceNumberCode):
+		// The release may have been executed after we checked.
+		if processed, perr := a.isReleaseProcessed(ctx, body.ID); perr == nil && processed {
+			return ReleaseAlreadyProcessed, nil
+		}
+		return 0, err
+	default:
+		return 0, err
+	}
+
+	// The release may also have been executed by the signature of another witness processed
+	// right after the submitted one, so only the event emitted by the submitted transaction
+	// tells whether it executed the release.
+	created, err := a.releaseEmittedBy(ctx, before.Header.Round+1, body.ID, txHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up release event: %w", err)
+	}
+	if created {
+		return ReleaseCreated, nil
+	}
+	return ReleasePending, nil
+}
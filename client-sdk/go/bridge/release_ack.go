@@ -0,0 +1,89 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+var (
+	// ErrNoRemoteRuntime is the error returned when querying the remote side of the bridge without
+	// a remote runtime being configured (see WithRemoteRuntime).
+	ErrNoRemoteRuntime = errors.New("bridge: remote runtime not configured")
+
+	// ErrReleaseNotAcknowledged is the error returned when a lock has not been released on the
+	// remote side of the bridge (yet).
+	ErrReleaseNotAcknowledged = errors.New("bridge: release not acknowledged")
+)
+
+// ReleaseAck is the acknowledgement that a lock has been released on the remote side of the
+// bridge.
+type ReleaseAck struct {
+	// LockID is the identifier of the lock on this side of the bridge.
+	LockID uint64 `json:"lock_id"`
+
+	// Release is the release event emitted by the remote runtime. Its Round is the remote runtime
+	// round in which the lock has been released.
+	Release *ReleaseEvent `json:"release"`
+}
+
+// WithRemoteRuntime configures the client of the runtime on the remote side of the bridge, e.g.
+// in case both sides of the bridge are Oasis runtimes, and the options for its bridge module
+// client. It is used to correlate locks with their releases (see GetReleaseAck).
+func WithRemoteRuntime(rc client.RuntimeClient, opts ...V1Option) V1Option {
+	return func(a *v1) {
+		a.remote = newV1(rc, ModuleName, opts...)
+	}
+}
+
+// Implements V1.
+func (a *v1) GetReleaseAck(ctx context.Context, round, lockID uint64) (*ReleaseAck, error) {
+	if a.remote == nil {
+		return nil, ErrNoRemoteRuntime
+	}
+
+	seq, err := a.NextSequenceNumbers(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query next sequence numbers: %w", err)
+	}
+	if seq.Outgoing <= lockID {
+		return nil, fmt.Errorf("%w: %d", ErrLockNotFound, lockID)
+	}
+
+	if round == client.RoundLatest {
+		blk, err := a.remote.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest remote block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	// The outgoing sequence number of a lock is the incoming sequence number of the release on
+	// the remote side.
+	released := func(seq *NextSequenceNumbers) bool {
+		return seq.Incoming > lockID
+	}
+	releaseRound, ok, err := a.remote.findSequenceRound(ctx, round, released)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("remote: %w", err)
+	case !ok:
+		return nil, fmt.Errorf("%w: %d", ErrReleaseNotAcknowledged, lockID)
+	}
+
+	events, err := a.remote.GetEvents(ctx, releaseRound)
+	if err != nil {
+		return nil, fmt.Errorf("remote: %w", err)
+	}
+	for _, ev := range events {
+		if ev.Release != nil && ev.Release.ID == lockID {
+			return &ReleaseAck{
+				LockID:  lockID,
+				Release: ev.Release,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d (no release event in remote round %d)", ErrReleaseNotAcknowledged, lockID, releaseRound)
+}
@@ -0,0 +1,79 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// sequenceClient is a runtime client in which a lock and a release are made in each round, the
+// ones with identifier n being made in round n+1.
+type sequenceClient struct {
+	client.RuntimeClient
+
+	latest uint64
+}
+
+func (rc *sequenceClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *sequenceClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	return &block.Block{}, nil
+}
+
+func (rc *sequenceClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	if round == client.RoundLatest {
+		round = rc.latest
+	}
+	*rsp.(*NextSequenceNumbers) = NextSequenceNumbers{Incoming: round, Outgoing: round}
+	return nil
+}
+
+func (rc *sequenceClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	if round == 0 {
+		return nil, nil
+	}
+	return []*coreClient.Event{
+		{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: round - 1})},
+		{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: round - 1})},
+	}, nil
+}
+
+func TestGetReleaseAck(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewV1(&sequenceClient{latest: 10}).GetReleaseAck(ctx, client.RoundLatest, 1); !errors.Is(err, ErrNoRemoteRuntime) {
+		t.Fatalf("expected ErrNoRemoteRuntime, got %v", err)
+	}
+
+	v := NewV1(&sequenceClient{latest: 10}, WithRemoteRuntime(&sequenceClient{latest: 5}))
+	ack, err := v.GetReleaseAck(ctx, client.RoundLatest, 3)
+	if err != nil {
+		t.Fatalf("failed to get release acknowledgement: %s", err)
+	}
+	if ack.LockID != 3 || ack.Release.ID != 3 || ack.Release.Round != 4 {
+		t.Fatalf("unexpected release acknowledgement: %+v", ack.Release)
+	}
+
+	// Release 3 has not been made yet as of remote round 3.
+	if _, err = v.GetReleaseAck(ctx, 3, 3); !errors.Is(err, ErrReleaseNotAcknowledged) {
+		t.Fatalf("expected ErrReleaseNotAcknowledged, got %v", err)
+	}
+	// Lock 7 exists, but has not been released yet.
+	if _, err = v.GetReleaseAck(ctx, client.RoundLatest, 7); !errors.Is(err, ErrReleaseNotAcknowledged) {
+		t.Fatalf("expected ErrReleaseNotAcknowledged, got %v", err)
+	}
+	// Lock 10 does not exist yet.
+	if _, err = v.GetReleaseAck(ctx, client.RoundLatest, 10); !errors.Is(err, ErrLockNotFound) {
+		t.Fatalf("expected ErrLockNotFound, got %v", err)
+	}
+}
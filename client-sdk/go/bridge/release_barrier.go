@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrReleaseBarrierTimeout is the error returned when not all witnesses have completed their
+// release before the context deadline (see ReleaseBarrier.Wait).
+var ErrReleaseBarrierTimeout = errors.New("bridge: timed out waiting for witnesses to release")
+
+// ReleaseBarrier coordinates the witnesses of a multi-witness release. As the incoming sequence
+// number only advances once enough witnesses have submitted their bridge.Release transaction for
+// it, each witness marks its release as complete via Done and then waits for the others via
+// Wait before releasing with the next sequence number.
+//
+// A release barrier is used for a single release. Use a new barrier for each subsequent release.
+type ReleaseBarrier struct {
+	lock      sync.Mutex
+	witnesses int
+	completed int
+	done      chan struct{}
+}
+
+// Done marks the release of one of the witnesses as complete. Calling Done more often than the
+// number of witnesses the barrier has been created for panics.
+func (b *ReleaseBarrier) Done() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.completed >= b.witnesses {
+		panic("bridge: more releases completed than witnesses")
+	}
+	b.completed++
+	if b.completed == b.witnesses {
+		close(b.done)
+	}
+}
+
+// Wait waits until all witnesses have completed their release or the context is canceled. In
+// case the context deadline expires first, an error wrapping ErrReleaseBarrierTimeout is returned
+// which reports how many witnesses have completed their release.
+func (b *ReleaseBarrier) Wait(ctx context.Context) error {
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Prefer reporting success in case both happened at the same time.
+	select {
+	case <-b.done:
+		return nil
+	default:
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return fmt.Errorf("%w: %d of %d witnesses completed", ErrReleaseBarrierTimeout, b.completed, b.witnesses)
+}
+
+// NewReleaseBarrier creates a release barrier for the given number of witnesses.
+func NewReleaseBarrier(witnesses int) *ReleaseBarrier {
+	b := &ReleaseBarrier{
+		witnesses: witnesses,
+		done:      make(chan struct{}),
+	}
+	if witnesses <= 0 {
+		close(b.done)
+	}
+	return b
+}
@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReleaseBarrier(t *testing.T) {
+	b := NewReleaseBarrier(2)
+
+	waitErr := make(chan error, 1)
+	go func() {
+		b.Done()
+		waitErr <- b.Wait(context.Background())
+	}()
+	select {
+	case err := <-waitErr:
+		t.Fatalf("Wait returned before all witnesses released: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	b.Done()
+	if err := <-waitErr; err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait after completion: %s", err)
+	}
+
+	// A witness that never releases makes waiting time out.
+	b = NewReleaseBarrier(2)
+	b.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, ErrReleaseBarrierTimeout) {
+		t.Fatalf("expected ErrReleaseBarrierTimeout, got %v", err)
+	}
+
+	// Cancellation is reported as such.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// releaseOutcome is what happens when a release transaction is submitted to a releaseClient.
+type releaseOutcome uint8
+
+const (
+	// releaseRecorded records the signature without executing the release.
+	releaseRecorded releaseOutcome = iota
+	// releaseExecuted executes the release with the submitted signature.
+	releaseExecuted
+	// releaseExecutedByOther records the signature after which the signature of another witness
+	// executes the release in the same round.
+	releaseExecutedByOther
+)
+
+// releaseClient is a runtime client that executes each submitted release transaction in a new
+// round according to the configured outcome.
+type releaseClient struct {
+	watchClient
+
+	lock     sync.Mutex
+	latest   uint64
+	incoming uint64
+	events   map[uint64][]*coreClient.Event
+	outcome  releaseOutcome
+	err      error
+	txs      int
+}
+
+func (rc *releaseClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	return &types.RuntimeInfo{ChainContext: "test"}, nil
+}
+
+func (rc *releaseClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *releaseClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	return rc.events[round], nil
+}
+
+func (rc *releaseClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	switch method {
+	case ModuleName + "." + methodParameters:
+		*rsp.(*Parameters) = Parameters{LocalDenominations: []types.Denomination{types.NativeDenomination}}
+	case ModuleName + "." + methodNextSequenceNumbers:
+		*rsp.(*NextSequenceNumbers) = NextSequenceNumbers{Incoming: rc.incoming}
+	case "accounts.Nonce", "core.EstimateGas":
+		*rsp.(*uint64) = 0
+	default:
+		return fmt.Errorf("unexpected query: %s", method)
+	}
+	return nil
+}
+
+func (rc *releaseClient) SubmitTx(ctx context.Context, utx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	rc.txs++
+	if rc.err != nil {
+		return nil, rc.err
+	}
+
+	rc.latest++
+	release := cbor.Marshal(&ReleaseEvent{ID: rc.incoming})
+	switch rc.outcome {
+	case releaseExecuted:
+		rc.events[rc.latest] = []*coreClient.Event{{Key: ReleaseEventKey, Value: release, TxHash: TxHash(utx)}}
+		rc.incoming++
+	case releaseExecutedByOther:
+		rc.events[rc.latest] = []*coreClient.Event{{Key: ReleaseEventKey, Value: release, TxHash: hash.NewFromBytes([]byte("other"))}}
+		rc.incoming++
+	}
+	return nil, nil
+}
+
+func TestSubmitRelease(t *testing.T) {
+	body := &Release{
+		ID:     3,
+		Target: sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		incoming uint64
+		outcome  releaseOutcome
+		err      error
+		expected ReleaseStatus
+		txs      int
+	}{
+		{"Pending", 3, releaseRecorded, nil, ReleasePending, 1},
+		{"Created", 3, releaseExecuted, nil, ReleaseCreated, 1},
+		// A release executed by the signature of another witness has not been created by us.
+		{"ExecutedByOther", 3, releaseExecutedByOther, nil, ReleasePending, 1},
+		{"AlreadySigned", 3, releaseRecorded, &types.FailedCallResult{Module: ModuleName, Code: ErrAlreadySubmittedSignatureCode}, ReleaseAlreadySigned, 1},
+		{"AlreadyProcessed", 4, releaseRecorded, nil, ReleaseAlreadyProcessed, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &releaseClient{
+				latest:   10,
+				incoming: tc.incoming,
+				events:   make(map[uint64][]*coreClient.Event),
+				outcome:  tc.outcome,
+				err:      tc.err,
+			}
+			status, err := NewV1(rc).SubmitRelease(context.Background(), sdkTesting.Alice.Signer, body)
+			if err != nil {
+				t.Fatalf("SubmitRelease: %s", err)
+			}
+			if status != tc.expected {
+				t.Fatalf("expected status %s, got %s", tc.expected, status)
+			}
+			if rc.txs != tc.txs {
+				t.Fatalf("expected %d submitted transactions, got %d", tc.txs, rc.txs)
+			}
+		})
+	}
+}
+
+func TestScanReleases(t *testing.T) {
+	// Releases are made in rounds 1, 3, 4, 7 and 10 with the round as the identifier and the
+	// latest round is 10.
+	rc := &releaseClient{
+		latest: 10,
+		events: make(map[uint64][]*coreClient.Event),
+	}
+	for _, round := range []uint64{1, 3, 4, 7, 10} {
+		rc.events[round] = []*coreClient.Event{{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: round})}}
+	}
+	rc.events[5] = []*coreClient.Event{{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 5})}}
+	v := NewV1(rc)
+
+	for _, tc := range []struct {
+		name      string
+		fromRound uint64
+		toRound   uint64
+		chunkSize uint64
+		expected  [][]uint64
+	}{
+		{"Chunked", 1, 10, 3, [][]uint64{{1, 3}, {4}, {7}, {10}}},
+		{"RangeStart", 3, 10, 3, [][]uint64{{3, 4}, {7}, {10}}},
+		{"RangeEnd", 1, 7, 3, [][]uint64{{1, 3}, {4}, {7}}},
+		{"SmallerThanChunk", 2, 5, 100, [][]uint64{{3, 4}}},
+		{"SingleRound", 4, 4, 3, [][]uint64{{4}}},
+		{"EmptyChunksSkipped", 5, 9, 1, [][]uint64{{7}}},
+		{"NoReleases", 5, 6, 2, nil},
+		{"Latest", 8, client.RoundLatest, 2, [][]uint64{{10}}},
+		{"DefaultChunkSize", 1, 10, 0, [][]uint64{{1, 3, 4, 7, 10}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var (
+				chunks [][]uint64
+				all    []uint64
+			)
+			err := v.ScanReleases(ctx, tc.fromRound, tc.toRound, tc.chunkSize, func(releases []*ReleaseEvent) error {
+				var chunk []uint64
+				for _, ev := range releases {
+					chunk = append(chunk, ev.ID)
+				}
+				chunks = append(chunks, chunk)
+				all = append(all, chunk...)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ScanReleases: %s", err)
+			}
+			if !reflect.DeepEqual(chunks, tc.expected) {
+				t.Fatalf("expected chunks %v, got %v", tc.expected, chunks)
+			}
+
+			releases, err := v.ListReleases(ctx, tc.fromRound, tc.toRound)
+			if err != nil {
+				t.Fatalf("ListReleases: %s", err)
+			}
+			var listed []uint64
+			for _, ev := range releases {
+				if ev.Round != ev.ID {
+					t.Fatalf("expected release %d to be made in round %d, got %d", ev.ID, ev.ID, ev.Round)
+				}
+				listed = append(listed, ev.ID)
+			}
+			if !reflect.DeepEqual(listed, all) {
+				t.Fatalf("expected releases %v, got %v", all, listed)
+			}
+		})
+	}
+
+	// Invalid ranges are rejected.
+	if _, err := v.ListReleases(context.Background(), 5, 4); err == nil {
+		t.Fatalf("expected invalid round range to be rejected")
+	}
+
+	// Scanning stops at the first error returned by the callback.
+	errStop := errors.New("stop")
+	var calls int
+	err := v.ScanReleases(context.Background(), 1, 10, 3, func([]*ReleaseEvent) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Fatalf("expected scanning to stop after the first chunk, got %d calls (err: %v)", calls, err)
+	}
+}
@@ -0,0 +1,118 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrMalformedRemoteAddress is the error returned when a remote address is not valid according to
+// the configured remote address codec.
+var ErrMalformedRemoteAddress = errors.New("bridge: malformed remote address")
+
+// RemoteAddress is an address on the remote side of the bridge.
+//
+// Its width and text format depend on the remote chain (see RemoteAddressCodec). Independent of
+// the codec, RemoteAddress is encoded as a byte string in CBOR and as hex in text form.
+type RemoteAddress []byte
+
+// String returns a string representation of the remote address.
+func (ra RemoteAddress) String() string {
+	return hex.EncodeToString(ra)
+}
+
+// Equal compares vs another remote address for equality.
+func (ra RemoteAddress) Equal(other RemoteAddress) bool {
+	return bytes.Equal(ra, other)
+}
+
+// MarshalText encodes the remote address into text form.
+func (ra RemoteAddress) MarshalText() ([]byte, error) {
+	return []byte(ra.String()), nil
+}
+
+// UnmarshalText decodes a text marshalled (hex-encoded) remote address.
+//
+// The width of the address is not checked, use the RemoteAddressCodec of the remote chain to
+// validate it.
+func (ra *RemoteAddress) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedRemoteAddress, err)
+	}
+	*ra = RemoteAddress(b)
+	return nil
+}
+
+// NewRemoteAddressFromHex creates a new remote address from a hex-encoded string using the
+// default remote address codec or panics.
+func NewRemoteAddressFromHex(text string) RemoteAddress {
+	ra, err := DefaultRemoteAddressCodec.Decode(text)
+	if err != nil {
+		panic(err)
+	}
+	return ra
+}
+
+// RemoteAddressCodec encodes, decodes and validates the addresses of a remote chain.
+type RemoteAddressCodec interface {
+	// Size returns the width of remote addresses in bytes.
+	Size() int
+
+	// Encode returns the text form of the given remote address as used by the remote chain.
+	Encode(ra RemoteAddress) string
+
+	// Decode decodes and validates the text form of a remote address.
+	Decode(text string) (RemoteAddress, error)
+
+	// Validate checks that the given remote address is valid for the remote chain.
+	Validate(ra RemoteAddress) error
+}
+
+// hexAddressCodec is a codec for fixed-width hex-encoded remote addresses.
+type hexAddressCodec struct {
+	size int
+}
+
+// Implements RemoteAddressCodec.
+func (c *hexAddressCodec) Size() int {
+	return c.size
+}
+
+// Implements RemoteAddressCodec.
+func (c *hexAddressCodec) Encode(ra RemoteAddress) string {
+	return ra.String()
+}
+
+// Implements RemoteAddressCodec.
+func (c *hexAddressCodec) Decode(text string) (RemoteAddress, error) {
+	var ra RemoteAddress
+	if err := ra.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(ra); err != nil {
+		return nil, err
+	}
+	return ra, nil
+}
+
+// Implements RemoteAddressCodec.
+func (c *hexAddressCodec) Validate(ra RemoteAddress) error {
+	if len(ra) != c.size {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrMalformedRemoteAddress, c.size, len(ra))
+	}
+	return nil
+}
+
+// NewHexAddressCodec creates a codec for hex-encoded remote addresses of the given width in bytes.
+func NewHexAddressCodec(size int) RemoteAddressCodec {
+	return &hexAddressCodec{size: size}
+}
+
+// EthereumAddressCodec is the codec for 20-byte Ethereum addresses.
+var EthereumAddressCodec = NewHexAddressCodec(20)
+
+// DefaultRemoteAddressCodec is the remote address codec used unless configured otherwise (see
+// WithRemoteAddressCodec).
+var DefaultRemoteAddressCodec = EthereumAddressCodec
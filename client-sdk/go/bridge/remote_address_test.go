@@ -0,0 +1,37 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+func TestRemoteAddressCodec(t *testing.T) {
+	ra, err := EthereumAddressCodec.Decode("0102030405060708090a0b0c0d0e0f1011121314")
+	if err != nil {
+		t.Fatalf("failed to decode Ethereum address: %s", err)
+	}
+	if s := EthereumAddressCodec.Encode(ra); s != "0102030405060708090a0b0c0d0e0f1011121314" {
+		t.Fatalf("unexpected encoding: %s", s)
+	}
+	for _, text := range []string{"01020304", "0102030405060708090a0b0c0d0e0f101112131415", "zz"} {
+		if _, err = EthereumAddressCodec.Decode(text); !errors.Is(err, ErrMalformedRemoteAddress) {
+			t.Fatalf("%s: expected ErrMalformedRemoteAddress, got %v", text, err)
+		}
+	}
+
+	// Wider addresses use the same wire format.
+	codec := NewHexAddressCodec(32)
+	wide, err := codec.Decode("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	if err != nil {
+		t.Fatalf("failed to decode 32-byte address: %s", err)
+	}
+	if err = EthereumAddressCodec.Validate(wide); !errors.Is(err, ErrMalformedRemoteAddress) {
+		t.Fatalf("expected ErrMalformedRemoteAddress, got %v", err)
+	}
+	var decoded RemoteAddress
+	if err = cbor.Unmarshal(cbor.Marshal(wide), &decoded); err != nil || !decoded.Equal(wide) {
+		t.Fatalf("CBOR round trip failed: %s (err: %v)", decoded, err)
+	}
+}
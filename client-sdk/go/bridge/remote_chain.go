@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMissingRemoteChainID is the error returned when no remote chain identifier is configured.
+	ErrMissingRemoteChainID = errors.New("bridge: missing remote chain identifier")
+
+	// ErrRemoteChainMismatch is the error returned when the remote chain identifier does not
+	// match the expected one.
+	ErrRemoteChainMismatch = errors.New("bridge: remote chain mismatch")
+)
+
+// RemoteChainID is an identifier of the remote chain (e.g., an EIP-155 chain ID).
+type RemoteChainID string
+
+// String returns a string representation of the remote chain identifier.
+func (id RemoteChainID) String() string {
+	return string(id)
+}
+
+// Validate checks that the remote chain identifier matches the expected identifier.
+func (id RemoteChainID) Validate(expected RemoteChainID) error {
+	if expected == "" || id == "" {
+		return ErrMissingRemoteChainID
+	}
+	if id != expected {
+		return fmt.Errorf("%w: expected %s, got %s", ErrRemoteChainMismatch, expected, id)
+	}
+	return nil
+}
+
+// ValidateRemoteChainID checks that the given configured remote chain identifier (e.g., the
+// chain a relayer is connected to) matches the one in the parameters.
+//
+// In case the parameters do not specify a remote chain identifier, only the presence of the
+// configured identifier is checked.
+func (p *Parameters) ValidateRemoteChainID(configured RemoteChainID) error {
+	if p.RemoteChainID == "" {
+		if configured == "" {
+			return ErrMissingRemoteChainID
+		}
+		return nil
+	}
+	return configured.Validate(p.RemoteChainID)
+}
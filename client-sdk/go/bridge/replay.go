@@ -0,0 +1,186 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBackfillWindow is the default maximum number of rounds fetched ahead of the round
+	// being replayed.
+	defaultBackfillWindow = 64
+
+	// defaultBackfillProgressInterval is the default interval at which backfill progress is
+	// reported.
+	defaultBackfillProgressInterval = 10 * time.Second
+)
+
+// ErrBackfillBelowMinRound is the error returned when replaying events would start below the
+// configured minimum round (see WithBackfillMinRound).
+var ErrBackfillBelowMinRound = errors.New("bridge: backfill starts below minimum round")
+
+// WithBackfillConcurrency configures the number of rounds whose events are fetched concurrently
+// when replaying events (see ReplayEvents).
+//
+// By default the events of 4 rounds are fetched concurrently.
+func WithBackfillConcurrency(n int) V1Option {
+	return func(a *v1) {
+		if n > 0 {
+			a.backfillConcurrency = n
+		}
+	}
+}
+
+// WithBackfillWindow configures the maximum number of rounds whose events are fetched ahead of
+// the round being replayed, which bounds the memory used for reordering fetched rounds when
+// replaying events (see ReplayEvents). The window is never smaller than the backfill
+// concurrency.
+//
+// By default the window is 64 rounds.
+func WithBackfillWindow(n int) V1Option {
+	return func(a *v1) {
+		if n > 0 {
+			a.backfillWindow = n
+		}
+	}
+}
+
+// WithBackfillMinRound configures the lowest round from which events may be replayed (see
+// ReplayEvents). Replaying from an earlier round fails with ErrBackfillBelowMinRound, which guards
+// against accidentally replaying the entire history of a long-lived chain (e.g. via Snapshot and
+// Accounting which replay from the genesis round).
+//
+// By default there is no minimum round.
+func WithBackfillMinRound(round uint64) V1Option {
+	return func(a *v1) {
+		a.backfillMinRound = round
+	}
+}
+
+// WithOnBackfillProgress configures a callback invoked with the last replayed round and the final
+// round while replaying events (see ReplayEvents), at most once per progress interval (see
+// WithBackfillProgressInterval) and once the final round has been replayed.
+//
+// The callback is invoked synchronously from the replay loop and should not block.
+func WithOnBackfillProgress(fn func(current, target uint64)) V1Option {
+	return func(a *v1) {
+		a.onBackfillProgress = fn
+	}
+}
+
+// WithBackfillProgressInterval configures the interval at which backfill progress is reported
+// (see WithOnBackfillProgress).
+//
+// By default progress is reported every 10 seconds.
+func WithBackfillProgressInterval(d time.Duration) V1Option {
+	return func(a *v1) {
+		if d > 0 {
+			a.backfillProgressInterval = d
+		}
+	}
+}
+
+type replayResult struct {
+	round  uint64
+	events []*Event
+	err    error
+}
+
+// Implements V1.
+func (a *v1) ReplayEvents(
+	ctx context.Context,
+	fromRound, toRound uint64,
+	fn func(round uint64, events []*Event) error,
+) error {
+	if fromRound < a.backfillMinRound {
+		return fmt.Errorf("%w: %d (min %d)", ErrBackfillBelowMinRound, fromRound, a.backfillMinRound)
+	}
+	toRound, err := a.resolveRoundRange(ctx, fromRound, toRound)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	window := a.backfillWindow
+	if window < a.backfillConcurrency {
+		window = a.backfillConcurrency
+	}
+
+	// A window token is held from the time a round is dispatched until it is replayed, so at most
+	// window results are ever outstanding and sending them never blocks.
+	tokens := make(chan struct{}, window)
+	sem := make(chan struct{}, a.backfillConcurrency)
+	results := make(chan *replayResult, window)
+	go func() {
+		var wg sync.WaitGroup
+		defer close(results)
+		defer wg.Wait()
+
+		for round := fromRound; ; round++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func(round uint64) {
+				defer wg.Done()
+
+				events, err := a.GetEvents(ctx, round)
+				<-sem
+				results <- &replayResult{round: round, events: events, err: err}
+			}(round)
+
+			if round == toRound {
+				return
+			}
+		}
+	}()
+
+	// Reorder fetched rounds so that they are replayed in order.
+	pending := make(map[uint64][]*Event)
+	next := fromRound
+	lastProgress := a.clock.Now()
+	for res := range results {
+		if res.err != nil {
+			return res.err
+		}
+		pending[res.round] = res.events
+
+		for {
+			events, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if err = fn(next, events); err != nil {
+				return err
+			}
+			<-tokens
+
+			if a.onBackfillProgress != nil {
+				if now := a.clock.Now(); next == toRound || now.Sub(lastProgress) >= a.backfillProgressInterval {
+					a.onBackfillProgress(next, toRound)
+					lastProgress = now
+				}
+			}
+			if next == toRound {
+				return nil
+			}
+			next++
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,125 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// replayClient is a runtime client whose rounds each contain a single lock event with the round
+// as its identifier and which are served after a random delay.
+type replayClient struct {
+	client.RuntimeClient
+
+	lock        sync.Mutex
+	inFlight    int
+	maxInFlight int
+	failRound   uint64
+}
+
+func (rc *replayClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	rc.lock.Lock()
+	rc.inFlight++
+	if rc.inFlight > rc.maxInFlight {
+		rc.maxInFlight = rc.inFlight
+	}
+	rc.lock.Unlock()
+	defer func() {
+		rc.lock.Lock()
+		rc.inFlight--
+		rc.lock.Unlock()
+	}()
+
+	time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+	if round == rc.failRound {
+		return nil, errors.New("fetch failed")
+	}
+	return []*coreClient.Event{{
+		Key:   LockEventKey,
+		Value: cbor.Marshal(&LockEvent{ID: round}),
+	}}, nil
+}
+
+func TestReplayEventsInOrder(t *testing.T) {
+	rc := &replayClient{}
+	v := NewV1(rc, WithBackfillConcurrency(8), WithBackfillWindow(16))
+
+	next := uint64(10)
+	err := v.ReplayEvents(context.Background(), 10, 209, func(round uint64, events []*Event) error {
+		if round != next {
+			t.Fatalf("expected round %d, got %d", next, round)
+		}
+		if len(events) != 1 || events[0].Lock.ID != round || events[0].Round != round {
+			t.Fatalf("unexpected events for round %d: %+v", round, events)
+		}
+		next++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to replay events: %s", err)
+	}
+	if next != 210 {
+		t.Fatalf("expected all rounds to be replayed, stopped at %d", next)
+	}
+	if rc.maxInFlight > 8 {
+		t.Fatalf("expected at most 8 concurrent fetches, got %d", rc.maxInFlight)
+	}
+}
+
+func TestReplayEventsErrors(t *testing.T) {
+	rc := &replayClient{failRound: 50}
+	v := NewV1(rc, WithBackfillConcurrency(4))
+
+	var replayed uint64
+	err := v.ReplayEvents(context.Background(), 1, 100, func(round uint64, events []*Event) error {
+		replayed = round
+		return nil
+	})
+	if err == nil || replayed >= 50 {
+		t.Fatalf("expected replay to fail before round 50, got %v after round %d", err, replayed)
+	}
+
+	errStop := errors.New("stop")
+	err = NewV1(&replayClient{}).ReplayEvents(context.Background(), 1, 100, func(round uint64, events []*Event) error {
+		if round == 5 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+}
+
+func TestReplayEventsProgress(t *testing.T) {
+	rc := &replayClient{}
+	v := NewV1(rc, WithBackfillMinRound(10))
+	err := v.ReplayEvents(context.Background(), 0, 20, func(uint64, []*Event) error { return nil })
+	if !errors.Is(err, ErrBackfillBelowMinRound) {
+		t.Fatalf("expected ErrBackfillBelowMinRound, got %v", err)
+	}
+
+	var progress [][2]uint64
+	v = NewV1(rc,
+		WithBackfillMinRound(10),
+		WithOnBackfillProgress(func(current, target uint64) {
+			progress = append(progress, [2]uint64{current, target})
+		}),
+		WithBackfillProgressInterval(time.Hour),
+	)
+	if err = v.ReplayEvents(context.Background(), 10, 20, func(uint64, []*Event) error { return nil }); err != nil {
+		t.Fatalf("failed to replay events: %s", err)
+	}
+	// Only the final round is reported as the interval never elapses.
+	if len(progress) != 1 || progress[0] != [2]uint64{20, 20} {
+		t.Fatalf("unexpected progress reports: %v", progress)
+	}
+}
@@ -0,0 +1,54 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// RoundStrategy selects the round at which queries for client.RoundLatest are performed.
+type RoundStrategy func(ctx context.Context, rc client.RuntimeClient) (uint64, error)
+
+// LatestRound returns a round strategy that queries the latest round known to the node.
+func LatestRound() RoundStrategy {
+	return func(ctx context.Context, rc client.RuntimeClient) (uint64, error) {
+		return client.RoundLatest, nil
+	}
+}
+
+// FinalizedRound returns a round strategy that queries the round the given number of rounds
+// behind the latest round known to the node.
+func FinalizedRound(confirmations uint64) RoundStrategy {
+	return func(ctx context.Context, rc client.RuntimeClient) (uint64, error) {
+		blk, err := rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		if blk.Header.Round < confirmations {
+			return 0, nil
+		}
+		return blk.Header.Round - confirmations, nil
+	}
+}
+
+// WithRoundStrategy configures the round strategy used by queries for client.RoundLatest.
+//
+// By default LatestRound is used. Rounds reported by a node have been finalized by the consensus
+// layer, but a node may be ahead of others, e.g. the ones used by remote verifiers. As witness
+// signatures are relayed to the remote side based on the queried state, relayers that need to
+// ensure such state is observable by everyone should use FinalizedRound with a suitable number
+// of confirmations instead.
+func WithRoundStrategy(strategy RoundStrategy) V1Option {
+	return func(a *v1) {
+		a.roundStrategy = strategy
+	}
+}
+
+// queryRound resolves the round at which to perform a query for the given round.
+func (a *v1) queryRound(ctx context.Context, round uint64) (uint64, error) {
+	if round != client.RoundLatest {
+		return round, nil
+	}
+	return a.roundStrategy(ctx, a.rc)
+}
@@ -0,0 +1,86 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// roundClient is a runtime client whose latest round is the configured one, recording the rounds
+// at which queries are performed.
+type roundClient struct {
+	client.RuntimeClient
+
+	latest uint64
+	err    error
+	rounds []uint64
+}
+
+func (rc *roundClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if rc.err != nil {
+		return nil, rc.err
+	}
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *roundClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	rc.rounds = append(rc.rounds, round)
+	return nil
+}
+
+func TestRoundStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy RoundStrategy
+		round    uint64
+		expected uint64
+	}{
+		{"Default", nil, client.RoundLatest, client.RoundLatest},
+		{"Latest", LatestRound(), client.RoundLatest, client.RoundLatest},
+		{"Finalized", FinalizedRound(3), client.RoundLatest, 7},
+		{"FinalizedBeforeGenesis", FinalizedRound(20), client.RoundLatest, 0},
+		// Queries for specific rounds are not affected.
+		{"FinalizedSpecificRound", FinalizedRound(3), 5, 5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &roundClient{latest: 10}
+			var opts []V1Option
+			if tc.strategy != nil {
+				opts = append(opts, WithRoundStrategy(tc.strategy))
+			}
+			v := NewV1(rc, opts...)
+
+			ctx := context.Background()
+			if _, err := v.Parameters(ctx, tc.round); err != nil {
+				t.Fatalf("Parameters: %s", err)
+			}
+			if _, err := v.NextSequenceNumbers(ctx, tc.round); err != nil {
+				t.Fatalf("NextSequenceNumbers: %s", err)
+			}
+			if len(rc.rounds) != 2 {
+				t.Fatalf("expected 2 queries, got %v", rc.rounds)
+			}
+			for _, round := range rc.rounds {
+				if round != tc.expected {
+					t.Fatalf("expected queries at round %d, got %v", tc.expected, rc.rounds)
+				}
+			}
+		})
+	}
+
+	// Failures to resolve the round are returned without querying.
+	errUnavailable := errors.New("node unavailable")
+	rc := &roundClient{err: errUnavailable}
+	if _, err := NewV1(rc, WithRoundStrategy(FinalizedRound(3))).Parameters(context.Background(), client.RoundLatest); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected round resolution error, got %v", err)
+	}
+	if len(rc.rounds) != 0 {
+		t.Fatalf("expected no queries, got %v", rc.rounds)
+	}
+}
@@ -0,0 +1,203 @@
+package bridge
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// signatureStoreSuffix is the suffix of the relay bundle files of a file signature store.
+const signatureStoreSuffix = ".bundle"
+
+// SignedOperationKey identifies a signed operation. Locks and releases are numbered by separate
+// sequences, so the identifier alone does not identify a signed operation.
+type SignedOperationKey struct {
+	Kind OperationKind
+	ID   uint64
+}
+
+// String returns a string representation of the key, e.g. "lock-42".
+func (k SignedOperationKey) String() string {
+	return k.Kind.String() + "-" + strconv.FormatUint(k.ID, 10)
+}
+
+// less returns true iff the key is ordered before the other key, ordering by identifier first.
+func (k SignedOperationKey) less(other SignedOperationKey) bool {
+	if k.ID != other.ID {
+		return k.ID < other.ID
+	}
+	return k.Kind < other.Kind
+}
+
+// parseSignedOperationKey parses a key in the format returned by SignedOperationKey.String.
+func parseSignedOperationKey(s string) (SignedOperationKey, bool) {
+	for _, kind := range []OperationKind{OperationLock, OperationRelease} {
+		prefix := kind.String() + "-"
+		if !strings.HasPrefix(s, prefix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimPrefix(s, prefix), 10, 64)
+		if err != nil {
+			return SignedOperationKey{}, false
+		}
+		return SignedOperationKey{Kind: kind, ID: id}, true
+	}
+	return SignedOperationKey{}, false
+}
+
+// sortSignedOperations sorts the given signed operations by key.
+func sortSignedOperations(events []*WitnessesSignedEvent) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Key().less(events[j].Key()) })
+}
+
+// SignatureStore persists the witness signatures collected for signed operations (see
+// WitnessesSignedEvent) until they have been relayed to the remote side.
+type SignatureStore interface {
+	// Put stores the signed operation, replacing any operation stored under the same key.
+	Put(ev *WitnessesSignedEvent) error
+
+	// Delete removes the signed operation with the given key. Deleting an operation that is not
+	// stored is not an error.
+	Delete(key SignedOperationKey) error
+
+	// List returns all stored signed operations ordered by key.
+	List() ([]*WitnessesSignedEvent, error)
+}
+
+type memorySignatureStore struct {
+	sync.Mutex
+
+	events map[SignedOperationKey]*WitnessesSignedEvent
+}
+
+// Implements SignatureStore.
+func (s *memorySignatureStore) Put(ev *WitnessesSignedEvent) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.events[ev.Key()] = ev
+	return nil
+}
+
+// Implements SignatureStore.
+func (s *memorySignatureStore) Delete(key SignedOperationKey) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.events, key)
+	return nil
+}
+
+// Implements SignatureStore.
+func (s *memorySignatureStore) List() ([]*WitnessesSignedEvent, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	events := make([]*WitnessesSignedEvent, 0, len(s.events))
+	for _, ev := range s.events {
+		events = append(events, ev)
+	}
+	sortSignedOperations(events)
+	return events, nil
+}
+
+// NewMemorySignatureStore creates a new signature store that keeps the signed operations in
+// memory.
+func NewMemorySignatureStore() SignatureStore {
+	return &memorySignatureStore{
+		events: make(map[SignedOperationKey]*WitnessesSignedEvent),
+	}
+}
+
+type fileSignatureStore struct {
+	dir string
+}
+
+func (s *fileSignatureStore) path(key SignedOperationKey) string {
+	return filepath.Join(s.dir, key.String()+signatureStoreSuffix)
+}
+
+// Implements SignatureStore.
+func (s *fileSignatureStore) Put(ev *WitnessesSignedEvent) error {
+	bundle, err := ev.MarshalRelayBundle()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first and then rename it so the bundle is never torn.
+	tmp, err := ioutil.TempFile(s.dir, ev.Key().String()+".tmp")
+	if err != nil {
+		return fmt.Errorf("bridge: failed to create relay bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Fails after a successful rename, which is fine.
+
+	if _, err = tmp.Write(bundle); err != nil {
+		tmp.Close()
+		return fmt.Errorf("bridge: failed to write relay bundle: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("bridge: failed to write relay bundle: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), s.path(ev.Key())); err != nil {
+		return fmt.Errorf("bridge: failed to store relay bundle: %w", err)
+	}
+	return nil
+}
+
+// Implements SignatureStore.
+func (s *fileSignatureStore) Delete(key SignedOperationKey) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("bridge: failed to delete relay bundle: %w", err)
+	}
+	return nil
+}
+
+// Implements SignatureStore.
+func (s *fileSignatureStore) List() ([]*WitnessesSignedEvent, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("bridge: failed to list relay bundles: %w", err)
+	}
+
+	var events []*WitnessesSignedEvent
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, signatureStoreSuffix) {
+			continue
+		}
+		key, ok := parseSignedOperationKey(strings.TrimSuffix(name, signatureStoreSuffix))
+		if !ok {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("bridge: failed to read relay bundle: %w", err)
+		}
+		var ev WitnessesSignedEvent
+		if err = ev.UnmarshalRelayBundle(data); err != nil {
+			return nil, fmt.Errorf("bridge: relay bundle %s: %w", name, err)
+		}
+		if ev.Key() != key {
+			return nil, fmt.Errorf("bridge: relay bundle %s contains %s", name, ev.Key())
+		}
+		events = append(events, &ev)
+	}
+	sortSignedOperations(events)
+	return events, nil
+}
+
+// NewFileSignatureStore creates a new signature store that persists each signed operation as a
+// relay bundle (see MarshalRelayBundle) named after its key (e.g. lock-42.bundle) in the given
+// directory, which is created if it does not exist. The bundles can be inspected with the verify command.
+func NewFileSignatureStore(dir string) (SignatureStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("bridge: failed to create signature store: %w", err)
+	}
+	return &fileSignatureStore{dir: dir}, nil
+}
@@ -0,0 +1,184 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrNoSigningHistory is the error returned when querying the signing history of a witness
+	// signer that does not keep one.
+	ErrNoSigningHistory = errors.New("bridge: no signing history")
+
+	// ErrSigningHistoryTampered is the error returned when the entries of a signing history do
+	// not form an intact hash chain.
+	ErrSigningHistoryTampered = errors.New("bridge: signing history has been tampered with")
+)
+
+// SigningRecord is an entry of a witness signing history describing an operation signed by a
+// witness.
+type SigningRecord struct {
+	// Time is the time at which the operation has been signed.
+	Time time.Time `json:"time"`
+
+	// Round is the runtime round in which the signed operation has been observed.
+	Round uint64 `json:"round"`
+
+	// ID is the identifier of the signed operation.
+	ID uint64 `json:"id"`
+
+	// PublicKey is the public key of the witness that signed the operation.
+	PublicKey types.PublicKey `json:"public_key"`
+
+	// PreimageHash is the hash of the signed preimage (see SigningPreimage).
+	PreimageHash hash.Hash `json:"preimage_hash"`
+
+	// Signature is the witness signature.
+	Signature []byte `json:"signature"`
+
+	// PrevHash is the hash of the previous entry or the empty hash for the first entry.
+	PrevHash hash.Hash `json:"prev_hash"`
+
+	// Hash is the hash of the entry (see ComputeHash) which the next entry refers to.
+	Hash hash.Hash `json:"hash"`
+}
+
+// signingRecordBody is the part of a signing record covered by its hash.
+type signingRecordBody struct {
+	Time         time.Time       `json:"time"`
+	Round        uint64          `json:"round"`
+	ID           uint64          `json:"id"`
+	PublicKey    types.PublicKey `json:"public_key"`
+	PreimageHash hash.Hash       `json:"preimage_hash"`
+	Signature    []byte          `json:"signature"`
+	PrevHash     hash.Hash       `json:"prev_hash"`
+}
+
+// ComputeHash returns the hash of the canonical encoding of all fields of the record but Hash.
+func (r *SigningRecord) ComputeHash() hash.Hash {
+	return hash.NewFrom(&signingRecordBody{
+		Time:         r.Time.UTC(),
+		Round:        r.Round,
+		ID:           r.ID,
+		PublicKey:    r.PublicKey,
+		PreimageHash: r.PreimageHash,
+		Signature:    r.Signature,
+		PrevHash:     r.PrevHash,
+	})
+}
+
+// SigningHistory is an append-only, file-backed log of the operations signed by witnesses.
+//
+// Each entry is stored as a JSON object on its own line and refers to the hash of the previous
+// entry, so that modifying or removing any entry but the last ones is detected when reading the
+// history.
+type SigningHistory struct {
+	lock sync.Mutex
+	f    *os.File
+	last hash.Hash
+}
+
+// Records returns all entries of the history recorded at or after the given time, after
+// verifying the integrity of the whole history.
+func (h *SigningHistory) Records(since time.Time) ([]*SigningRecord, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, err := h.f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("bridge: failed to read signing history: %w", err)
+	}
+	var records []*SigningRecord
+	_, err := readSigningHistory(h.f, func(r *SigningRecord) {
+		if !r.Time.Before(since) {
+			records = append(records, r)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// append appends the given record to the history, setting its PrevHash and Hash.
+func (h *SigningHistory) append(r *SigningRecord) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	r.Time = r.Time.UTC()
+	r.PrevHash = h.last
+	r.Hash = r.ComputeHash()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to encode signing record: %w", err)
+	}
+	if _, err = h.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("bridge: failed to write signing record: %w", err)
+	}
+	if err = h.f.Sync(); err != nil {
+		return fmt.Errorf("bridge: failed to write signing record: %w", err)
+	}
+	h.last = r.Hash
+	return nil
+}
+
+// Close closes the history.
+func (h *SigningHistory) Close() error {
+	return h.f.Close()
+}
+
+// readSigningHistory reads and verifies all entries of a signing history, passing each of them
+// to fn, and returns the hash of the last entry.
+func readSigningHistory(rd io.Reader, fn func(r *SigningRecord)) (hash.Hash, error) {
+	var last hash.Hash
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		var r SigningRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return hash.Hash{}, fmt.Errorf("%w: malformed entry %d: %s", ErrSigningHistoryTampered, n, err)
+		}
+		if !r.PrevHash.Equal(&last) {
+			return hash.Hash{}, fmt.Errorf("%w: entry %d does not refer to the previous entry", ErrSigningHistoryTampered, n)
+		}
+		if h := r.ComputeHash(); !h.Equal(&r.Hash) {
+			return hash.Hash{}, fmt.Errorf("%w: entry %d has been modified", ErrSigningHistoryTampered, n)
+		}
+		last = r.Hash
+		fn(&r)
+	}
+	if err := scanner.Err(); err != nil {
+		return hash.Hash{}, fmt.Errorf("bridge: failed to read signing history: %w", err)
+	}
+	return last, nil
+}
+
+// OpenSigningHistory opens the signing history stored in the file at the given path, creating
+// it if it does not exist.
+//
+// ErrSigningHistoryTampered is returned in case the existing entries do not verify.
+func OpenSigningHistory(path string) (*SigningHistory, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("bridge: failed to open signing history: %w", err)
+	}
+	last, err := readSigningHistory(f, func(*SigningRecord) {})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &SigningHistory{
+		f:    f,
+		last: last,
+	}, nil
+}
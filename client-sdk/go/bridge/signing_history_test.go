@@ -0,0 +1,150 @@
+package bridge
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSigningHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := OpenSigningHistory(path)
+	if err != nil {
+		t.Fatalf("failed to open signing history: %s", err)
+	}
+
+	op := &Operation{
+		Lock: &Lock{
+			Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		},
+	}
+	alice := NewWitnessSigner(sdkTesting.Alice.Signer, "test-chain-context", WithSigningHistory(history))
+	bob := NewWitnessSigner(sdkTesting.Bob.Signer, "test-chain-context", WithSigningHistory(history))
+	for id := uint64(1); id <= 3; id++ {
+		if _, err = alice.SignOperation(10+id, id, op); err != nil {
+			t.Fatalf("failed to sign operation: %s", err)
+		}
+		if _, err = bob.SignOperation(10+id, id, op); err != nil {
+			t.Fatalf("failed to sign operation: %s", err)
+		}
+	}
+
+	records, err := alice.History(time.Time{})
+	if err != nil {
+		t.Fatalf("failed to query signing history: %s", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	for i, r := range records {
+		if r.ID != uint64(i+1) || r.Round != uint64(11+i) || !r.PublicKey.Equal(sdkTesting.Alice.Signer.Public()) {
+			t.Fatalf("unexpected record %d: %+v", i, r)
+		}
+		if !sdkTesting.Alice.Signer.Public().Verify([]byte(WitnessSignatureContext), SigningPreimage("test-chain-context", op, r.ID), r.Signature) {
+			t.Fatalf("record %d: signature verification failed", i)
+		}
+	}
+	if records, err = bob.History(time.Now().Add(time.Hour)); err != nil || len(records) != 0 {
+		t.Fatalf("expected no future records, got %d (err: %v)", len(records), err)
+	}
+	if _, err = NewWitnessSigner(sdkTesting.Alice.Signer, "test-chain-context").History(time.Time{}); !errors.Is(err, ErrNoSigningHistory) {
+		t.Fatalf("expected ErrNoSigningHistory, got %v", err)
+	}
+	if err = history.Close(); err != nil {
+		t.Fatalf("failed to close signing history: %s", err)
+	}
+
+	// Reopening continues the chain.
+	if history, err = OpenSigningHistory(path); err != nil {
+		t.Fatalf("failed to reopen signing history: %s", err)
+	}
+	alice = NewWitnessSigner(sdkTesting.Alice.Signer, "test-chain-context", WithSigningHistory(history))
+	if _, err = alice.SignOperation(14, 4, op); err != nil {
+		t.Fatalf("failed to sign operation: %s", err)
+	}
+	if records, err = history.Records(time.Time{}); err != nil || len(records) != 7 {
+		t.Fatalf("expected 7 records, got %d (err: %v)", len(records), err)
+	}
+	history.Close()
+
+	// Any modification of an entry is detected.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read signing history: %s", err)
+	}
+	tampered := bytes.Replace(data, []byte(`"round":12`), []byte(`"round":13`), 1)
+	if err = os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatalf("failed to write signing history: %s", err)
+	}
+	if _, err = OpenSigningHistory(path); !errors.Is(err, ErrSigningHistoryTampered) {
+		t.Fatalf("expected ErrSigningHistoryTampered, got %v", err)
+	}
+
+	// As is the removal of an entry.
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	removed := bytes.Join(append(lines[:1:1], lines[2:]...), nil)
+	if err = os.WriteFile(path, removed, 0o600); err != nil {
+		t.Fatalf("failed to write signing history: %s", err)
+	}
+	if _, err = OpenSigningHistory(path); !errors.Is(err, ErrSigningHistoryTampered) {
+		t.Fatalf("expected ErrSigningHistoryTampered, got %v", err)
+	}
+}
+
+func TestSigningHistoryClock(t *testing.T) {
+	privateKey := make([]byte, 32)
+	privateKey[31] = 1
+	domain := EIP712Domain{VerifyingContract: NewRemoteAddressFromHex("cccccccccccccccccccccccccccccccccccccccc")}
+	op := &Operation{Lock: &Lock{Target: RemoteAddress{1}}}
+
+	for _, tc := range []struct {
+		name      string
+		newSigner func(opts ...WitnessSignerOption) (WitnessSigner, error)
+	}{
+		{"Ed25519", func(opts ...WitnessSignerOption) (WitnessSigner, error) {
+			return NewWitnessSigner(sdkTesting.Alice.Signer, "test-chain-context", opts...), nil
+		}},
+		{"EIP712", func(opts ...WitnessSignerOption) (WitnessSigner, error) {
+			return NewEIP712WitnessSigner(privateKey, "test-chain-context", domain, opts...)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			history, err := OpenSigningHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+			if err != nil {
+				t.Fatalf("failed to open signing history: %s", err)
+			}
+			defer history.Close()
+
+			start := time.Unix(1000, 0)
+			clock := NewFakeClock(start)
+			signer, err := tc.newSigner(WithSigningHistory(history), WithWitnessSignerClock(clock))
+			if err != nil {
+				t.Fatalf("failed to create signer: %s", err)
+			}
+			if _, err = signer.SignOperation(1, 1, op); err != nil {
+				t.Fatalf("failed to sign operation: %s", err)
+			}
+			clock.Advance(time.Minute)
+			if _, err = signer.SignOperation(2, 2, op); err != nil {
+				t.Fatalf("failed to sign operation: %s", err)
+			}
+
+			records, err := signer.History(start.Add(time.Second))
+			if err != nil {
+				t.Fatalf("failed to query signing history: %s", err)
+			}
+			if len(records) != 1 || records[0].ID != 2 || !records[0].Time.Equal(start.Add(time.Minute)) {
+				t.Fatalf("expected only the second record timestamped by the clock, got %+v", records)
+			}
+		})
+	}
+}
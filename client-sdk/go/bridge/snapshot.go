@@ -0,0 +1,218 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// BridgeSnapshot is a point-in-time view of the bridge state at a given round.
+//
+// The bridge runtime does not expose the signatures collected for incoming releases, so the
+// only release that may be pending is the one with sequence number NextSequenceNumbers.Incoming
+// and its contents are not part of the snapshot.
+type BridgeSnapshot struct {
+	// Round is the runtime round of the snapshot.
+	Round uint64 `json:"round"`
+
+	// Parameters are the bridge module parameters.
+	Parameters *Parameters `json:"parameters"`
+
+	// NextSequenceNumbers are the next bridge sequence numbers.
+	NextSequenceNumbers *NextSequenceNumbers `json:"next_sequence_numbers"`
+
+	// PendingLocks are the locks that have not yet been signed by enough witnesses, ordered by
+	// their identifier.
+	PendingLocks []*LockEvent `json:"pending_locks"`
+}
+
+// MarshalCanonical returns the canonical CBOR encoding of the snapshot (with sorted map keys)
+// which is suitable for comparing snapshots.
+func (s *BridgeSnapshot) MarshalCanonical() []byte {
+	return cbor.Marshal(s)
+}
+
+// Implements V1.
+func (a *v1) Snapshot(ctx context.Context, round uint64) (*BridgeSnapshot, error) {
+	if round == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	params, err := a.Parameters(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query parameters: %w", err)
+	}
+	seq, err := a.NextSequenceNumbers(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query next sequence numbers: %w", err)
+	}
+	genesis, err := a.rc.GetGenesisBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis block: %w", err)
+	}
+
+	// Replay all events up to the snapshot round to determine which locks are still pending.
+	pending := make(map[uint64]*LockEvent)
+	err = a.ReplayEvents(ctx, genesis.Header.Round, round, func(_ uint64, events []*Event) error {
+		for _, ev := range events {
+			switch {
+			case ev.Lock != nil:
+				pending[ev.Lock.ID] = ev.Lock
+			case ev.WitnessesSigned != nil && ev.WitnessesSigned.IsLock():
+				delete(pending, ev.WitnessesSigned.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &BridgeSnapshot{
+		Round:               round,
+		Parameters:          params,
+		NextSequenceNumbers: seq,
+		PendingLocks:        make([]*LockEvent, 0, len(pending)),
+	}
+	for _, lock := range pending {
+		snapshot.PendingLocks = append(snapshot.PendingLocks, lock)
+	}
+	sort.Slice(snapshot.PendingLocks, func(i, j int) bool {
+		return snapshot.PendingLocks[i].ID < snapshot.PendingLocks[j].ID
+	})
+	return snapshot, nil
+}
+
+// ThresholdChange is a change of the witness threshold.
+type ThresholdChange struct {
+	// From is the threshold before the change.
+	From uint64 `json:"from"`
+
+	// To is the threshold after the change.
+	To uint64 `json:"to"`
+}
+
+// SnapshotDiff is the difference between two bridge snapshots.
+//
+// All lists are ordered (witnesses by their string representation, identifiers and locks by
+// identifier) so that diffs of the same snapshots are always equal.
+type SnapshotDiff struct {
+	// FromRound is the round of the older snapshot.
+	FromRound uint64 `json:"from_round"`
+
+	// ToRound is the round of the newer snapshot.
+	ToRound uint64 `json:"to_round"`
+
+	// AddedWitnesses are the witnesses that have been authorized.
+	AddedWitnesses []types.PublicKey `json:"added_witnesses"`
+
+	// RemovedWitnesses are the witnesses that are no longer authorized.
+	RemovedWitnesses []types.PublicKey `json:"removed_witnesses"`
+
+	// Threshold is the change of the witness threshold, if any.
+	Threshold *ThresholdChange `json:"threshold,omitempty"`
+
+	// NewLockIDs are the identifiers of the locks that have been created.
+	NewLockIDs []uint64 `json:"new_lock_ids"`
+
+	// NewPendingLocks are the locks that are pending in the newer snapshot but not in the older
+	// one.
+	NewPendingLocks []*LockEvent `json:"new_pending_locks"`
+
+	// SignedLockIDs are the identifiers of the locks that are pending in the older snapshot but
+	// not in the newer one, i.e. that have been signed by enough witnesses.
+	SignedLockIDs []uint64 `json:"signed_lock_ids"`
+
+	// ReleasedIDs are the identifiers of the releases that have been executed.
+	ReleasedIDs []uint64 `json:"released_ids"`
+}
+
+// IsEmpty returns true iff nothing changed between the snapshots.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.AddedWitnesses) == 0 &&
+		len(d.RemovedWitnesses) == 0 &&
+		d.Threshold == nil &&
+		len(d.NewLockIDs) == 0 &&
+		len(d.NewPendingLocks) == 0 &&
+		len(d.SignedLockIDs) == 0 &&
+		len(d.ReleasedIDs) == 0
+}
+
+// DiffSnapshots returns the difference between the given snapshots, a being the older one.
+func DiffSnapshots(a, b *BridgeSnapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		FromRound:        a.Round,
+		ToRound:          b.Round,
+		AddedWitnesses:   witnessesMissingFrom(b.Parameters, a.Parameters),
+		RemovedWitnesses: witnessesMissingFrom(a.Parameters, b.Parameters),
+		NewLockIDs:       sequenceRange(a.NextSequenceNumbers.Outgoing, b.NextSequenceNumbers.Outgoing),
+		NewPendingLocks:  []*LockEvent{},
+		SignedLockIDs:    []uint64{},
+		ReleasedIDs:      sequenceRange(a.NextSequenceNumbers.Incoming, b.NextSequenceNumbers.Incoming),
+	}
+	if a.Parameters.Threshold != b.Parameters.Threshold {
+		diff.Threshold = &ThresholdChange{
+			From: a.Parameters.Threshold,
+			To:   b.Parameters.Threshold,
+		}
+	}
+
+	pendingA := make(map[uint64]bool, len(a.PendingLocks))
+	for _, lock := range a.PendingLocks {
+		pendingA[lock.ID] = true
+	}
+	pendingB := make(map[uint64]bool, len(b.PendingLocks))
+	for _, lock := range b.PendingLocks {
+		pendingB[lock.ID] = true
+		if !pendingA[lock.ID] {
+			diff.NewPendingLocks = append(diff.NewPendingLocks, lock)
+		}
+	}
+	for _, lock := range a.PendingLocks {
+		if !pendingB[lock.ID] {
+			diff.SignedLockIDs = append(diff.SignedLockIDs, lock.ID)
+		}
+	}
+
+	// Snapshots order pending locks by identifier, but they may have been constructed otherwise.
+	sort.Slice(diff.NewPendingLocks, func(i, j int) bool {
+		return diff.NewPendingLocks[i].ID < diff.NewPendingLocks[j].ID
+	})
+	sort.Slice(diff.SignedLockIDs, func(i, j int) bool {
+		return diff.SignedLockIDs[i] < diff.SignedLockIDs[j]
+	})
+	return diff
+}
+
+// witnessesMissingFrom returns the witnesses of a that are not witnesses of b, ordered by their
+// string representation.
+func witnessesMissingFrom(a, b *Parameters) []types.PublicKey {
+	missing := []types.PublicKey{}
+	for _, w := range a.Witnesses {
+		if _, ok := b.WitnessIndex(w.PublicKey); !ok {
+			missing = append(missing, w)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].String() < missing[j].String()
+	})
+	return missing
+}
+
+// sequenceRange returns the sequence numbers in [from, to).
+func sequenceRange(from, to uint64) []uint64 {
+	ids := []uint64{}
+	for id := from; id < to; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,166 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	alice := types.PublicKey{PublicKey: sdkTesting.Alice.Signer.Public()}
+	bob := types.PublicKey{PublicKey: sdkTesting.Bob.Signer.Public()}
+	charlie := types.PublicKey{PublicKey: sdkTesting.Charlie.Signer.Public()}
+
+	a := &BridgeSnapshot{
+		Round: 10,
+		Parameters: &Parameters{
+			Witnesses: []types.PublicKey{alice, bob},
+			Threshold: 2,
+		},
+		NextSequenceNumbers: &NextSequenceNumbers{Incoming: 3, Outgoing: 5},
+		PendingLocks:        []*LockEvent{{ID: 3}, {ID: 4}},
+	}
+	b := &BridgeSnapshot{
+		Round: 20,
+		Parameters: &Parameters{
+			Witnesses: []types.PublicKey{charlie, alice},
+			Threshold: 1,
+		},
+		NextSequenceNumbers: &NextSequenceNumbers{Incoming: 5, Outgoing: 8},
+		PendingLocks:        []*LockEvent{{ID: 7}, {ID: 4}, {ID: 6}},
+	}
+
+	diff := DiffSnapshots(a, b)
+	if diff.FromRound != 10 || diff.ToRound != 20 {
+		t.Fatalf("unexpected rounds: %d -> %d", diff.FromRound, diff.ToRound)
+	}
+	if len(diff.AddedWitnesses) != 1 || !diff.AddedWitnesses[0].Equal(charlie.PublicKey) {
+		t.Fatalf("unexpected added witnesses: %v", diff.AddedWitnesses)
+	}
+	if len(diff.RemovedWitnesses) != 1 || !diff.RemovedWitnesses[0].Equal(bob.PublicKey) {
+		t.Fatalf("unexpected removed witnesses: %v", diff.RemovedWitnesses)
+	}
+	if diff.Threshold == nil || *diff.Threshold != (ThresholdChange{From: 2, To: 1}) {
+		t.Fatalf("unexpected threshold change: %+v", diff.Threshold)
+	}
+	if !reflect.DeepEqual(diff.NewLockIDs, []uint64{5, 6, 7}) {
+		t.Fatalf("unexpected new lock ids: %v", diff.NewLockIDs)
+	}
+	var pending []uint64
+	for _, lock := range diff.NewPendingLocks {
+		pending = append(pending, lock.ID)
+	}
+	if !reflect.DeepEqual(pending, []uint64{6, 7}) {
+		t.Fatalf("unexpected new pending locks: %v", pending)
+	}
+	if !reflect.DeepEqual(diff.SignedLockIDs, []uint64{3}) {
+		t.Fatalf("unexpected signed lock ids: %v", diff.SignedLockIDs)
+	}
+	if !reflect.DeepEqual(diff.ReleasedIDs, []uint64{3, 4}) {
+		t.Fatalf("unexpected released ids: %v", diff.ReleasedIDs)
+	}
+
+	// The diff must be deterministic and serializable.
+	first, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	second, _ := json.Marshal(DiffSnapshots(a, b))
+	if string(first) != string(second) {
+		t.Fatalf("diff is not deterministic:\n%s\n%s", first, second)
+	}
+
+	if diff = DiffSnapshots(b, b); !diff.IsEmpty() {
+		t.Fatalf("expected an empty diff, got %+v", diff)
+	}
+}
+
+// snapshotClient is a release client whose genesis round is the configured one.
+type snapshotClient struct {
+	releaseClient
+
+	genesis uint64
+}
+
+func (rc *snapshotClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.genesis
+	return &blk, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	lock := func(id uint64) *coreClient.Event {
+		return &coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: id})}
+	}
+	signed := func(id uint64, op Operation) *coreClient.Event {
+		return &coreClient.Event{Key: WitnessesSignedEventKey, Value: cbor.Marshal(&WitnessesSignedEvent{ID: id, Op: op})}
+	}
+	rc := &snapshotClient{
+		releaseClient: releaseClient{
+			latest:   6,
+			incoming: 2,
+			events: map[uint64][]*coreClient.Event{
+				// Events before the genesis round are not replayed.
+				1: {lock(9)},
+				3: {lock(1), lock(2)},
+				4: {signed(1, NewLockOperation(Lock{}))},
+				// Signed releases do not affect locks with the same identifier.
+				5: {signed(2, NewReleaseOperation(Release{})), lock(3)},
+				6: {lock(4)},
+			},
+		},
+		genesis: 2,
+	}
+	v := NewV1(rc)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name     string
+		round    uint64
+		expected uint64
+		pending  []uint64
+	}{
+		{"Latest", client.RoundLatest, 6, []uint64{2, 3, 4}},
+		{"Past", 4, 4, []uint64{2}},
+		{"Genesis", 2, 2, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			snapshot, err := v.Snapshot(ctx, tc.round)
+			if err != nil {
+				t.Fatalf("Snapshot: %s", err)
+			}
+			if snapshot.Round != tc.expected {
+				t.Fatalf("expected snapshot of round %d, got %d", tc.expected, snapshot.Round)
+			}
+			if snapshot.NextSequenceNumbers.Incoming != 2 {
+				t.Fatalf("expected next incoming sequence number 2, got %d", snapshot.NextSequenceNumbers.Incoming)
+			}
+			var pending []uint64
+			for _, lock := range snapshot.PendingLocks {
+				pending = append(pending, lock.ID)
+			}
+			if !reflect.DeepEqual(pending, tc.pending) {
+				t.Fatalf("expected pending locks %v, got %v", tc.pending, pending)
+			}
+
+			// Snapshots of the same round are identical.
+			again, err := v.Snapshot(ctx, tc.expected)
+			if err != nil {
+				t.Fatalf("Snapshot: %s", err)
+			}
+			if !bytes.Equal(snapshot.MarshalCanonical(), again.MarshalCanonical()) {
+				t.Fatalf("expected snapshots of the same round to be identical")
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// SubmitOption is an option for configuring transaction submission.
+type SubmitOption func(o *submitOptions)
+
+type submitOptions struct {
+	nonce           *NonceManager
+	fee             *types.Fee
+	feeDenomination *types.Denomination
+	feePayer        signature.Signer
+	balancePrecheck bool
+	txHash          *hash.Hash
+	receipt         *Receipt
+}
+
+func newSubmitOptions(opts ...SubmitOption) *submitOptions {
+	var o submitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// WithNonceManager configures the nonce manager used to obtain the transaction nonce.
+//
+// By default the nonce is queried from the runtime for each submitted transaction. The nonce
+// manager is reset in case submission fails.
+func WithNonceManager(nm *NonceManager) SubmitOption {
+	return func(o *submitOptions) {
+		o.nonce = nm
+	}
+}
+
+func (a *v1) nextNonce(ctx context.Context, o *submitOptions, address types.Address) (uint64, error) {
+	if o.nonce != nil {
+		return o.nonce.Next(ctx)
+	}
+
+	nonce, err := a.accounts.Nonce(ctx, client.RoundLatest, address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch account nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// WithBalancePrecheck configures lock submission to first verify that the signer holds at least
+// the locked amount and fail with ErrInsufficientBalance otherwise, without submitting anything.
+//
+// The precheck requires an additional query and does not account for the transaction fee. It is
+// ignored by other transactions.
+func WithBalancePrecheck() SubmitOption {
+	return func(o *submitOptions) {
+		o.balancePrecheck = true
+	}
+}
+
+// WithSubmittedTxHash configures the submit helpers to store the hash of the submitted transaction
+// (see TxHash) into the given hash once the transaction has been successfully executed.
+func WithSubmittedTxHash(h *hash.Hash) SubmitOption {
+	return func(o *submitOptions) {
+		o.txHash = h
+	}
+}
+
+// WithReceipt configures the submit helpers to store the receipt of the submitted transaction (see
+// SubmitWithReceipt) into the given receipt once the transaction has been successfully executed.
+//
+// Determining the round requires additional queries. In case the round cannot be determined, the
+// transaction is still reported as executed and the receipt's Round is zero.
+func WithReceipt(r *Receipt) SubmitOption {
+	return func(o *submitOptions) {
+		o.receipt = r
+	}
+}
+
+// SubmitAndDecode submits the given transaction, waits for its result and decodes the CBOR-encoded
+// call result into a new value of type T.
+//
+// An empty call result decodes to the zero value of T.
+func SubmitAndDecode[T any](ctx context.Context, rc client.RuntimeClient, tx *types.UnverifiedTransaction) (*T, error) {
+	raw, err := rc.SubmitTx(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	var result T
+	if len(raw) == 0 {
+		return &result, nil
+	}
+	if err = cbor.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal call result: %w", err)
+	}
+	return &result, nil
+}
+
+// submitAndDecodeWithReceipt is like SubmitAndDecode, but additionally stores the receipt of the
+// executed transaction into the given receipt.
+func submitAndDecodeWithReceipt[T any](
+	ctx context.Context,
+	a *v1,
+	tx *types.UnverifiedTransaction,
+	receipt *Receipt,
+) (*T, error) {
+	r, err := SubmitWithReceipt(ctx, a.rc, tx)
+	switch {
+	case errors.Is(err, ErrReceiptNotFound):
+		// The transaction has been executed, so it must not be reported as failed.
+		a.logger.Warn("failed to determine the round of an executed transaction",
+			"err", err,
+			"tx_hash", r.TxHash,
+		)
+	case err != nil:
+		return nil, err
+	}
+	*receipt = *r
+
+	var result T
+	if err = r.DecodeResult(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func signAndSubmit[T any](
+	ctx context.Context,
+	a *v1,
+	signer signature.Signer,
+	method string,
+	body interface{},
+	opts ...SubmitOption,
+) (result *T, err error) {
+	o := newSubmitOptions(opts...)
+	if o.nonce != nil {
+		defer func() {
+			if err != nil {
+				o.nonce.Reset()
+			}
+		}()
+	}
+
+	tx, chainContext, err := prepareTx(ctx, a, signer.Public(), method, body, o)
+	if err != nil {
+		return nil, err
+	}
+	tb := tx.PrepareForSigning()
+	if err = tb.AppendSign(chainContext, signer); err != nil {
+		return nil, fmt.Errorf("failed to sign %s transaction: %w", method, err)
+	}
+	if o.receipt != nil {
+		result, err = submitAndDecodeWithReceipt[T](ctx, a, tb.UnverifiedTransaction(), o.receipt)
+	} else {
+		result, err = SubmitAndDecode[T](ctx, a.rc, tb.UnverifiedTransaction())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	if o.txHash != nil {
+		*o.txHash = TxHash(tb.UnverifiedTransaction())
+	}
+	return result, nil
+}
+
+// prepareTx builds an unsigned transaction calling the given method, authenticated by the given
+// public key, with its nonce and fee populated, and returns it together with the runtime's chain
+// context.
+func prepareTx(
+	ctx context.Context,
+	a *v1,
+	pk signature.PublicKey,
+	method string,
+	body interface{},
+	o *submitOptions,
+) (*types.Transaction, signature.Context, error) {
+	if o.feePayer != nil && !o.feePayer.Public().Equal(pk) {
+		// TODO: Add the fee payer as a transaction signer once the runtime supports it.
+		return nil, "", fmt.Errorf("%s: %w", method, ErrFeePayerNotSupported)
+	}
+	info, err := a.runtimeInfo(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := a.nextNonce(ctx, o, AddressOfPublicKey(pk))
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx := types.NewTransaction(nil, method, body)
+	tx.AppendAuthSignature(pk, nonce)
+	fee := o.fee
+	switch {
+	case fee != nil:
+	case o.feeDenomination != nil:
+		if err = a.validateFeeDenomination(ctx, *o.feeDenomination); err != nil {
+			return nil, "", fmt.Errorf("%s: %w", method, err)
+		}
+		if fee, err = a.estimateFeeIn(ctx, tx, *o.feeDenomination); err != nil {
+			return nil, "", fmt.Errorf("failed to estimate %s transaction fee: %w", method, err)
+		}
+	default:
+		if fee, err = a.EstimateFee(ctx, tx); err != nil {
+			return nil, "", fmt.Errorf("failed to estimate %s transaction fee: %w", method, err)
+		}
+	}
+	tx.AuthInfo.Fee = *fee
+	return tx, info.ChainContext, nil
+}
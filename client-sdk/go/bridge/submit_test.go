@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// resultClient is a runtime client returning the configured call result for all submitted
+// transactions.
+type resultClient struct {
+	client.RuntimeClient
+
+	result cbor.RawMessage
+	err    error
+}
+
+func (rc *resultClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	return rc.result, rc.err
+}
+
+func TestSubmitAndDecode(t *testing.T) {
+	errSubmit := errors.New("submit failed")
+
+	for _, tc := range []struct {
+		name     string
+		result   cbor.RawMessage
+		err      error
+		expected *LockResult
+		valid    bool
+	}{
+		{"Result", cbor.Marshal(&LockResult{ID: 42}), nil, &LockResult{ID: 42}, true},
+		{"Empty", nil, nil, &LockResult{}, true},
+		{"Null", cbor.Marshal(nil), nil, &LockResult{}, true},
+		{"Malformed", cbor.RawMessage{0xff}, nil, nil, false},
+		{"WrongType", cbor.Marshal("lock"), nil, nil, false},
+		{"SubmitFailure", nil, errSubmit, nil, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &resultClient{result: tc.result, err: tc.err}
+			result, err := SubmitAndDecode[LockResult](context.Background(), rc, &types.UnverifiedTransaction{})
+			switch {
+			case !tc.valid:
+				if err == nil {
+					t.Fatalf("expected error, got %+v", result)
+				}
+				if result != nil {
+					t.Fatalf("expected no result on error, got %+v", result)
+				}
+				if tc.err != nil && !errors.Is(err, tc.err) {
+					t.Fatalf("expected submission error to be wrapped, got %v", err)
+				}
+			case err != nil:
+				t.Fatalf("SubmitAndDecode: %s", err)
+			case *result != *tc.expected:
+				t.Fatalf("expected result %+v, got %+v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.uber.org/goleak"
+
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// watchClient is a runtime client producing a new block whenever one is requested and whose
+// rounds contain no events. Each block subscription has its own block feed so that every
+// subscriber observes every produced block.
+type watchClient struct {
+	client.RuntimeClient
+
+	lock   sync.Mutex
+	subbed *sync.Cond
+	subs   []*watchSubscription
+}
+
+type watchSubscription struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	ch     chan *roothash.AnnotatedBlock
+}
+
+func (s *watchSubscription) Close() {
+	s.cancel()
+}
+
+func (rc *watchClient) WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &watchSubscription{
+		ctx:    ctx,
+		cancel: cancel,
+		ch:     make(chan *roothash.AnnotatedBlock),
+	}
+
+	rc.lock.Lock()
+	rc.subs = append(rc.subs, sub)
+	rc.subscribed().Broadcast()
+	rc.lock.Unlock()
+
+	return sub.ch, sub, nil
+}
+
+func (rc *watchClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	return nil, nil
+}
+
+// subscribed returns the condition signaled whenever a block subscription is established. The
+// lock must be held.
+func (rc *watchClient) subscribed() *sync.Cond {
+	if rc.subbed == nil {
+		rc.subbed = sync.NewCond(&rc.lock)
+	}
+	return rc.subbed
+}
+
+// produce delivers a block for the given round to all block subscriptions that have not been
+// closed yet, waiting for the first subscription to be established.
+func (rc *watchClient) produce(round uint64) {
+	var blk block.Block
+	blk.Header.Round = round
+	rc.produceBlock(&blk)
+}
+
+// produceBlock is like produce, but delivers the given block.
+func (rc *watchClient) produceBlock(blk *block.Block) {
+	rc.lock.Lock()
+	for len(rc.subs) == 0 {
+		rc.subscribed().Wait()
+	}
+	subs := append([]*watchSubscription{}, rc.subs...)
+	rc.lock.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case <-sub.ctx.Done():
+		case sub.ch <- &roothash.AnnotatedBlock{Block: blk}:
+		}
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	ignoreGoroutines := goleak.IgnoreCurrent()
+	defer goleak.VerifyNone(t, ignoreGoroutines)
+
+	rc := &watchClient{}
+	v := NewV1(rc)
+
+	evCh, evSub, err := v.WatchEvents(context.Background())
+	if err != nil {
+		t.Fatalf("failed to watch events: %s", err)
+	}
+	relCh, relSub, err := v.WatchReleases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to watch releases: %s", err)
+	}
+	for round := uint64(1); round <= 3; round++ {
+		rc.produce(round)
+	}
+
+	evSub.Close()
+	relSub.Close()
+	// Closing again is a no-op.
+	evSub.Close()
+
+	for range evCh {
+	}
+	for range relCh {
+	}
+	if err = evSub.Err(); err != context.Canceled {
+		t.Fatalf("expected subscription to be canceled, got %v", err)
+	}
+	if err = goleak.Find(ignoreGoroutines); err != nil {
+		t.Fatal(err)
+	}
+
+	// Canceling the parent context tears down the subscription as well.
+	ctx, cancel := context.WithCancel(context.Background())
+	evCh, _, err = v.WatchEvents(ctx)
+	if err != nil {
+		t.Fatalf("failed to watch events: %s", err)
+	}
+	cancel()
+	for range evCh {
+	}
+}
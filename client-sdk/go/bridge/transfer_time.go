@@ -0,0 +1,134 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTransferTimeWindow is the default number of recent transfers the transfer time
+	// estimate is based on.
+	DefaultTransferTimeWindow = 16
+
+	// DefaultTransferTime is the default transfer time returned while no transfers have been
+	// observed.
+	DefaultTransferTime = 1 * time.Minute
+
+	// maxPendingTransfers bounds the number of submitted locks tracked while waiting for their
+	// WitnessesSignedEvent, so that locks that are never witnessed (or whose events are never
+	// fetched) do not leak memory. Once reached, the oldest submitted lock is no longer tracked.
+	maxPendingTransfers = 1024
+)
+
+// WithTransferTimeWindow configures the number of most recent transfers the transfer time
+// estimate (see EstimateTransferTime) is averaged over.
+//
+// By default DefaultTransferTimeWindow is used.
+func WithTransferTimeWindow(n int) V1Option {
+	return func(a *v1) {
+		if n > 0 {
+			a.transferTimes.window = make([]time.Duration, 0, n)
+		}
+	}
+}
+
+// WithDefaultTransferTime configures the transfer time returned by EstimateTransferTime while no
+// transfers have been observed.
+//
+// By default DefaultTransferTime is used.
+func WithDefaultTransferTime(d time.Duration) V1Option {
+	return func(a *v1) {
+		a.transferTimes.defaultTime = d
+	}
+}
+
+// transferTimes keeps a rolling window of the durations between submitting a lock and observing
+// its WitnessesSignedEvent.
+//
+// Transfers are only completed when their WitnessesSignedEvent is decoded by GetEvents, which
+// all event helpers including WatchEvents use, so the estimate is only updated while the client
+// fetches events.
+type transferTimes struct {
+	sync.Mutex
+
+	defaultTime time.Duration
+	pending     map[uint64]time.Time
+	window      []time.Duration
+	next        int
+}
+
+func (t *transferTimes) submitted(id uint64, at time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(t.pending) >= maxPendingTransfers {
+		t.evictOldest()
+	}
+	t.pending[id] = at
+}
+
+// evictOldest stops tracking the lock that has been submitted first.
+func (t *transferTimes) evictOldest() {
+	var (
+		oldestID uint64
+		oldest   time.Time
+		found    bool
+	)
+	for id, at := range t.pending {
+		if !found || at.Before(oldest) {
+			oldestID, oldest, found = id, at, true
+		}
+	}
+	delete(t.pending, oldestID)
+}
+
+func (t *transferTimes) completed(id uint64, at time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	submitted, ok := t.pending[id]
+	if !ok {
+		return
+	}
+	delete(t.pending, id)
+
+	d := at.Sub(submitted)
+	if len(t.window) < cap(t.window) {
+		t.window = append(t.window, d)
+		return
+	}
+	t.window[t.next] = d
+	t.next = (t.next + 1) % len(t.window)
+}
+
+func (t *transferTimes) estimate() (time.Duration, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(t.window) == 0 {
+		return t.defaultTime, false
+	}
+	var total time.Duration
+	for _, d := range t.window {
+		total += d
+	}
+	return total / time.Duration(len(t.window)), true
+}
+
+func newTransferTimes() *transferTimes {
+	return &transferTimes{
+		defaultTime: DefaultTransferTime,
+		pending:     make(map[uint64]time.Time),
+		window:      make([]time.Duration, 0, DefaultTransferTimeWindow),
+	}
+}
+
+// Implements V1.
+func (a *v1) EstimateTransferTime(ctx context.Context) (time.Duration, error) {
+	d, ok := a.transferTimes.estimate()
+	if !ok {
+		return d, ErrNoTransferTimeSamples
+	}
+	return d, nil
+}
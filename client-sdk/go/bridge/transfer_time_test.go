@@ -0,0 +1,112 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestEstimateTransferTime(t *testing.T) {
+	ctx := context.Background()
+	rc := newSubmitLockClient()
+	rc.withhold = true
+	clock := NewFakeClock(time.Unix(1000, 0))
+	v := NewV1(rc, WithClock(clock), WithTransferTimeWindow(2), WithDefaultTransferTime(5*time.Minute))
+	body := &Lock{
+		Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination),
+	}
+
+	// Without any completed transfers the default is returned.
+	d, err := v.EstimateTransferTime(ctx)
+	if !errors.Is(err, ErrNoTransferTimeSamples) || d != 5*time.Minute {
+		t.Fatalf("expected the default transfer time with ErrNoTransferTimeSamples, got %s, %v", d, err)
+	}
+
+	// transfer submits a lock and observes its signatures after the given duration.
+	round := uint64(100)
+	transfer := func(after time.Duration) {
+		result, err := v.SubmitLock(ctx, sdkTesting.Alice.Signer, body)
+		if err != nil {
+			t.Fatalf("SubmitLock: %s", err)
+		}
+		clock.Advance(after)
+
+		round++
+		rc.lock.Lock()
+		rc.events[round] = []*coreClient.Event{{
+			Key: WitnessesSignedEventKey,
+			Value: cbor.Marshal(&WitnessesSignedEvent{
+				ID: result.ID,
+				Op: NewLockOperation(*body),
+			}),
+		}}
+		rc.lock.Unlock()
+		if _, err = v.GetEvents(ctx, round); err != nil {
+			t.Fatalf("GetEvents: %s", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		after    time.Duration
+		expected time.Duration
+	}{
+		{30 * time.Second, 30 * time.Second},
+		{60 * time.Second, 45 * time.Second},
+		// The first transfer falls out of the window.
+		{90 * time.Second, 75 * time.Second},
+	} {
+		transfer(tc.after)
+		if d, err = v.EstimateTransferTime(ctx); err != nil || d != tc.expected {
+			t.Fatalf("expected an estimate of %s, got %s, %v", tc.expected, d, err)
+		}
+	}
+
+	// Signatures of locks that have not been submitted by this client are ignored.
+	round++
+	rc.events[round] = []*coreClient.Event{{
+		Key:   WitnessesSignedEventKey,
+		Value: cbor.Marshal(&WitnessesSignedEvent{ID: 1000, Op: NewLockOperation(*body)}),
+	}}
+	clock.Advance(time.Hour)
+	if _, err = v.GetEvents(ctx, round); err != nil {
+		t.Fatalf("GetEvents: %s", err)
+	}
+	if d, err = v.EstimateTransferTime(ctx); err != nil || d != 75*time.Second {
+		t.Fatalf("expected an estimate of 75s, got %s, %v", d, err)
+	}
+}
+
+func TestTransferTimesPendingEviction(t *testing.T) {
+	tt := newTransferTimes()
+	start := time.Unix(1000, 0)
+	for id := uint64(0); id < maxPendingTransfers; id++ {
+		tt.submitted(id, start.Add(time.Duration(id)*time.Second))
+	}
+
+	// Once full, the oldest submitted lock is evicted instead of dropping new submissions.
+	tt.submitted(maxPendingTransfers, start.Add(maxPendingTransfers*time.Second))
+	if len(tt.pending) != maxPendingTransfers {
+		t.Fatalf("expected %d pending transfers, got %d", maxPendingTransfers, len(tt.pending))
+	}
+	if _, ok := tt.pending[0]; ok {
+		t.Fatalf("expected the oldest pending transfer to be evicted")
+	}
+
+	tt.completed(0, start.Add(time.Hour))
+	if _, ok := tt.estimate(); ok {
+		t.Fatalf("expected the evicted transfer not to be taken into account")
+	}
+	tt.completed(maxPendingTransfers, start.Add((maxPendingTransfers+30)*time.Second))
+	if d, ok := tt.estimate(); !ok || d != 30*time.Second {
+		t.Fatalf("expected an estimate of 30s, got %s, %t", d, ok)
+	}
+}
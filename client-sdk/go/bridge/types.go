@@ -0,0 +1,445 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdk "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Event codes emitted by the bridge module.
+const (
+	lockEventCode            = 1
+	releaseEventCode         = 2
+	witnessesSignedEventCode = 3
+)
+
+// Lock is the body of the Lock call.
+type Lock struct {
+	Target RemoteAddress   `json:"target"`
+	Amount types.BaseUnits `json:"amount"`
+}
+
+// LockResult is the result of a Lock method call.
+type LockResult struct {
+	ID uint64 `json:"id"`
+}
+
+// Witness is the body of a Witness call.
+type Witness struct {
+	ID        uint64 `json:"id"`
+	Signature []byte `json:"sig"`
+}
+
+// Release is the body of a Release call.
+type Release struct {
+	ID     uint64          `json:"id"`
+	Target types.Address   `json:"target"`
+	Amount types.BaseUnits `json:"amount"`
+}
+
+// LockEvent is a lock event.
+type LockEvent struct {
+	ID     uint64          `json:"id"`
+	Owner  types.Address   `json:"owner"`
+	Target RemoteAddress   `json:"target"`
+	Amount types.BaseUnits `json:"amount"`
+}
+
+// LockEventKey is the key used for lock events.
+var LockEventKey = sdk.NewEventKey(ModuleName, lockEventCode)
+
+// ReleaseEvent is the release event.
+type ReleaseEvent struct {
+	ID     uint64          `json:"id"`
+	Target types.Address   `json:"target"`
+	Amount types.BaseUnits `json:"amount"`
+
+	// Round is the runtime round in which the event was emitted. It is set for events obtained
+	// via GetEvents (and the helpers built on it) and is not part of the event encoding.
+	Round uint64 `json:"round,omitempty" cbor:"-"`
+}
+
+// ReleaseEventKey is the key used for release events.
+var ReleaseEventKey = sdk.NewEventKey(ModuleName, releaseEventCode)
+
+// ErrInvalidOperation is the error returned when an operation does not have exactly one of its
+// variants set.
+var ErrInvalidOperation = errors.New("bridge: invalid operation")
+
+// Operation is a bridge operation.
+//
+// Exactly one of the fields is set. Use NewLockOperation and NewReleaseOperation to construct
+// operations.
+type Operation struct {
+	Lock    *Lock    `json:"lock,omitempty"`
+	Release *Release `json:"release,omitempty"`
+}
+
+// NewLockOperation creates a new lock operation.
+func NewLockOperation(l Lock) Operation {
+	return Operation{Lock: &l}
+}
+
+// NewReleaseOperation creates a new release operation.
+func NewReleaseOperation(r Release) Operation {
+	return Operation{Release: &r}
+}
+
+// Validate checks that exactly one of the operation variants is set.
+func (op *Operation) Validate() error {
+	switch {
+	case op.Lock != nil && op.Release != nil:
+		return fmt.Errorf("%w: both lock and release set", ErrInvalidOperation)
+	case op.Lock == nil && op.Release == nil:
+		return fmt.Errorf("%w: neither lock nor release set", ErrInvalidOperation)
+	default:
+		return nil
+	}
+}
+
+// OperationKind is the kind of a bridge operation.
+type OperationKind uint8
+
+const (
+	// OperationUnknown is an operation that is neither a lock nor a release.
+	OperationUnknown OperationKind = iota
+	// OperationLock is a lock operation.
+	OperationLock
+	// OperationRelease is a release operation.
+	OperationRelease
+)
+
+// String returns a string representation of the operation kind.
+func (k OperationKind) String() string {
+	switch k {
+	case OperationUnknown:
+		return "unknown"
+	case OperationLock:
+		return "lock"
+	case OperationRelease:
+		return "release"
+	default:
+		return fmt.Sprintf("[unknown operation kind: %d]", uint8(k))
+	}
+}
+
+// Kind returns the kind of the operation.
+func (op *Operation) Kind() OperationKind {
+	switch {
+	case op.Lock != nil && op.Release == nil:
+		return OperationLock
+	case op.Release != nil && op.Lock == nil:
+		return OperationRelease
+	default:
+		return OperationUnknown
+	}
+}
+
+// WitnessesSignedEvent is the witnesses signed event.
+type WitnessesSignedEvent struct {
+	ID         uint64    `json:"id"`
+	Op         Operation `json:"op"`
+	Witnesses  []uint16  `json:"wits,omitempty"`
+	Signatures [][]byte  `json:"sigs,omitempty"`
+}
+
+// Kind returns the kind of the signed operation.
+func (e *WitnessesSignedEvent) Kind() OperationKind {
+	return e.Op.Kind()
+}
+
+// Key returns the key identifying the signed operation.
+func (e *WitnessesSignedEvent) Key() SignedOperationKey {
+	return SignedOperationKey{Kind: e.Kind(), ID: e.ID}
+}
+
+// IsLock returns true iff the signed operation is a lock.
+func (e *WitnessesSignedEvent) IsLock() bool {
+	return e.Kind() == OperationLock
+}
+
+// IsRelease returns true iff the signed operation is a release.
+func (e *WitnessesSignedEvent) IsRelease() bool {
+	return e.Kind() == OperationRelease
+}
+
+// WitnessesSignedEventKey is the key used for witnesses signed events.
+var WitnessesSignedEventKey = sdk.NewEventKey(ModuleName, witnessesSignedEventCode)
+
+// NextSequenceNumbers are the next sequence numbers.
+type NextSequenceNumbers struct {
+	Incoming uint64 `json:"in"`
+	Outgoing uint64 `json:"out"`
+}
+
+// MaxRemoteDenominationLength is the maximum length of a remote denomination in bytes.
+const MaxRemoteDenominationLength = 32
+
+// RemoteDenomination is a remote denomination.
+type RemoteDenomination []byte
+
+// String returns a string representation of a remote denomination.
+func (rd RemoteDenomination) String() string {
+	return hex.EncodeToString([]byte(rd))
+}
+
+// MarshalText encodes the remote denomination into text form.
+func (rd RemoteDenomination) MarshalText() ([]byte, error) {
+	return []byte(rd.String()), nil
+}
+
+// UnmarshalText decodes a text marshalled (hex-encoded) remote denomination.
+func (rd *RemoteDenomination) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*rd = RemoteDenomination(b)
+	return nil
+}
+
+// Parameters are the bridge module parameters.
+type Parameters struct {
+	// Witnesses is a list of authorized witness public keys.
+	Witnesses []types.PublicKey `json:"witnesses"`
+
+	// Threshold is the number of witnesses that needs to sign off.
+	Threshold uint64 `json:"threshold"`
+
+	// LocalDenominations are the denominations local to this side of the bridge.
+	LocalDenominations []types.Denomination `json:"local_denominations"`
+
+	// RemoteDenominations are the denominations that exist on the remote side of the bridge,
+	// keyed by the local denomination representing them on this side.
+	//
+	// Both local and remote denominations are encoded as untagged CBOR byte strings, as expected
+	// by the runtime, so they are only distinguished by their position in the map: keys are
+	// always local denominations and values always remote denominations. For example, mapping
+	// oETH to the remote denomination aabb is encoded as a1 44 6f455448 42 aabb.
+	RemoteDenominations map[types.Denomination]RemoteDenomination `json:"remote_denominations"`
+
+	// RemoteChainID is the identifier of the remote chain.
+	//
+	// The bridge runtime does not expose the remote chain identifier yet in which case it is
+	// empty and the identifier must be supplied by the relayer configuration instead.
+	RemoteChainID RemoteChainID `json:"remote_chain_id,omitempty"`
+}
+
+// MaxWitnesses is the maximum number of authorized witnesses. Witnesses are identified by their
+// uint16 index in the list of authorized witnesses.
+const MaxWitnesses = math.MaxUint16
+
+var (
+	// ErrTooManyWitnesses is the error returned when the parameters contain more than
+	// MaxWitnesses authorized witnesses.
+	ErrTooManyWitnesses = errors.New("bridge: too many witnesses")
+
+	// ErrDenominationLocalAndRemote is the error returned when the parameters contain a
+	// denomination that is both local and remote.
+	ErrDenominationLocalAndRemote = errors.New("bridge: denomination is both local and remote")
+
+	// ErrDuplicateWitness is the error returned when the parameters contain the same witness
+	// public key more than once.
+	ErrDuplicateWitness = errors.New("bridge: duplicate witness")
+
+	// ErrThresholdTooHigh is the error returned when the parameters require more witness
+	// signatures than there are authorized witnesses.
+	ErrThresholdTooHigh = errors.New("bridge: threshold exceeds number of witnesses")
+
+	// ErrInvalidDenominationMapping is the error returned when the parameters contain a remote
+	// denomination mapping that cannot be resolved on both sides of the bridge.
+	ErrInvalidDenominationMapping = errors.New("bridge: invalid denomination mapping")
+)
+
+// ValidateBasic performs the same basic validation of the parameters as the bridge module.
+func (p *Parameters) ValidateBasic() error {
+	if len(p.Witnesses) > MaxWitnesses {
+		return fmt.Errorf("%w: %d (max %d)", ErrTooManyWitnesses, len(p.Witnesses), MaxWitnesses)
+	}
+	for _, local := range p.LocalDenominations {
+		if _, ok := p.RemoteDenominations[local]; ok {
+			return fmt.Errorf("%w: %s", ErrDenominationLocalAndRemote, local)
+		}
+	}
+	return nil
+}
+
+// Validate performs the basic validation (see ValidateBasic) and additionally rejects parameters
+// which are accepted by the bridge module but with which the bridge cannot operate: duplicate
+// witnesses, a threshold that can never be reached and denomination mappings from an unknown
+// local denomination (the native or an oversized one) or to an empty or oversized remote
+// denomination.
+func (p *Parameters) Validate() error {
+	if err := p.ValidateBasic(); err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(p.Witnesses))
+	for _, w := range p.Witnesses {
+		raw := string(cbor.Marshal(w.PublicKey))
+		if _, ok := seen[raw]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateWitness, w)
+		}
+		seen[raw] = struct{}{}
+	}
+	if p.Threshold > uint64(len(p.Witnesses)) {
+		return fmt.Errorf("%w: %d (witnesses %d)", ErrThresholdTooHigh, p.Threshold, len(p.Witnesses))
+	}
+	for _, m := range p.SortedRemoteDenominations() {
+		switch {
+		case m.Local.IsNative() || len(m.Local) > types.MaxDenominationSize:
+			// The native denomination is always local and cannot represent a remote one, while
+			// oversized denominations cannot exist on this side of the bridge at all.
+			return fmt.Errorf("%w: %s is mapped from unknown local denomination %q", ErrInvalidDenominationMapping, m.Remote, string(m.Local))
+		case len(m.Remote) == 0:
+			return fmt.Errorf("%w: %s maps to an empty remote denomination", ErrInvalidDenominationMapping, m.Local)
+		case len(m.Remote) > MaxRemoteDenominationLength:
+			return fmt.Errorf("%w: %s maps to an oversized remote denomination", ErrInvalidDenominationMapping, m.Local)
+		}
+	}
+	return nil
+}
+
+// WitnessIndex returns the index of the given witness public key in the list of authorized
+// witnesses and true, or false if the public key is not an authorized witness.
+//
+// Witnesses whose index does not fit into an uint16 (see ValidateBasic) are never authorized.
+func (p *Parameters) WitnessIndex(pk signature.PublicKey) (uint16, bool) {
+	pk = derefPublicKey(pk)
+	for i, w := range p.Witnesses {
+		if i >= MaxWitnesses {
+			break
+		}
+		if w.Equal(pk) {
+			return uint16(i), true
+		}
+	}
+	return 0, false
+}
+
+// WitnessAddresses returns the runtime account addresses of the authorized witnesses, in the order
+// of the witnesses.
+func (p *Parameters) WitnessAddresses() []types.Address {
+	addresses := make([]types.Address, 0, len(p.Witnesses))
+	for _, w := range p.Witnesses {
+		addresses = append(addresses, AddressOfPublicKey(w.PublicKey))
+	}
+	return addresses
+}
+
+// AuthorizedWitness is an authorized witness together with its index and runtime account address.
+type AuthorizedWitness struct {
+	// Index is the index of the witness in the list of authorized witnesses.
+	Index uint16 `json:"index"`
+
+	// PublicKey is the public key of the witness.
+	PublicKey types.PublicKey `json:"public_key"`
+
+	// Address is the runtime account address of the witness.
+	Address types.Address `json:"address"`
+}
+
+// AuthorizedWitnesses returns the authorized witnesses together with their index and runtime
+// account address, e.g. for cross-referencing witnesses with their on-chain accounts.
+//
+// Like WitnessIndex, witnesses whose index does not fit into an uint16 are omitted.
+func (p *Parameters) AuthorizedWitnesses() []AuthorizedWitness {
+	var witnesses []AuthorizedWitness
+	for i, w := range p.Witnesses {
+		if i >= MaxWitnesses {
+			break
+		}
+		witnesses = append(witnesses, AuthorizedWitness{
+			Index:     uint16(i),
+			PublicKey: w,
+			Address:   AddressOfPublicKey(w.PublicKey),
+		})
+	}
+	return witnesses
+}
+
+// DenominationMapping is a mapping of a local denomination to a remote denomination.
+type DenominationMapping struct {
+	// Local is the local denomination.
+	Local types.Denomination `json:"local"`
+
+	// Remote is the remote denomination.
+	Remote RemoteDenomination `json:"remote"`
+}
+
+// SortedRemoteDenominations returns the remote denomination mappings sorted by local
+// denomination so that they can be iterated over deterministically.
+func (p *Parameters) SortedRemoteDenominations() []DenominationMapping {
+	mappings := make([]DenominationMapping, 0, len(p.RemoteDenominations))
+	for local, remote := range p.RemoteDenominations {
+		mappings = append(mappings, DenominationMapping{
+			Local:  local,
+			Remote: remote,
+		})
+	}
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return string(mappings[i].Local) < string(mappings[j].Local)
+	})
+	return mappings
+}
+
+// SupportedDenominations returns the denominations local to this side of the bridge and the
+// remote denominations that exist on the remote side of the bridge, each sorted so that they can
+// e.g. be listed deterministically. The local denominations representing the remote ones are
+// available via SortedRemoteDenominations.
+func (p *Parameters) SupportedDenominations() ([]types.Denomination, []RemoteDenomination) {
+	local := append([]types.Denomination{}, p.LocalDenominations...)
+	sort.Slice(local, func(i, j int) bool {
+		return string(local[i]) < string(local[j])
+	})
+
+	remote := make([]RemoteDenomination, 0, len(p.RemoteDenominations))
+	for _, rd := range p.RemoteDenominations {
+		remote = append(remote, rd)
+	}
+	sort.Slice(remote, func(i, j int) bool {
+		return bytes.Compare(remote[i], remote[j]) < 0
+	})
+	return local, remote
+}
+
+// ResolveDenomination checks whether the given denomination is supported by the bridge and
+// returns the corresponding remote denomination. In case the denomination is local to this side
+// of the bridge, the returned remote denomination is nil.
+func (p *Parameters) ResolveDenomination(denomination types.Denomination) (RemoteDenomination, bool) {
+	if remote, ok := p.RemoteDenominations[denomination]; ok {
+		return remote, true
+	}
+	for _, local := range p.LocalDenominations {
+		if local == denomination {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// ExpectedRemoteAmount returns the remote denomination and the amount that is expected to arrive
+// on the remote side of the bridge for the given lock, e.g. for showing users the outcome of a
+// transfer before locking. ErrUnsupportedDenomination is returned in case the denomination of the
+// locked amount is not supported by the bridge.
+//
+// In case the denomination is local to this side of the bridge, the returned remote denomination
+// is nil (see ResolveDenomination). The bridge currently transfers amounts 1:1 without charging a
+// fee, so the expected amount equals the locked amount.
+func (p *Parameters) ExpectedRemoteAmount(lock Lock) (RemoteDenomination, *quantity.Quantity, error) {
+	remote, ok := p.ResolveDenomination(lock.Amount.Denomination)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedDenomination, lock.Amount.Denomination)
+	}
+	// TODO: Deduct the bridge fee once the bridge module supports charging one.
+	return remote, lock.Amount.Amount.Clone(), nil
+}
@@ -0,0 +1,238 @@
+package bridge
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreSignature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func testWitnessKey(i int) ed25519.PublicKey {
+	var pk coreSignature.PublicKey
+	binary.BigEndian.PutUint32(pk[:], uint32(i))
+	return ed25519.PublicKey(pk)
+}
+
+func testParameters(n int) *Parameters {
+	params := &Parameters{Witnesses: make([]types.PublicKey, n)}
+	for i := range params.Witnesses {
+		params.Witnesses[i] = types.PublicKey{PublicKey: testWitnessKey(i)}
+	}
+	return params
+}
+
+func TestWitnessIndexBoundary(t *testing.T) {
+	params := testParameters(MaxWitnesses)
+	if err := params.ValidateBasic(); err != nil {
+		t.Fatalf("parameters with %d witnesses should be valid: %s", MaxWitnesses, err)
+	}
+	index, ok := params.WitnessIndex(testWitnessKey(MaxWitnesses - 1))
+	if !ok || index != MaxWitnesses-1 {
+		t.Fatalf("expected last witness to have index %d, got %d (ok: %t)", MaxWitnesses-1, index, ok)
+	}
+
+	params = testParameters(MaxWitnesses + 1)
+	if err := params.ValidateBasic(); !errors.Is(err, ErrTooManyWitnesses) {
+		t.Fatalf("expected ErrTooManyWitnesses, got %v", err)
+	}
+	if index, ok = params.WitnessIndex(testWitnessKey(MaxWitnesses)); ok {
+		t.Fatalf("witness beyond the index range should not be authorized, got index %d", index)
+	}
+}
+
+func TestWitnessIndexDecodedKeys(t *testing.T) {
+	// Both the witnesses of decoded parameters and decoded signer public keys are pointers.
+	var params Parameters
+	if err := cbor.Unmarshal(cbor.Marshal(testParameters(3)), &params); err != nil {
+		t.Fatalf("failed to decode parameters: %s", err)
+	}
+	var pk types.PublicKey
+	if err := cbor.Unmarshal(cbor.Marshal(&types.PublicKey{PublicKey: testWitnessKey(2)}), &pk); err != nil {
+		t.Fatalf("failed to decode public key: %s", err)
+	}
+	if index, ok := params.WitnessIndex(pk.PublicKey); !ok || index != 2 {
+		t.Fatalf("expected decoded witness to have index 2, got %d (ok: %t)", index, ok)
+	}
+}
+
+func TestValidateBasicDenominations(t *testing.T) {
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{types.NativeDenomination},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			types.NativeDenomination: RemoteDenomination("native"),
+		},
+	}
+	if err := params.ValidateBasic(); !errors.Is(err, ErrDenominationLocalAndRemote) {
+		t.Fatalf("expected ErrDenominationLocalAndRemote, got %v", err)
+	}
+}
+
+func TestParametersValidate(t *testing.T) {
+	params := testParameters(3)
+	params.Threshold = 3
+	params.RemoteDenominations = map[types.Denomination]RemoteDenomination{
+		"wETH": RemoteDenomination("eth"),
+	}
+	if err := params.Validate(); err != nil {
+		t.Fatalf("parameters should be valid: %s", err)
+	}
+
+	params.Threshold = 4
+	if err := params.Validate(); !errors.Is(err, ErrThresholdTooHigh) {
+		t.Fatalf("expected ErrThresholdTooHigh, got %v", err)
+	}
+	params.Threshold = 2
+
+	params.Witnesses = append(params.Witnesses, params.Witnesses[1])
+	if err := params.Validate(); !errors.Is(err, ErrDuplicateWitness) {
+		t.Fatalf("expected ErrDuplicateWitness, got %v", err)
+	}
+	params.Witnesses = params.Witnesses[:3]
+
+	params.RemoteDenominations["wBTC"] = RemoteDenomination{}
+	if err := params.Validate(); !errors.Is(err, ErrInvalidDenominationMapping) {
+		t.Fatalf("expected ErrInvalidDenominationMapping, got %v", err)
+	}
+	params.RemoteDenominations["wBTC"] = make(RemoteDenomination, MaxRemoteDenominationLength+1)
+	if err := params.Validate(); !errors.Is(err, ErrInvalidDenominationMapping) {
+		t.Fatalf("expected ErrInvalidDenominationMapping, got %v", err)
+	}
+	delete(params.RemoteDenominations, "wBTC")
+
+	// Mappings must reference a local denomination that can exist on this side of the bridge.
+	for _, local := range []types.Denomination{
+		types.NativeDenomination,
+		types.Denomination(strings.Repeat("w", types.MaxDenominationSize+1)),
+	} {
+		params.RemoteDenominations[local] = RemoteDenomination("btc")
+		if err := params.Validate(); !errors.Is(err, ErrInvalidDenominationMapping) {
+			t.Fatalf("expected ErrInvalidDenominationMapping for local denomination %q, got %v", string(local), err)
+		}
+		delete(params.RemoteDenominations, local)
+	}
+	if err := params.Validate(); err != nil {
+		t.Fatalf("parameters should be valid: %s", err)
+	}
+}
+
+func TestOperationValidate(t *testing.T) {
+	lock := NewLockOperation(Lock{Target: RemoteAddress{1}})
+	if err := lock.Validate(); err != nil {
+		t.Fatalf("lock operation should be valid: %s", err)
+	}
+	if kind := lock.Kind(); kind != OperationLock {
+		t.Fatalf("expected lock operation, got %s", kind)
+	}
+
+	release := NewReleaseOperation(Release{ID: 1})
+	if err := release.Validate(); err != nil {
+		t.Fatalf("release operation should be valid: %s", err)
+	}
+	if kind := release.Kind(); kind != OperationRelease {
+		t.Fatalf("expected release operation, got %s", kind)
+	}
+
+	for _, op := range []Operation{
+		{},
+		{Lock: lock.Lock, Release: release.Release},
+	} {
+		if err := op.Validate(); !errors.Is(err, ErrInvalidOperation) {
+			t.Fatalf("expected ErrInvalidOperation for %+v, got %v", op, err)
+		}
+	}
+	if _, err := NewWitnessSigner(nil, "test-chain-context").SignOperation(1, 1, &Operation{}); !errors.Is(err, ErrInvalidOperation) {
+		t.Fatalf("expected signing an invalid operation to fail with ErrInvalidOperation, got %v", err)
+	}
+}
+
+func TestExpectedRemoteAmount(t *testing.T) {
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{types.NativeDenomination},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			"oETH": RemoteDenomination("eth"),
+		},
+	}
+
+	lock := Lock{Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), "oETH")}
+	remote, amount, err := params.ExpectedRemoteAmount(lock)
+	if err != nil {
+		t.Fatalf("failed to compute expected remote amount: %s", err)
+	}
+	if string(remote) != "eth" || amount.Cmp(&lock.Amount.Amount) != 0 {
+		t.Fatalf("unexpected expected remote amount: %s %s", amount, remote)
+	}
+
+	lock.Amount.Denomination = types.NativeDenomination
+	if remote, _, err = params.ExpectedRemoteAmount(lock); err != nil || remote != nil {
+		t.Fatalf("expected local denomination without remote denomination, got %s (err: %v)", remote, err)
+	}
+
+	lock.Amount.Denomination = "FOO"
+	if _, _, err = params.ExpectedRemoteAmount(lock); !errors.Is(err, ErrUnsupportedDenomination) {
+		t.Fatalf("expected ErrUnsupportedDenomination, got %v", err)
+	}
+}
+
+func TestAuthorizedWitnesses(t *testing.T) {
+	// Decode the parameters so that the witness public keys are pointers as when queried.
+	var params Parameters
+	if err := cbor.Unmarshal(cbor.Marshal(testParameters(3)), &params); err != nil {
+		t.Fatalf("failed to decode parameters: %v", err)
+	}
+
+	addresses := params.WitnessAddresses()
+	witnesses := params.AuthorizedWitnesses()
+	if len(addresses) != 3 || len(witnesses) != 3 {
+		t.Fatalf("expected 3 addresses and witnesses, got %d and %d", len(addresses), len(witnesses))
+	}
+	for i, w := range witnesses {
+		expected := types.NewAddress(testWitnessKey(i))
+		if !addresses[i].Equal(expected) {
+			t.Fatalf("expected address %s for witness %d, got %s", expected, i, addresses[i])
+		}
+		if w.Index != uint16(i) || !w.Address.Equal(expected) || !w.PublicKey.Equal(testWitnessKey(i)) {
+			t.Fatalf("unexpected witness %d: %+v", i, w)
+		}
+	}
+
+	if n := len(testParameters(MaxWitnesses + 1).AuthorizedWitnesses()); n != MaxWitnesses {
+		t.Fatalf("expected witnesses beyond the index range to be omitted, got %d witnesses", n)
+	}
+}
+
+func TestSupportedDenominations(t *testing.T) {
+	params := &Parameters{
+		LocalDenominations: []types.Denomination{"TEST", types.NativeDenomination, "ABC"},
+		RemoteDenominations: map[types.Denomination]RemoteDenomination{
+			"oETH": RemoteDenomination("ETH"),
+			"oBTC": RemoteDenomination("BTC"),
+			"oDAI": RemoteDenomination("DAI"),
+		},
+	}
+	rc := &rotatingClient{params: map[uint64]*Parameters{1: params}}
+	local, remote, err := NewV1(rc).SupportedDenominations(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("SupportedDenominations: %v", err)
+	}
+
+	expectedLocal := []types.Denomination{types.NativeDenomination, "ABC", "TEST"}
+	if !reflect.DeepEqual(local, expectedLocal) {
+		t.Fatalf("expected local denominations %v, got %v", expectedLocal, local)
+	}
+	expectedRemote := []RemoteDenomination{RemoteDenomination("BTC"), RemoteDenomination("DAI"), RemoteDenomination("ETH")}
+	if !reflect.DeepEqual(remote, expectedRemote) {
+		t.Fatalf("expected remote denominations %v, got %v", expectedRemote, remote)
+	}
+	if params.LocalDenominations[0] != "TEST" {
+		t.Fatalf("parameters must not be modified")
+	}
+}
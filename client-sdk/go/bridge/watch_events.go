@@ -0,0 +1,356 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// EventFilter is a predicate that selects which events are delivered by an event watcher.
+type EventFilter func(ev *Event) bool
+
+// OnlyLocks returns a filter that only selects lock events.
+func OnlyLocks() EventFilter {
+	return func(ev *Event) bool {
+		return ev.Lock != nil
+	}
+}
+
+// OnlyReleases returns a filter that only selects release events.
+func OnlyReleases() EventFilter {
+	return func(ev *Event) bool {
+		return ev.Release != nil
+	}
+}
+
+// OnlyWitnessesSigned returns a filter that only selects witnesses signed events.
+func OnlyWitnessesSigned() EventFilter {
+	return func(ev *Event) bool {
+		return ev.WitnessesSigned != nil
+	}
+}
+
+// TargetedAt returns a filter that only selects lock events targeting the given remote address.
+func TargetedAt(target RemoteAddress) EventFilter {
+	return func(ev *Event) bool {
+		return ev.Lock != nil && ev.Lock.Target.Equal(target)
+	}
+}
+
+// OverflowPolicy configures what an event watcher does when the consumer does not keep up with
+// the events and the event channel buffer is full.
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock blocks the event watcher until the consumer receives the next event. No
+	// events are lost, but processing of further blocks is delayed.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered event to make room for the new one. Each
+	// discarded event is counted by the oasis_bridge_dropped_events metric.
+	OverflowDropOldest
+)
+
+// String returns a string representation of the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	default:
+		return fmt.Sprintf("[unknown overflow policy: %d]", uint8(p))
+	}
+}
+
+// WithEventBuffer configures the size of the event channel buffer of event watchers.
+//
+// By default the event channel is unbuffered.
+func WithEventBuffer(n int) WatchOption {
+	return func(o *watchOptions) {
+		o.eventBuffer = n
+	}
+}
+
+// WithOverflowPolicy configures the policy of event watchers for when the event channel buffer
+// is full.
+//
+// By default OverflowBlock is used. As OverflowDropOldest requires somewhere to drop events from,
+// a buffer of at least one event is used with it.
+func WithOverflowPolicy(policy OverflowPolicy) WatchOption {
+	return func(o *watchOptions) {
+		o.overflow = policy
+	}
+}
+
+// WithConfirmations configures event watchers to hold back each event until the runtime has
+// advanced by the given number of rounds past the round in which it was emitted, based on the
+// kind of the event's operation (see Event.Kind). Held back events are discarded in case the
+// runtime goes back to their round, so only events that survived the configured depth are
+// delivered.
+//
+// Events are still delivered in order, so an event may be held back further until all earlier
+// events have been delivered. Kinds missing from the map use the largest configured depth.
+//
+// By default events are delivered as soon as their round is seen, which is safe as long as the
+// runtime does not revert finalized rounds. When acting on events in a way that cannot be undone,
+// a deeper depth for locks (funds leaving this side) than for releases, e.g.,
+// {OperationLock: 2, OperationRelease: 1}, is recommended.
+func WithConfirmations(depths map[OperationKind]uint64) WatchOption {
+	return func(o *watchOptions) {
+		o.confirmations = depths
+	}
+}
+
+// WithStartRound configures event watchers to start with the events of the given round instead of
+// the events of the rounds seen after subscribing. The events of rounds up to the latest round
+// are replayed (see V1.ReplayEvents) before switching over to live events without missing or
+// repeating any rounds in between.
+//
+// The option is ignored by block watchers.
+func WithStartRound(round uint64) WatchOption {
+	return func(o *watchOptions) {
+		o.startRound = round
+		o.startRoundValid = true
+	}
+}
+
+// EventSubscription is a bridge event subscription.
+type EventSubscription struct {
+	v             *v1
+	filter        EventFilter
+	overflow      OverflowPolicy
+	confirmations map[OperationKind]uint64
+	maxDepth      uint64
+	startRound    *uint64
+	ch            chan *Event
+	cancel        context.CancelFunc
+	done          chan struct{}
+	err           error
+
+	pending        []*Event
+	lastRound      uint64
+	lastRoundValid bool
+}
+
+// Close unsubscribes the subscription.
+//
+// Once Close returns, the goroutines backing the subscription have terminated and the event
+// channel has been closed. Close may be called multiple times.
+func (s *EventSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// Err returns the error that caused the subscription to terminate. It must only be called after
+// the event channel has been closed.
+func (s *EventSubscription) Err() error {
+	return s.err
+}
+
+func (s *EventSubscription) worker(ctx context.Context, blkCh <-chan *roothash.AnnotatedBlock, blkSub *BlockSubscription) {
+	defer close(s.done)
+	defer close(s.ch)
+	defer blkSub.Close()
+
+	// Rounds before skipBelow have already been handled and are skipped until the first live
+	// round is handled.
+	var skipBelow uint64
+	if s.startRound != nil {
+		var err error
+		if skipBelow, err = s.replay(ctx, *s.startRound); err != nil {
+			s.err = err
+			return
+		}
+	}
+
+	for {
+		var round uint64
+		select {
+		case <-ctx.Done():
+			s.err = ctx.Err()
+			return
+		case blk, ok := <-blkCh:
+			if !ok {
+				s.err = blkSub.Err()
+				return
+			}
+			round = blk.Block.Header.Round
+		}
+		if round < skipBelow {
+			continue
+		}
+		skipBelow = 0
+
+		events, err := s.v.GetEvents(ctx, round)
+		if err != nil {
+			s.err = err
+			return
+		}
+		if err = s.handleRound(ctx, round, events); err != nil {
+			s.err = err
+			return
+		}
+	}
+}
+
+// replay handles the events of the rounds from the given round up to the latest round and returns
+// the round following the last handled round.
+func (s *EventSubscription) replay(ctx context.Context, startRound uint64) (uint64, error) {
+	// The block subscription has already been established, so blocks of all rounds after the
+	// latest round are delivered by it.
+	latest, err := s.v.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return 0, fmt.Errorf("bridge: failed to fetch latest block: %w", err)
+	}
+	if startRound > latest.Header.Round {
+		return startRound, nil
+	}
+
+	err = s.v.ReplayEvents(ctx, startRound, latest.Header.Round, func(round uint64, events []*Event) error {
+		return s.handleRound(ctx, round, events)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return latest.Header.Round + 1, nil
+}
+
+// handleRound queues the events of the given round and delivers all queued events that have
+// enough confirmations.
+func (s *EventSubscription) handleRound(ctx context.Context, round uint64, events []*Event) error {
+	if s.lastRoundValid && round <= s.lastRound {
+		s.discardPending(round)
+	}
+	s.lastRound = round
+	s.lastRoundValid = true
+
+	for _, ev := range events {
+		if s.filter != nil && !s.filter(ev) {
+			continue
+		}
+		s.pending = append(s.pending, ev)
+	}
+
+	// Deliver events in order for as long as they have enough confirmations.
+	for len(s.pending) > 0 && s.pending[0].Round+s.depth(s.pending[0]) <= round {
+		ev := s.pending[0]
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+
+		if err := s.deliver(ctx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// depth returns the number of confirmations required before the given event is delivered.
+func (s *EventSubscription) depth(ev *Event) uint64 {
+	if depth, ok := s.confirmations[ev.Kind()]; ok {
+		return depth
+	}
+	return s.maxDepth
+}
+
+// discardPending discards held back events emitted in rounds the runtime went back from.
+func (s *EventSubscription) discardPending(round uint64) {
+	kept := s.pending[:0]
+	for _, ev := range s.pending {
+		if ev.Round < round {
+			kept = append(kept, ev)
+		}
+	}
+	if discarded := len(s.pending) - len(kept); discarded > 0 {
+		s.v.logger.Warn("discarding unconfirmed events of reverted rounds",
+			"round", round,
+			"discarded", discarded,
+		)
+	}
+	for i := len(kept); i < len(s.pending); i++ {
+		s.pending[i] = nil
+	}
+	s.pending = kept
+}
+
+func (s *EventSubscription) deliver(ctx context.Context, ev *Event) error {
+	if s.overflow != OverflowDropOldest {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s.ch <- ev:
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s.ch <- ev:
+			return nil
+		default:
+		}
+
+		// The buffer is full, discard the oldest event unless the consumer got to it first.
+		select {
+		case dropped := <-s.ch:
+			droppedEvents.Inc()
+			s.v.logger.Debug("dropped event as the consumer is not keeping up",
+				"round", dropped.Round,
+			)
+		default:
+		}
+	}
+}
+
+// Implements V1.
+func (a *v1) WatchEvents(ctx context.Context, opts ...WatchOption) (<-chan *Event, *EventSubscription, error) {
+	return a.WatchEventsFiltered(ctx, nil, opts...)
+}
+
+// Implements V1.
+func (a *v1) WatchEventsFiltered(
+	ctx context.Context,
+	filter EventFilter,
+	opts ...WatchOption,
+) (<-chan *Event, *EventSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	blkCh, blkSub, err := a.WatchBlocks(ctx, opts...)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	o := newWatchOptions(opts...)
+	buffer := o.eventBuffer
+	if o.overflow == OverflowDropOldest {
+		initMetrics()
+		if buffer < 1 {
+			buffer = 1
+		}
+	}
+
+	s := &EventSubscription{
+		v:             a,
+		filter:        filter,
+		overflow:      o.overflow,
+		confirmations: o.confirmations,
+		ch:            make(chan *Event, buffer),
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+	if o.startRoundValid {
+		s.startRound = &o.startRound
+	}
+	for _, depth := range o.confirmations {
+		if depth > s.maxDepth {
+			s.maxDepth = depth
+		}
+	}
+	go s.worker(ctx, blkCh, blkSub)
+
+	return s.ch, s, nil
+}
@@ -0,0 +1,252 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+func TestDeliverDropOldest(t *testing.T) {
+	s := &EventSubscription{
+		v:        NewV1(nil).(*v1),
+		overflow: OverflowDropOldest,
+		ch:       make(chan *Event, 2),
+	}
+
+	before := testutil.ToFloat64(droppedEvents)
+	for round := uint64(1); round <= 4; round++ {
+		if err := s.deliver(context.Background(), &Event{Round: round}); err != nil {
+			t.Fatalf("deliver round %d: %s", round, err)
+		}
+	}
+	if dropped := testutil.ToFloat64(droppedEvents) - before; dropped != 2 {
+		t.Fatalf("expected 2 dropped events, got %v", dropped)
+	}
+	for _, expected := range []uint64{3, 4} {
+		if ev := <-s.ch; ev.Round != expected {
+			t.Fatalf("expected event of round %d, got round %d", expected, ev.Round)
+		}
+	}
+}
+
+func TestDeliverBlock(t *testing.T) {
+	s := &EventSubscription{
+		v:        NewV1(nil).(*v1),
+		overflow: OverflowBlock,
+		ch:       make(chan *Event, 1),
+	}
+	if err := s.deliver(context.Background(), &Event{Round: 1}); err != nil {
+		t.Fatalf("deliver: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.deliver(ctx, &Event{Round: 2}); err != context.Canceled {
+		t.Fatalf("expected blocked delivery to be canceled, got %v", err)
+	}
+}
+
+func TestConfirmations(t *testing.T) {
+	s := &EventSubscription{
+		v:             NewV1(nil).(*v1),
+		confirmations: map[OperationKind]uint64{OperationLock: 2, OperationRelease: 0},
+		maxDepth:      2,
+		ch:            make(chan *Event, 10),
+	}
+	handle := func(round uint64, events ...*Event) {
+		if err := s.handleRound(context.Background(), round, events); err != nil {
+			t.Fatalf("handle round %d: %s", round, err)
+		}
+	}
+	expect := func(ids ...uint64) {
+		t.Helper()
+		for _, id := range ids {
+			select {
+			case ev := <-s.ch:
+				var evID uint64
+				switch {
+				case ev.Lock != nil:
+					evID = ev.Lock.ID
+				case ev.Release != nil:
+					evID = ev.Release.ID
+				default:
+					evID = ev.WitnessesSigned.ID
+				}
+				if evID != id {
+					t.Fatalf("expected event %d, got %d", id, evID)
+				}
+			default:
+				t.Fatalf("expected event %d to be delivered", id)
+			}
+		}
+		if len(s.ch) != 0 {
+			t.Fatalf("unexpected %d delivered event(s)", len(s.ch))
+		}
+	}
+
+	// Releases are delivered immediately.
+	handle(1, &Event{Round: 1, Release: &ReleaseEvent{ID: 1}})
+	expect(1)
+
+	// Locks wait for two more rounds and hold back later events.
+	handle(2, &Event{Round: 2, Lock: &LockEvent{ID: 2}})
+	handle(3, &Event{Round: 3, Release: &ReleaseEvent{ID: 3}})
+	expect()
+	handle(4)
+	expect(2, 3)
+
+	// Unknown kinds use the largest depth.
+	handle(5, &Event{Round: 5, WitnessesSigned: &WitnessesSignedEvent{ID: 5}})
+	handle(6)
+	expect()
+	handle(7)
+	expect(5)
+
+	// Unconfirmed events of reverted rounds are discarded.
+	handle(8, &Event{Round: 8, Lock: &LockEvent{ID: 8}})
+	handle(9)
+	handle(8, &Event{Round: 8, Lock: &LockEvent{ID: 9}})
+	handle(9)
+	handle(10)
+	expect(9)
+}
+
+// historyClient is a watch client whose rounds each contain a single lock event with the round
+// as its identifier.
+type historyClient struct {
+	watchClient
+
+	latest uint64
+}
+
+func (rc *historyClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	return &blk, nil
+}
+
+func (rc *historyClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	return []*coreClient.Event{{
+		Key:   LockEventKey,
+		Value: cbor.Marshal(&LockEvent{ID: round}),
+	}}, nil
+}
+
+func TestWatchEventsStartRound(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		startRound uint64
+		expected   []uint64
+	}{
+		{"History", 3, []uint64{3, 4, 5, 6, 7}},
+		{"Future", 7, []uint64{7}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &historyClient{
+				latest: 5,
+			}
+			evCh, evSub, err := NewV1(rc).WatchEvents(context.Background(), WithStartRound(tc.startRound))
+			if err != nil {
+				t.Fatalf("failed to watch events: %s", err)
+			}
+			defer evSub.Close()
+
+			// The latest round is also delivered by the subscription and must not be repeated.
+			go func() {
+				for round := uint64(5); round <= 7; round++ {
+					rc.produce(round)
+				}
+			}()
+			for _, expected := range tc.expected {
+				if ev := <-evCh; ev.Lock == nil || ev.Lock.ID != expected || ev.Round != expected {
+					t.Fatalf("expected lock of round %d, got %+v", expected, ev)
+				}
+			}
+		})
+	}
+}
+
+// eventsClient is a watch client whose rounds contain the configured events.
+type eventsClient struct {
+	watchClient
+
+	events map[uint64][]*coreClient.Event
+}
+
+func (rc *eventsClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	return rc.events[round], nil
+}
+
+func TestWatchEventsFiltered(t *testing.T) {
+	targetA := NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314")
+	targetB := NewRemoteAddressFromHex("1415161718191a1b1c1d1e1f2021222324252627")
+	lock := func(id uint64, target RemoteAddress) *coreClient.Event {
+		return &coreClient.Event{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: id, Target: target})}
+	}
+	release := func(id uint64) *coreClient.Event {
+		return &coreClient.Event{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: id})}
+	}
+	witnessesSigned := func(id uint64) *coreClient.Event {
+		op := NewLockOperation(Lock{})
+		return &coreClient.Event{Key: WitnessesSignedEventKey, Value: cbor.Marshal(&WitnessesSignedEvent{ID: id, Op: op})}
+	}
+	events := map[uint64][]*coreClient.Event{
+		1: {lock(1, targetA), lock(2, targetB), release(1)},
+		2: {witnessesSigned(1), lock(3, targetA), release(2)},
+		// The last round contains events of all kinds so that all filters select some of them.
+		3: {lock(4, targetA), lock(5, targetB), release(3), witnessesSigned(4)},
+	}
+	describe := func(ev *Event) string {
+		switch {
+		case ev.Lock != nil:
+			return fmt.Sprintf("lock-%d", ev.Lock.ID)
+		case ev.Release != nil:
+			return fmt.Sprintf("release-%d", ev.Release.ID)
+		default:
+			return fmt.Sprintf("signed-%d", ev.WitnessesSigned.ID)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   EventFilter
+		expected []string
+	}{
+		{"All", nil, []string{
+			"lock-1", "lock-2", "release-1",
+			"signed-1", "lock-3", "release-2",
+			"lock-4", "lock-5", "release-3", "signed-4",
+		}},
+		{"TargetedAtA", TargetedAt(targetA), []string{"lock-1", "lock-3", "lock-4"}},
+		{"TargetedAtB", TargetedAt(targetB), []string{"lock-2", "lock-5"}},
+		{"OnlyLocks", OnlyLocks(), []string{"lock-1", "lock-2", "lock-3", "lock-4", "lock-5"}},
+		{"OnlyReleases", OnlyReleases(), []string{"release-1", "release-2", "release-3"}},
+		{"OnlyWitnessesSigned", OnlyWitnessesSigned(), []string{"signed-1", "signed-4"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &eventsClient{events: events}
+			evCh, evSub, err := NewV1(rc).WatchEventsFiltered(context.Background(), tc.filter)
+			if err != nil {
+				t.Fatalf("failed to watch events: %s", err)
+			}
+			defer evSub.Close()
+
+			go func() {
+				for round := uint64(1); round <= 3; round++ {
+					rc.produce(round)
+				}
+			}()
+			for _, expected := range tc.expected {
+				if ev := <-evCh; describe(ev) != expected {
+					t.Fatalf("expected %s, got %s", expected, describe(ev))
+				}
+			}
+		})
+	}
+}
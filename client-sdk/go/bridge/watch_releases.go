@@ -0,0 +1,62 @@
+package bridge
+
+import (
+	"context"
+)
+
+// ReleaseSubscription is a bridge release event subscription.
+type ReleaseSubscription struct {
+	evSub  *EventSubscription
+	ch     chan *ReleaseEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close unsubscribes the subscription.
+//
+// Once Close returns, the goroutines backing the subscription have terminated and the release
+// channel has been closed. Close may be called multiple times.
+func (s *ReleaseSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// Err returns the error that caused the subscription to terminate. It must only be called after
+// the release event channel has been closed.
+func (s *ReleaseSubscription) Err() error {
+	return s.evSub.Err()
+}
+
+func (s *ReleaseSubscription) worker(ctx context.Context, evCh <-chan *Event) {
+	defer close(s.done)
+	defer close(s.ch)
+	defer s.evSub.Close()
+
+	for ev := range evCh {
+		select {
+		case <-ctx.Done():
+			return
+		case s.ch <- ev.Release:
+		}
+	}
+}
+
+// Implements V1.
+func (a *v1) WatchReleases(ctx context.Context, opts ...WatchOption) (<-chan *ReleaseEvent, *ReleaseSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	evCh, evSub, err := a.WatchEventsFiltered(ctx, OnlyReleases(), opts...)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	s := &ReleaseSubscription{
+		evSub:  evSub,
+		ch:     make(chan *ReleaseEvent),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.worker(ctx, evCh)
+
+	return s.ch, s, nil
+}
@@ -0,0 +1,54 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+func TestWatchReleases(t *testing.T) {
+	release := func(id uint64) *coreClient.Event {
+		return &coreClient.Event{Key: ReleaseEventKey, Value: cbor.Marshal(&ReleaseEvent{ID: id})}
+	}
+	rc := &eventsClient{
+		events: map[uint64][]*coreClient.Event{
+			1: {{Key: LockEventKey, Value: cbor.Marshal(&LockEvent{ID: 1})}, release(1)},
+			2: {{Key: WitnessesSignedEventKey, Value: cbor.Marshal(&WitnessesSignedEvent{ID: 1, Op: NewLockOperation(Lock{})})}},
+			3: {release(2), release(3)},
+		},
+	}
+	relCh, relSub, err := NewV1(rc).WatchReleases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to watch releases: %s", err)
+	}
+
+	go func() {
+		for round := uint64(1); round <= 3; round++ {
+			rc.produce(round)
+		}
+	}()
+
+	// Only releases are delivered, each with the round it was made in.
+	for _, expected := range []struct {
+		id    uint64
+		round uint64
+	}{
+		{1, 1},
+		{2, 3},
+		{3, 3},
+	} {
+		ev := <-relCh
+		if ev.ID != expected.id || ev.Round != expected.round {
+			t.Fatalf("expected release %d of round %d, got release %d of round %d", expected.id, expected.round, ev.ID, ev.Round)
+		}
+	}
+
+	relSub.Close()
+	for range relCh {
+	}
+	if err = relSub.Err(); err != context.Canceled {
+		t.Fatalf("expected subscription to be canceled, got %v", err)
+	}
+}
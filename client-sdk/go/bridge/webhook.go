@@ -0,0 +1,240 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// WebhookKind is the kind of a webhook notification.
+type WebhookKind string
+
+const (
+	// WebhookWitnessed is the kind of notifications about locks witnessed by a managed witness.
+	WebhookWitnessed WebhookKind = "witnessed"
+	// WebhookReleased is the kind of notifications about observed releases.
+	WebhookReleased WebhookKind = "released"
+)
+
+// WebhookPayload is the JSON document POSTed by a webhook notifier.
+type WebhookPayload struct {
+	// Kind is the kind of the notification.
+	Kind WebhookKind `json:"kind"`
+
+	// Lock is the witnessed lock for WebhookWitnessed notifications.
+	Lock *LockEvent `json:"lock,omitempty"`
+	// TxHash is the hash of the bridge.Witness transaction for WebhookWitnessed notifications.
+	TxHash *hash.Hash `json:"tx_hash,omitempty"`
+
+	// Release is the observed release for WebhookReleased notifications.
+	Release *ReleaseEvent `json:"release,omitempty"`
+}
+
+// WebhookRetryPolicy configures how the delivery of a webhook notification is retried.
+type WebhookRetryPolicy struct {
+	// MaxRetries is the maximum number of retries after a failed delivery before the notification
+	// is dropped. Zero disables retries.
+	MaxRetries uint64
+
+	// InitialInterval is the delay before the first retry. Subsequent retries are delayed
+	// exponentially.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum delay between retries.
+	MaxInterval time.Duration
+}
+
+// DefaultWebhookRetryPolicy is the default webhook retry policy.
+var DefaultWebhookRetryPolicy = WebhookRetryPolicy{
+	MaxRetries:      5,
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     30 * time.Second,
+}
+
+func (p *WebhookRetryPolicy) backOff(ctx context.Context, clock Clock) backoff.BackOff {
+	eb := backoff.NewExponentialBackOff()
+	eb.Clock = clock
+	eb.InitialInterval = p.InitialInterval
+	eb.MaxInterval = p.MaxInterval
+	eb.MaxElapsedTime = 0
+	return backoff.WithContext(backoff.WithMaxRetries(eb, p.MaxRetries), ctx)
+}
+
+// WebhookOption is an option for configuring a webhook notifier.
+type WebhookOption func(n *WebhookNotifier)
+
+// WithWebhookHTTPClient configures the HTTP client used to deliver notifications.
+//
+// By default a client with a 10 second timeout is used.
+func WithWebhookHTTPClient(c *http.Client) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.client = c
+	}
+}
+
+// WithWebhookRetryPolicy configures how failed deliveries are retried.
+//
+// By default DefaultWebhookRetryPolicy is used.
+func WithWebhookRetryPolicy(policy WebhookRetryPolicy) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.retry = policy
+	}
+}
+
+// WithWebhookClock configures the clock used to delay retries.
+//
+// By default RealClock is used.
+func WithWebhookClock(clock Clock) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.clock = clock
+	}
+}
+
+// WithWebhookLogger configures the logger used by the webhook notifier.
+//
+// By default a logger for the "bridge" module is used.
+func WithWebhookLogger(l *logging.Logger) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.logger = l
+	}
+}
+
+// WebhookNotifier POSTs a WebhookPayload to a configured URL whenever a lock is witnessed (see
+// OnWitnessed) or a release is observed (see WatchReleases).
+//
+// Notifications are delivered in the background and retried with an exponential backoff in case
+// the endpoint cannot be reached or responds with a server error.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+	retry  WebhookRetryPolicy
+	clock  Clock
+	logger *logging.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// OnWitnessed notifies the webhook about a witnessed lock. It can be passed to WithOnWitnessed.
+func (n *WebhookNotifier) OnWitnessed(ev *LockEvent, txHash hash.Hash) {
+	n.notify(&WebhookPayload{
+		Kind:   WebhookWitnessed,
+		Lock:   ev,
+		TxHash: &txHash,
+	})
+}
+
+// WatchReleases notifies the webhook about each release observed via V1.WatchReleases with the
+// given options until the context is canceled or the subscription fails.
+func (n *WebhookNotifier) WatchReleases(ctx context.Context, v V1, opts ...WatchOption) error {
+	ch, sub, err := v.WatchReleases(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("bridge: failed to subscribe to releases: %w", err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-ch:
+			if !ok {
+				return sub.Err()
+			}
+			n.notify(&WebhookPayload{
+				Kind:    WebhookReleased,
+				Release: ev,
+			})
+		}
+	}
+}
+
+// Close stops delivering notifications, abandoning any pending retries, and waits for in-flight
+// deliveries to terminate.
+func (n *WebhookNotifier) Close() {
+	n.cancel()
+	n.wg.Wait()
+}
+
+func (n *WebhookNotifier) notify(payload *WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		n.logger.Error("failed to encode webhook payload",
+			"err", err,
+			"kind", payload.Kind,
+		)
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		err := backoff.RetryNotifyWithTimer(func() error {
+			return n.deliver(body)
+		}, n.retry.backOff(n.ctx, n.clock), func(err error, d time.Duration) {
+			n.logger.Warn("webhook delivery failed, retrying",
+				"err", err,
+				"kind", payload.Kind,
+				"retry_in", d,
+			)
+		}, &backoffTimer{clock: n.clock})
+		if err != nil {
+			n.logger.Error("failed to deliver webhook notification",
+				"err", err,
+				"kind", payload.Kind,
+			)
+		}
+	}()
+}
+
+func (n *WebhookNotifier) deliver(body []byte) error {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	_, _ = io.Copy(io.Discard, rsp.Body)
+
+	switch {
+	case rsp.StatusCode >= 200 && rsp.StatusCode < 300:
+		return nil
+	case rsp.StatusCode >= 500 || rsp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("webhook responded with %s", rsp.Status)
+	default:
+		return backoff.Permanent(fmt.Errorf("webhook responded with %s", rsp.Status))
+	}
+}
+
+// NewWebhookNotifier creates a webhook notifier POSTing notifications to the given URL.
+func NewWebhookNotifier(url string, opts ...WebhookOption) *WebhookNotifier {
+	n := &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		retry:  DefaultWebhookRetryPolicy,
+		clock:  RealClock,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	return n
+}
@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		attempts int
+		payloads []*WebhookPayload
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		// Fail the first delivery attempt.
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("malformed payload: %s", err)
+		}
+		payloads = append(payloads, &payload)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.URL, WithWebhookRetryPolicy(WebhookRetryPolicy{
+		MaxRetries:      3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}))
+	ev := &LockEvent{
+		ID:     7,
+		Owner:  sdkTesting.Alice.Address,
+		Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	}
+	txHash := hash.NewFromBytes([]byte("tx"))
+	n.OnWitnessed(ev, txHash)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lock.Lock()
+		delivered := len(payloads)
+		lock.Unlock()
+		if delivered > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notification has not been delivered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	n.Close()
+
+	if attempts != 2 {
+		t.Fatalf("expected 2 delivery attempts, got %d", attempts)
+	}
+	p := payloads[0]
+	if p.Kind != WebhookWitnessed || !p.Lock.Equal(ev) || p.TxHash == nil || !p.TxHash.Equal(&txHash) {
+		t.Fatalf("unexpected payload: %+v", p)
+	}
+}
@@ -0,0 +1,302 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	coreSignature "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// WitnessSignatureContext is the signature context used for witness signatures.
+const WitnessSignatureContext = "oasis-bridge/witness: v1"
+
+// WitnessSignatureSize is the size of an encoded Ed25519 witness signature in bytes. EIP-712
+// witness signatures are EIP712SignatureSize bytes.
+const WitnessSignatureSize = coreSignature.SignatureSize
+
+// ErrMalformedWitnessSignature is the error returned when decoding a malformed witness signature.
+var ErrMalformedWitnessSignature = errors.New("bridge: malformed witness signature")
+
+// EncodeWitnessSignature encodes the given witness signature, as produced by a WitnessSigner, in
+// the format expected in the Signature field of bridge.Witness transactions.
+//
+// The runtime stores witness signatures as opaque byte strings (the CBOR encoding of the Witness
+// message carries them as a byte string) without any framing or prefix, so the encoding is the
+// raw Ed25519 signature.
+func EncodeWitnessSignature(sig []byte) []byte {
+	return append([]byte{}, sig...)
+}
+
+// DecodeWitnessSignature decodes a witness signature encoded with EncodeWitnessSignature, e.g. as
+// collected in a WitnessesSignedEvent, returning ErrMalformedWitnessSignature in case it is
+// neither a well-formed Ed25519 signature nor a well-formed EIP-712 signature (see
+// NewEIP712WitnessSigner).
+func DecodeWitnessSignature(encoded []byte) ([]byte, error) {
+	if n := len(encoded); n != WitnessSignatureSize && n != EIP712SignatureSize {
+		return nil, fmt.Errorf("%w: expected %d or %d bytes, got %d",
+			ErrMalformedWitnessSignature, WitnessSignatureSize, EIP712SignatureSize, n)
+	}
+	return append([]byte{}, encoded...), nil
+}
+
+// WitnessSigner produces the witness signatures over bridge operations that are submitted to
+// the runtime via bridge.Witness transactions and later relayed to the remote side.
+type WitnessSigner interface {
+	// SignOperation returns the witness signature over the operation with the given identifier
+	// which has been observed in the given runtime round. The round is not part of what is
+	// signed, but is recorded in the signing history.
+	SignOperation(round, id uint64, op *Operation) ([]byte, error)
+
+	// History returns the operations signed at or after the given time.
+	//
+	// ErrNoSigningHistory is returned in case the signer does not keep a signing history (see
+	// WithSigningHistory).
+	History(since time.Time) ([]*SigningRecord, error)
+}
+
+// WitnessSignerOption is an option for configuring a witness signer.
+type WitnessSignerOption func(s *witnessSigner)
+
+// WithSigningHistory configures the witness signer to record every signed operation in the given
+// signing history before returning the signature. Signing fails in case the operation cannot be
+// recorded.
+//
+// A signing history can be shared by multiple witness signers.
+func WithSigningHistory(h *SigningHistory) WitnessSignerOption {
+	return func(s *witnessSigner) {
+		s.history = h
+	}
+}
+
+// WithWitnessSignerClock configures the clock used to timestamp the records of the signing
+// history (see WithSigningHistory).
+//
+// By default RealClock is used.
+func WithWitnessSignerClock(clock Clock) WitnessSignerOption {
+	return func(s *witnessSigner) {
+		s.clock = clock
+	}
+}
+
+// signingPreimage is the structure whose canonical CBOR encoding is signed by witnesses.
+type signingPreimage struct {
+	ChainContext signature.Context `json:"chain_context"`
+	ID           uint64            `json:"id"`
+	Op           *Operation        `json:"op"`
+}
+
+// SigningPreimage returns the message that witnesses sign for the operation with the given
+// identifier. It is the canonical CBOR encoding of a map binding the operation to its sequence
+// number and the runtime's chain context so that a signature cannot be replayed against another
+// operation or on another chain:
+//
+//	{"chain_context": <string>, "id": <uint>, "op": <operation>}
+//
+// The message is signed under the WitnessSignatureContext signature context. Remote verifiers
+// must reconstruct the same preimage, including the binding, when verifying signatures.
+func SigningPreimage(chainContext signature.Context, op *Operation, id uint64) []byte {
+	return cbor.Marshal(&signingPreimage{
+		ChainContext: chainContext,
+		ID:           id,
+		Op:           op,
+	})
+}
+
+type witnessSigner struct {
+	signer       signature.Signer
+	chainContext signature.Context
+	history      *SigningHistory
+	clock        Clock
+}
+
+// Implements WitnessSigner.
+func (s *witnessSigner) SignOperation(round, id uint64, op *Operation) ([]byte, error) {
+	if err := op.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to sign operation %d: %w", id, err)
+	}
+	preimage := SigningPreimage(s.chainContext, op, id)
+	sig, err := s.signer.ContextSign([]byte(WitnessSignatureContext), preimage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign operation %d: %w", id, err)
+	}
+
+	if s.history != nil {
+		err = s.history.append(&SigningRecord{
+			Time:         s.clock.Now(),
+			Round:        round,
+			ID:           id,
+			PublicKey:    types.PublicKey{PublicKey: s.signer.Public()},
+			PreimageHash: hash.NewFromBytes(preimage),
+			Signature:    sig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record signed operation %d: %w", id, err)
+		}
+	}
+	return sig, nil
+}
+
+// Implements WitnessSigner.
+func (s *witnessSigner) History(since time.Time) ([]*SigningRecord, error) {
+	if s.history == nil {
+		return nil, ErrNoSigningHistory
+	}
+	records, err := s.history.Records(since)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history may be shared with other signers.
+	own := records[:0]
+	for _, r := range records {
+		if r.PublicKey.Equal(s.signer.Public()) {
+			own = append(own, r)
+		}
+	}
+	return own, nil
+}
+
+// NewWitnessSigner creates a witness signer that signs the SigningPreimage of operations using
+// the given signer.
+func NewWitnessSigner(signer signature.Signer, chainContext signature.Context, opts ...WitnessSignerOption) WitnessSigner {
+	s := &witnessSigner{
+		signer:       signer,
+		chainContext: chainContext,
+		clock:        RealClock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WitnessSignatureVerification is the result of verifying a single witness signature.
+type WitnessSignatureVerification struct {
+	// Index is the index of the witness in the list of authorized witnesses.
+	Index uint16 `json:"index"`
+
+	// PublicKey is the public key of the witness or nil if the index is not known.
+	PublicKey signature.PublicKey `json:"public_key,omitempty"`
+
+	// Valid is true iff the signature is a valid signature of the witness.
+	Valid bool `json:"valid"`
+
+	// Reason describes why the signature is not valid.
+	Reason string `json:"reason,omitempty"`
+}
+
+// WitnessSignaturesVerification is the result of verifying the witness signatures of a signed
+// operation.
+type WitnessSignaturesVerification struct {
+	// Signatures are the results for each of the signatures in the order they were collected.
+	Signatures []WitnessSignatureVerification `json:"signatures"`
+
+	// Valid is the number of valid signatures of distinct witnesses.
+	Valid uint64 `json:"valid"`
+
+	// Threshold is the number of signatures that are required.
+	Threshold uint64 `json:"threshold"`
+}
+
+// MeetsThreshold returns true iff enough valid signatures have been collected.
+func (v *WitnessSignaturesVerification) MeetsThreshold() bool {
+	return v.Valid >= v.Threshold
+}
+
+// VerifyOption is an option for verifying witness signatures.
+type VerifyOption func(o *verifyOptions)
+
+type verifyOptions struct {
+	eip712Domain *EIP712Domain
+}
+
+// WithEIP712Domain configures the EIP-712 domain under which witnesses with secp256k1 keys sign
+// operations (see NewEIP712WitnessSigner).
+//
+// By default signatures of witnesses with secp256k1 keys are not valid.
+func WithEIP712Domain(domain *EIP712Domain) VerifyOption {
+	return func(o *verifyOptions) {
+		o.eip712Domain = domain
+	}
+}
+
+// verifySignature returns why the given signature of the witness with the given public key over
+// the given signed operation is not valid or an empty string if it is valid.
+//
+// Witnesses with secp256k1 keys are expected to produce EIP-712 signatures while all others are
+// expected to sign the SigningPreimage.
+func (o *verifyOptions) verifySignature(
+	chainContext signature.Context,
+	pk signature.PublicKey,
+	ev *WitnessesSignedEvent,
+	sig []byte,
+) string {
+	switch pk := derefPublicKey(pk).(type) {
+	case secp256k1.PublicKey:
+		if o.eip712Domain == nil {
+			return "EIP-712 domain not configured"
+		}
+		signer, err := RecoverEIP712Signer(o.eip712Domain, chainContext, ev.ID, &ev.Op, sig)
+		if err != nil || !signer.Equal(EthereumAddressOfPublicKey(pk)) {
+			return "invalid signature"
+		}
+	default:
+		if !pk.Verify([]byte(WitnessSignatureContext), SigningPreimage(chainContext, &ev.Op, ev.ID), sig) {
+			return "invalid signature"
+		}
+	}
+	return ""
+}
+
+// VerifyWitnessSignatures verifies the witness signatures collected for the given signed
+// operation against the authorized witnesses of the given parameters.
+//
+// Signatures of witnesses with secp256k1 keys are verified as EIP-712 signatures, which requires
+// the EIP-712 domain (see WithEIP712Domain). Only the first signature of each witness is counted.
+func VerifyWitnessSignatures(
+	chainContext signature.Context,
+	params *Parameters,
+	ev *WitnessesSignedEvent,
+	opts ...VerifyOption,
+) *WitnessSignaturesVerification {
+	var o verifyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	v := &WitnessSignaturesVerification{
+		Signatures: make([]WitnessSignatureVerification, 0, len(ev.Witnesses)),
+		Threshold:  params.Threshold,
+	}
+	seen := make(map[uint16]bool)
+	for i, index := range ev.Witnesses {
+		result := WitnessSignatureVerification{Index: index}
+		switch {
+		case int(index) >= len(params.Witnesses):
+			result.Reason = "unknown witness"
+		case i >= len(ev.Signatures):
+			result.PublicKey = params.Witnesses[index].PublicKey
+			result.Reason = "missing signature"
+		case seen[index]:
+			result.PublicKey = params.Witnesses[index].PublicKey
+			result.Reason = "duplicate witness"
+		default:
+			result.PublicKey = params.Witnesses[index].PublicKey
+			if result.Reason = o.verifySignature(chainContext, result.PublicKey, ev, ev.Signatures[i]); result.Reason != "" {
+				break
+			}
+			result.Valid = true
+			seen[index] = true
+			v.Valid++
+		}
+		v.Signatures = append(v.Signatures, result)
+	}
+	return v
+}
@@ -0,0 +1,383 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSigningPreimage(t *testing.T) {
+	op := &Operation{
+		Lock: &Lock{
+			Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		},
+	}
+	chainContext := signature.Context("test-chain-context")
+
+	// Test vector.
+	preimage := SigningPreimage(chainContext, op, 42)
+	expected := "a3626964182a626f70a1646c6f636ba266616d6f756e7482410a4066746172676574540102030405060708090a0b0c0d0e0f10111213146d636861696e5f636f6e7465787472746573742d636861696e2d636f6e74657874"
+	if actual := hex.EncodeToString(preimage); actual != expected {
+		t.Fatalf("unexpected preimage: expected %s, got %s", expected, actual)
+	}
+
+	// The preimage must bind the operation identifier and the chain context.
+	if bytes.Equal(preimage, SigningPreimage(chainContext, op, 43)) {
+		t.Fatalf("preimage does not bind the operation identifier")
+	}
+	if bytes.Equal(preimage, SigningPreimage("other-chain-context", op, 42)) {
+		t.Fatalf("preimage does not bind the chain context")
+	}
+
+	signer := NewWitnessSigner(sdkTesting.Alice.Signer, chainContext)
+	sig, err := signer.SignOperation(1, 42, op)
+	if err != nil {
+		t.Fatalf("failed to sign operation: %s", err)
+	}
+	expected = "ac4977855f47256081dd656f063066d4a50cbc36aa2860e8b77eb1c0f4fd2838835231bccb77cc0a5020cfe456a10a71fbf8a5e587be0e8cd1db06332646990d"
+	if actual := hex.EncodeToString(sig); actual != expected {
+		t.Fatalf("unexpected signature: expected %s, got %s", expected, actual)
+	}
+	if !sdkTesting.Alice.Signer.Public().Verify([]byte(WitnessSignatureContext), preimage, sig) {
+		t.Fatalf("signature verification failed")
+	}
+}
+
+func TestVerifyWitnessSignatures(t *testing.T) {
+	chainContext := signature.Context("test-chain-context")
+	params := &Parameters{
+		Witnesses: []types.PublicKey{
+			{PublicKey: sdkTesting.Alice.Signer.Public()},
+			{PublicKey: sdkTesting.Bob.Signer.Public()},
+			{PublicKey: sdkTesting.Charlie.Signer.Public()},
+		},
+		Threshold: 2,
+	}
+	ev := &WitnessesSignedEvent{
+		ID: 7,
+		Op: Operation{
+			Lock: &Lock{
+				Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+				Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+			},
+		},
+	}
+	sign := func(signer signature.Signer) []byte {
+		sig, err := NewWitnessSigner(signer, chainContext).SignOperation(1, ev.ID, &ev.Op)
+		if err != nil {
+			t.Fatalf("failed to sign operation: %s", err)
+		}
+		return sig
+	}
+	aliceSig, bobSig := sign(sdkTesting.Alice.Signer), sign(sdkTesting.Bob.Signer)
+
+	ev.Witnesses = []uint16{0, 1}
+	ev.Signatures = [][]byte{aliceSig, bobSig}
+	v := VerifyWitnessSignatures(chainContext, params, ev)
+	if v.Valid != 2 || !v.MeetsThreshold() {
+		t.Fatalf("expected valid signatures to meet threshold, got %+v", v)
+	}
+	if v := VerifyWitnessSignatures("other-chain-context", params, ev); v.Valid != 0 {
+		t.Fatalf("expected signatures to be invalid under another chain context, got %+v", v)
+	}
+
+	// Signatures of the wrong witness, duplicates and unknown witnesses do not count.
+	ev.Witnesses = []uint16{0, 2, 0, 5}
+	ev.Signatures = [][]byte{aliceSig, bobSig, aliceSig, bobSig}
+	v = VerifyWitnessSignatures(chainContext, params, ev)
+	if v.Valid != 1 || v.MeetsThreshold() {
+		t.Fatalf("expected a single valid signature, got %+v", v)
+	}
+	for i, reason := range []string{"", "invalid signature", "duplicate witness", "unknown witness"} {
+		if r := v.Signatures[i]; r.Reason != reason || r.Valid != (reason == "") {
+			t.Fatalf("signature %d: expected reason %q, got %+v", i, reason, r)
+		}
+	}
+}
+
+func TestEncodeWitnessSignature(t *testing.T) {
+	op := &Operation{
+		Lock: &Lock{
+			Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		},
+	}
+	chainContext := signature.Context("test-chain-context")
+	sig, err := NewWitnessSigner(sdkTesting.Alice.Signer, chainContext).SignOperation(1, 42, op)
+	if err != nil {
+		t.Fatalf("SignOperation: %s", err)
+	}
+
+	encoded := EncodeWitnessSignature(sig)
+	decoded, err := DecodeWitnessSignature(encoded)
+	if err != nil {
+		t.Fatalf("DecodeWitnessSignature: %s", err)
+	}
+	if !bytes.Equal(decoded, sig) {
+		t.Fatalf("signature did not round-trip")
+	}
+	if !sdkTesting.Alice.Signer.Public().Verify([]byte(WitnessSignatureContext), SigningPreimage(chainContext, op, 42), decoded) {
+		t.Fatalf("decoded signature does not verify")
+	}
+
+	// The runtime expects the signature as a plain CBOR byte string in the Witness message.
+	raw := cbor.Marshal(&Witness{ID: 42, Signature: encoded})
+	expected := append([]byte{0x58, WitnessSignatureSize}, sig...)
+	if !bytes.Contains(raw, append([]byte("sig"), expected...)) {
+		t.Fatalf("unexpected witness encoding: %x", raw)
+	}
+
+	for _, malformed := range [][]byte{nil, sig[:WitnessSignatureSize-1], append(sig, 0, 0)} {
+		if _, err = DecodeWitnessSignature(malformed); !errors.Is(err, ErrMalformedWitnessSignature) {
+			t.Fatalf("expected ErrMalformedWitnessSignature for %d bytes, got %v", len(malformed), err)
+		}
+	}
+}
+
+func TestVerifyEIP712WitnessSignatures(t *testing.T) {
+	chainContext := signature.Context("test-chain-context")
+	domain := EIP712Domain{
+		Name:              "Oasis Bridge",
+		Version:           "1",
+		ChainID:           1,
+		VerifyingContract: NewRemoteAddressFromHex("cccccccccccccccccccccccccccccccccccccccc"),
+	}
+	privateKeys := [][]byte{make([]byte, 32), make([]byte, 32)}
+	privateKeys[0][31], privateKeys[1][31] = 1, 2
+
+	// Decoded parameters, as queried from the runtime, mixing Ed25519 and secp256k1 witnesses.
+	params := Parameters{
+		Witnesses: []types.PublicKey{
+			{PublicKey: sdkTesting.Alice.Signer.Public()},
+			{PublicKey: secp256k1.NewSigner(privateKeys[0]).Public()},
+			{PublicKey: secp256k1.NewSigner(privateKeys[1]).Public()},
+		},
+		Threshold: 3,
+	}
+	var decodedParams Parameters
+	if err := cbor.Unmarshal(cbor.Marshal(&params), &decodedParams); err != nil {
+		t.Fatalf("failed to decode parameters: %s", err)
+	}
+
+	signers := []WitnessSigner{NewWitnessSigner(sdkTesting.Alice.Signer, chainContext)}
+	for _, privateKey := range privateKeys {
+		signer, err := NewEIP712WitnessSigner(privateKey, chainContext, domain)
+		if err != nil {
+			t.Fatalf("failed to create signer: %s", err)
+		}
+		signers = append(signers, signer)
+	}
+
+	// Sign, relay and verify as a relayer would.
+	ev := &WitnessesSignedEvent{
+		ID: 7,
+		Op: NewLockOperation(Lock{
+			Target: NewRemoteAddressFromHex("0102030405060708090a0b0c0d0e0f1011121314"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		}),
+	}
+	for i, signer := range signers {
+		sig, err := signer.SignOperation(1, ev.ID, &ev.Op)
+		if err != nil {
+			t.Fatalf("failed to sign operation: %s", err)
+		}
+		ev.Witnesses = append(ev.Witnesses, uint16(i))
+		ev.Signatures = append(ev.Signatures, EncodeWitnessSignature(sig))
+	}
+	bundle, err := ev.MarshalRelayBundle()
+	if err != nil {
+		t.Fatalf("MarshalRelayBundle: %s", err)
+	}
+	var relayed WitnessesSignedEvent
+	if err = relayed.UnmarshalRelayBundle(bundle); err != nil {
+		t.Fatalf("UnmarshalRelayBundle: %s", err)
+	}
+	for i, encoded := range relayed.Signatures {
+		if relayed.Signatures[i], err = DecodeWitnessSignature(encoded); err != nil {
+			t.Fatalf("DecodeWitnessSignature: %s", err)
+		}
+	}
+
+	v := VerifyWitnessSignatures(chainContext, &decodedParams, &relayed, WithEIP712Domain(&domain))
+	if v.Valid != 3 || !v.MeetsThreshold() {
+		t.Fatalf("expected all signatures to be valid, got %+v", v)
+	}
+
+	otherDomain := domain
+	otherDomain.ChainID = 2
+	for _, tc := range []struct {
+		name   string
+		opts   []VerifyOption
+		reason string
+	}{
+		{"NoDomain", nil, "EIP-712 domain not configured"},
+		{"OtherDomain", []VerifyOption{WithEIP712Domain(&otherDomain)}, "invalid signature"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := VerifyWitnessSignatures(chainContext, &decodedParams, &relayed, tc.opts...)
+			if v.Valid != 1 {
+				t.Fatalf("expected only the Ed25519 signature to be valid, got %+v", v)
+			}
+			for _, r := range v.Signatures[1:] {
+				if r.Reason != tc.reason {
+					t.Fatalf("expected reason %q, got %+v", tc.reason, r)
+				}
+			}
+		})
+	}
+
+	// Signatures of one witness do not verify for another.
+	relayed.Witnesses = []uint16{0, 2, 1}
+	if v = VerifyWitnessSignatures(chainContext, &decodedParams, &relayed, WithEIP712Domain(&domain)); v.Valid != 1 {
+		t.Fatalf("expected swapped EIP-712 signatures to be invalid, got %+v", v)
+	}
+}
+
+func TestEIP712WitnessSigner(t *testing.T) {
+	// Test vector from the EIP-712 specification.
+	domain := EIP712Domain{
+		Name:              "Ether Mail",
+		Version:           "1",
+		ChainID:           1,
+		VerifyingContract: NewRemoteAddressFromHex("cccccccccccccccccccccccccccccccccccccccc"),
+	}
+	expected := "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"
+	if actual := hex.EncodeToString(domain.Separator()); actual != expected {
+		t.Fatalf("unexpected domain separator: expected %s, got %s", expected, actual)
+	}
+
+	// The address of the private key 1 is well known.
+	privateKey := make([]byte, 32)
+	privateKey[31] = 1
+	pk := secp256k1.NewSigner(privateKey).Public().(secp256k1.PublicKey)
+	address := EthereumAddressOfPublicKey(pk)
+	if expected := NewRemoteAddressFromHex("7e5f4552091a69125d5dfcb7b8c2659029395bdf"); !address.Equal(expected) {
+		t.Fatalf("unexpected address: expected %s, got %s", expected, address)
+	}
+
+	history, err := OpenSigningHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open signing history: %s", err)
+	}
+	defer history.Close()
+	chainContext := signature.Context("test chain context")
+	signer, err := NewEIP712WitnessSigner(privateKey, chainContext, domain, WithSigningHistory(history))
+	if err != nil {
+		t.Fatalf("failed to create signer: %s", err)
+	}
+
+	op := &Operation{
+		Release: &Release{
+			ID:     7,
+			Target: sdkTesting.Alice.Address,
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), "oETH"),
+		},
+	}
+	sig, err := signer.SignOperation(1, 42, op)
+	if err != nil {
+		t.Fatalf("failed to sign operation: %s", err)
+	}
+	if len(sig) != EIP712SignatureSize || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("malformed signature: %x", sig)
+	}
+
+	recovered, err := RecoverEIP712Signer(&domain, chainContext, 42, op, sig)
+	if err != nil {
+		t.Fatalf("failed to recover signer: %s", err)
+	}
+	if !recovered.Equal(address) {
+		t.Fatalf("unexpected signer: expected %s, got %s", address, recovered)
+	}
+
+	// Recovery identifiers without the offset are accepted too.
+	raw := append([]byte{}, sig...)
+	raw[64] -= 27
+	if recovered, err = RecoverEIP712Signer(&domain, chainContext, 42, op, raw); err != nil || !recovered.Equal(address) {
+		t.Fatalf("failed to recover signer from raw recovery identifier: %v", err)
+	}
+
+	// Signatures must not verify for other operations or domains.
+	if recovered, err = RecoverEIP712Signer(&domain, chainContext, 43, op, sig); err == nil && recovered.Equal(address) {
+		t.Fatalf("signature verified for another operation identifier")
+	}
+	other := domain
+	other.ChainID = 2
+	if recovered, err = RecoverEIP712Signer(&other, chainContext, 42, op, sig); err == nil && recovered.Equal(address) {
+		t.Fatalf("signature verified for another domain")
+	}
+
+	// Operations of different runtimes have different digests, so that signatures cannot be
+	// replayed across runtimes.
+	digest, err := EIP712Digest(&domain, chainContext, 42, op)
+	if err != nil {
+		t.Fatalf("failed to compute digest: %s", err)
+	}
+	otherDigest, err := EIP712Digest(&domain, "other chain context", 42, op)
+	if err != nil {
+		t.Fatalf("failed to compute digest: %s", err)
+	}
+	if bytes.Equal(digest, otherDigest) {
+		t.Fatalf("digest does not depend on the chain context")
+	}
+	if recovered, err = RecoverEIP712Signer(&domain, "other chain context", 42, op, sig); err == nil && recovered.Equal(address) {
+		t.Fatalf("signature verified for another chain context")
+	}
+
+	if _, err = RecoverEIP712Signer(&domain, chainContext, 42, op, sig[:64]); !errors.Is(err, ErrMalformedEIP712Signature) {
+		t.Fatalf("expected ErrMalformedEIP712Signature, got %v", err)
+	}
+	other.VerifyingContract = RemoteAddress{0xcc, 0xcc}
+	if _, err = NewEIP712WitnessSigner(privateKey, chainContext, other); !errors.Is(err, ErrInvalidEIP712Domain) {
+		t.Fatalf("expected ErrInvalidEIP712Domain, got %v", err)
+	}
+
+	records, err := signer.History(time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get history: %s", err)
+	}
+	if len(records) != 1 || records[0].ID != 42 || !bytes.Equal(records[0].Signature, sig) {
+		t.Fatalf("unexpected history: %+v", records)
+	}
+
+	// The typed data describes the signed operation.
+	data, err := EIP712TypedData(&domain, chainContext, 42, op)
+	if err != nil {
+		t.Fatalf("failed to get typed data: %s", err)
+	}
+	var typedData struct {
+		PrimaryType string                 `json:"primaryType"`
+		Domain      map[string]interface{} `json:"domain"`
+		Message     map[string]interface{} `json:"message"`
+	}
+	if err = json.Unmarshal(data, &typedData); err != nil {
+		t.Fatalf("malformed typed data: %s", err)
+	}
+	if typedData.PrimaryType != "Operation" {
+		t.Fatalf("unexpected primary type: %s", typedData.PrimaryType)
+	}
+	if v := typedData.Domain["verifyingContract"]; v != "0xcccccccccccccccccccccccccccccccccccccccc" {
+		t.Fatalf("unexpected verifying contract: %v", v)
+	}
+	if v := typedData.Message["chainContext"]; v != string(chainContext) {
+		t.Fatalf("unexpected chain context: %v", v)
+	}
+	if v := typedData.Message["amount"]; v != "10" {
+		t.Fatalf("unexpected amount: %v", v)
+	}
+	if v := typedData.Message["denomination"]; v != "0x6f455448" {
+		t.Fatalf("unexpected denomination: %v", v)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// CfgNodeAddr configures the gRPC addresses of the Oasis nodes to connect to.
+	CfgNodeAddr = "node-addr"
+	// CfgRuntimeID configures the bridge runtime identifier.
+	CfgRuntimeID = "runtime-id"
+	// CfgTestKey configures the test keys used for signing.
+	CfgTestKey = "test-key"
+	// CfgKeyring configures the source of the keys used for signing (see bridge.LoadKeyring).
+	CfgKeyring = "keyring"
+	// CfgLogRawEvents configures whether the raw bytes of all fetched events are logged.
+	CfgLogRawEvents = "log-raw-events"
+	// CfgGetEventsTimeout configures the timeout of each call fetching the events of a round.
+	CfgGetEventsTimeout = "get-events-timeout"
+	// CfgExpectedChainContext configures the chain context the nodes are expected to have.
+	CfgExpectedChainContext = "expected-chain-context"
+	// CfgConnectTimeout configures how long to wait for the primary node when connecting.
+	CfgConnectTimeout = "connect-timeout"
+)
+
+var (
+	connFlags   = flag.NewFlagSet("", flag.ContinueOnError)
+	signerFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func loadRuntimeID() (common.Namespace, error) {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalHex(viper.GetString(CfgRuntimeID)); err != nil {
+		return runtimeID, fmt.Errorf("malformed runtime ID: %w", err)
+	}
+	return runtimeID, nil
+}
+
+func connect() (*bridge.Client, error) {
+	runtimeID, err := loadRuntimeID()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := loadNodeAddrs()
+	logger.Debug("establishing connection", "addrs", addrs)
+
+	ctx := context.Background()
+	if timeout := viper.GetDuration(CfgConnectTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return bridge.ConnectMulti(ctx, addrs, runtimeID,
+		bridge.WithConnectLogger(logger),
+		bridge.WithBridgeOptions(
+			bridge.WithRawEventLogging(viper.GetBool(CfgLogRawEvents)),
+			bridge.WithGetEventsTimeout(viper.GetDuration(CfgGetEventsTimeout)),
+		),
+		bridge.WithExpectedChainContext(signature.Context(viper.GetString(CfgExpectedChainContext))),
+	)
+}
+
+// loadNodeAddrs returns the configured node addresses, the first of which is the primary node.
+func loadNodeAddrs() []string {
+	var addrs []string
+	for _, addr := range strings.Split(viper.GetString(CfgNodeAddr), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func loadSigners() ([]signature.Signer, error) {
+	kr, err := loadKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	var signers []signature.Signer
+	for _, e := range kr.Entries() {
+		signers = append(signers, e.Signer)
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no keys configured")
+	}
+	return signers, nil
+}
+
+func loadKeyring() (bridge.Keyring, error) {
+	if source := viper.GetString(CfgKeyring); source != "" {
+		return bridge.LoadKeyring(source)
+	}
+	names := viper.GetStringSlice(CfgTestKey)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no keys configured")
+	}
+	return bridge.NewTestKeyring(names...)
+}
+
+func init() {
+	connFlags.String(CfgNodeAddr, os.Getenv("OASIS_NODE_GRPC_ADDR"), "gRPC address of the Oasis node (comma separated addresses to fail over between several nodes)")
+	connFlags.String(CfgRuntimeID, os.Getenv("BRIDGE_RUNTIME_ID"), "hex-encoded bridge runtime identifier")
+	connFlags.Bool(CfgLogRawEvents, false, "log the raw bytes of all fetched events at debug level")
+	connFlags.Duration(CfgGetEventsTimeout, 0, "timeout of each call fetching the events of a round (0 disables)")
+	connFlags.String(CfgExpectedChainContext, "", "chain context the nodes must have (not verified if empty)")
+	connFlags.Duration(CfgConnectTimeout, 30*time.Second, "how long to wait for the primary node when connecting (0 disables)")
+	_ = viper.BindPFlags(connFlags)
+
+	signerFlags.StringSlice(CfgTestKey, nil, "names of the test keys to sign with (alice, bob, charlie, dave)")
+	signerFlags.String(CfgKeyring, "", "source of the keys to sign with (test:NAMES, file:PATH or env:VAR), overrides test keys")
+	_ = viper.BindPFlags(signerFlags)
+}
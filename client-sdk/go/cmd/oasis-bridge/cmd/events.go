@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+// CfgEventsStartRound configures the round whose events are streamed first.
+const CfgEventsStartRound = "start-round"
+
+var (
+	eventsCmd = &cobra.Command{
+		Use:   "events",
+		Short: "stream bridge events to standard output as newline-delimited JSON",
+		RunE:  doEvents,
+	}
+
+	eventsFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doEvents(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	rc, err := connect()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var opts []bridge.WatchOption
+	if cmd.Flags().Changed(CfgEventsStartRound) {
+		opts = append(opts, bridge.WithStartRound(viper.GetUint64(CfgEventsStartRound)))
+	}
+	err = bridge.StreamEventsNDJSON(ctx, rc.Bridge, os.Stdout, opts...)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func init() {
+	eventsFlags.Uint64(CfgEventsStartRound, 0, "round to start streaming with (defaults to the rounds seen after connecting)")
+	_ = viper.BindPFlags(eventsFlags)
+
+	eventsCmd.Flags().AddFlagSet(connFlags)
+	eventsCmd.Flags().AddFlagSet(eventsFlags)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// CfgHealthAddr configures the address of the health check server.
+	CfgHealthAddr = "health-addr"
+	// CfgHealthMaxLag configures the maximum number of rounds the witness can lag behind the
+	// latest round while still being considered ready.
+	CfgHealthMaxLag = "health-max-lag"
+
+	healthCheckTimeout = 5 * time.Second
+)
+
+var healthFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+// healthStatus is the status reported by the health check server.
+type healthStatus struct {
+	// Connected is true iff the connection to the node is ready.
+	Connected bool `json:"connected"`
+	// Authorized is true iff all witness keys are authorized witnesses.
+	Authorized bool `json:"authorized"`
+	// LastProcessedRound is the last round processed by the witness.
+	LastProcessedRound *uint64 `json:"last_processed_round,omitempty"`
+	// LatestRound is the latest round of the runtime.
+	LatestRound *uint64 `json:"latest_round,omitempty"`
+	// Lag is the number of rounds the witness is lagging behind the latest round.
+	Lag *uint64 `json:"lag,omitempty"`
+}
+
+// healthServer is a HTTP server exposing liveness and readiness probes.
+type healthServer struct {
+	rc        client.RuntimeClient
+	bridge    bridge.V1
+	connected func() bool
+	processor *bridge.EventProcessor
+	pks       []signature.PublicKey
+	maxLag    uint64
+
+	srv    *http.Server
+	logger *logging.Logger
+}
+
+func (hs *healthServer) status(ctx context.Context) *healthStatus {
+	var status healthStatus
+	status.Connected = hs.connected()
+
+	status.Authorized = true
+	for _, pk := range hs.pks {
+		_, err := bridge.EnsureAuthorizedWitness(ctx, hs.bridge, pk)
+		if err == nil {
+			continue
+		}
+		status.Authorized = false
+		if !errors.Is(err, bridge.ErrNotAuthorizedWitness) {
+			hs.logger.Warn("failed to check witness authorization",
+				"err", err,
+			)
+		}
+		break
+	}
+
+	if round, ok := hs.processor.LastProcessedRound(); ok {
+		status.LastProcessedRound = &round
+	}
+	blk, err := hs.rc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		hs.logger.Warn("failed to fetch latest block",
+			"err", err,
+		)
+	} else {
+		status.LatestRound = &blk.Header.Round
+	}
+	if status.LastProcessedRound != nil && status.LatestRound != nil {
+		var lag uint64
+		if *status.LatestRound > *status.LastProcessedRound {
+			lag = *status.LatestRound - *status.LastProcessedRound
+		}
+		status.Lag = &lag
+	}
+	return &status
+}
+
+func (hs *healthServer) writeStatus(w http.ResponseWriter, status *healthStatus, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func (hs *healthServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	status := hs.status(ctx)
+	hs.writeStatus(w, status, status.Connected)
+}
+
+func (hs *healthServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	status := hs.status(ctx)
+	ready := status.Connected && status.Authorized && status.Lag != nil && *status.Lag <= hs.maxLag
+	hs.writeStatus(w, status, ready)
+}
+
+// Start starts serving health checks (and metrics) on the given address.
+func (hs *healthServer) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", hs.handleLiveness)
+	mux.HandleFunc("/readyz", hs.handleReadiness)
+	mux.Handle("/metrics", promhttp.Handler())
+	hs.srv = &http.Server{Handler: mux}
+
+	hs.logger.Info("starting health check server",
+		"addr", ln.Addr(),
+	)
+	go func() {
+		if err := hs.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			hs.logger.Error("health check server terminated",
+				"err", err,
+			)
+		}
+	}()
+	return nil
+}
+
+// Stop stops the health check server.
+func (hs *healthServer) Stop() {
+	_ = hs.srv.Close()
+}
+
+func newHealthServer(
+	rc *bridge.Client,
+	processor *bridge.EventProcessor,
+	pks []signature.PublicKey,
+	maxLag uint64,
+) *healthServer {
+	return &healthServer{
+		rc:        rc,
+		bridge:    rc.Bridge,
+		connected: rc.IsConnected,
+		processor: processor,
+		pks:       pks,
+		maxLag:    maxLag,
+		logger:    logging.GetLogger("cmd/health"),
+	}
+}
+
+func init() {
+	healthFlags.String(CfgHealthAddr, "", "address of the health check server (disabled if empty)")
+	healthFlags.Uint64(CfgHealthMaxLag, 10, "maximum number of rounds the witness may lag behind to be ready")
+	_ = viper.BindPFlags(healthFlags)
+}
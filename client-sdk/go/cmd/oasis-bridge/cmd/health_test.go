@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+// healthClient is a runtime client without events whose latest round and bridge parameters are
+// the configured ones.
+type healthClient struct {
+	client.RuntimeClient
+
+	latest uint64
+	params *bridge.Parameters
+}
+
+func (rc *healthClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = rc.latest
+	if round != client.RoundLatest {
+		blk.Header.Round = round
+	}
+	return &blk, nil
+}
+
+func (rc *healthClient) GetEvents(ctx context.Context, round uint64) ([]*coreClient.Event, error) {
+	return nil, nil
+}
+
+func (rc *healthClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	*rsp.(*bridge.Parameters) = *rc.params
+	return nil
+}
+
+func TestHealthServer(t *testing.T) {
+	params := &bridge.Parameters{
+		Witnesses: []types.PublicKey{{PublicKey: sdkTesting.Alice.Signer.Public()}},
+		Threshold: 1,
+	}
+
+	for _, tc := range []struct {
+		name      string
+		connected bool
+		witness   signature.PublicKey
+		processed bool
+		lag       uint64
+		live      bool
+		ready     bool
+	}{
+		{"Ready", true, sdkTesting.Alice.Signer.Public(), true, 0, true, true},
+		{"LagWithinLimit", true, sdkTesting.Alice.Signer.Public(), true, 2, true, true},
+		{"Lagging", true, sdkTesting.Alice.Signer.Public(), true, 3, true, false},
+		{"NotProcessed", true, sdkTesting.Alice.Signer.Public(), false, 0, true, false},
+		{"NotAuthorized", true, sdkTesting.Bob.Signer.Public(), true, 0, true, false},
+		{"Disconnected", false, sdkTesting.Alice.Signer.Public(), true, 0, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &healthClient{latest: 10, params: params}
+			processor := bridge.NewEventProcessor(rc, nil)
+			if tc.processed {
+				if _, err := processor.RunOnce(context.Background()); err != nil {
+					t.Fatalf("RunOnce: %s", err)
+				}
+			}
+			rc.latest += tc.lag
+
+			hs := &healthServer{
+				rc:        rc,
+				bridge:    bridge.NewV1(rc),
+				connected: func() bool { return tc.connected },
+				processor: processor,
+				pks:       []signature.PublicKey{tc.witness},
+				maxLag:    2,
+				logger:    logging.GetLogger("cmd/health"),
+			}
+
+			probe := func(handler http.HandlerFunc, expected bool) *healthStatus {
+				t.Helper()
+				w := httptest.NewRecorder()
+				handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+				expectedCode := http.StatusOK
+				if !expected {
+					expectedCode = http.StatusServiceUnavailable
+				}
+				if w.Code != expectedCode {
+					t.Fatalf("expected status code %d, got %d", expectedCode, w.Code)
+				}
+				if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+					t.Fatalf("expected JSON content type, got '%s'", ct)
+				}
+				var status healthStatus
+				if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+					t.Fatalf("malformed status '%s': %s", w.Body.String(), err)
+				}
+				return &status
+			}
+			probe(hs.handleLiveness, tc.live)
+			status := probe(hs.handleReadiness, tc.ready)
+
+			if status.Connected != tc.connected {
+				t.Fatalf("expected connected %t, got %t", tc.connected, status.Connected)
+			}
+			if authorized := tc.witness.Equal(sdkTesting.Alice.Signer.Public()); status.Authorized != authorized {
+				t.Fatalf("expected authorized %t, got %t", authorized, status.Authorized)
+			}
+			if status.LatestRound == nil || *status.LatestRound != rc.latest {
+				t.Fatalf("expected latest round %d, got %v", rc.latest, status.LatestRound)
+			}
+			if tc.processed {
+				if status.LastProcessedRound == nil || *status.LastProcessedRound != 10 {
+					t.Fatalf("expected last processed round 10, got %v", status.LastProcessedRound)
+				}
+				if status.Lag == nil || *status.Lag != tc.lag {
+					t.Fatalf("expected lag %d, got %v", tc.lag, status.Lag)
+				}
+			} else if status.LastProcessedRound != nil || status.Lag != nil {
+				t.Fatalf("expected no last processed round and lag, got %v and %v", status.LastProcessedRound, status.Lag)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// CfgInitConfigOutput configures the path the config skeleton is written to.
+	CfgInitConfigOutput = "output"
+	// CfgInitConfigForce configures whether an existing config file is overwritten.
+	CfgInitConfigForce = "force"
+)
+
+// configSkeleton is the witness config skeleton written by init-config. Its keys are the names of
+// the command line flags (see CfgConfigFile).
+const configSkeleton = `# Oasis bridge witness configuration.
+#
+# Pass this file to the witness with:
+#
+#   oasis-bridge witness --config oasis-bridge.yaml
+#
+# Every key corresponds to the command line flag of the same name, which takes
+# precedence over the value in this file.
+
+# Log level (debug, info, warn or error).
+log-level: info
+
+## Connection
+
+# gRPC address of the Oasis node, e.g. unix:/node/internal.sock. Additional
+# comma separated addresses are failed over to in case the first node is
+# unavailable.
+node-addr: "unix:/node/internal.sock"
+
+# Hex-encoded identifier of the bridge runtime.
+runtime-id: "0000000000000000000000000000000000000000000000000000000000000000"
+
+# Chain context the nodes must have, refusing to start when connected to a node
+# of a different network. Not verified if empty, which is not recommended.
+expected-chain-context: ""
+
+# How long to wait for the first node when connecting, e.g. 30s. Failover nodes
+# do not need to be reachable on startup. 0 disables the timeout.
+connect-timeout: 30s
+
+# Timeout of each call fetching the events of a round, e.g. 30s. Timed out calls
+# are retried. 0 disables the timeout.
+get-events-timeout: 30s
+
+## Keys
+
+# Source of the witness keys: file:PATH for a JSON file mapping key names to
+# base64-encoded Ed25519 private key seeds, or env:VAR for the same JSON object
+# in an environment variable. Keep the key file readable only by the witness.
+keyring: "file:/etc/oasis-bridge/witness-keys.json"
+
+## Witnessing
+
+# Path of the file used to persist witness progress so that a restarted witness
+# resumes where it left off. If empty, witnessing starts with the latest round.
+checkpoint: "/var/lib/oasis-bridge/witness.checkpoint"
+
+# Path of the file recording every signed operation for audits. Disabled if
+# empty.
+signing-history: "/var/lib/oasis-bridge/signing-history.jsonl"
+
+# Whether events are processed and signed without submitting transactions.
+dry-run: false
+
+# Maximum random delay before submitting witness transactions (at most 5s), so
+# that witnesses sharing infrastructure do not submit at the same instant.
+submit-jitter: 0s
+
+# Number of rounds whose bridge parameters are cached. 0 disables the cache.
+parameters-cache-size: 16
+
+# Interval at which the bridge parameters are queried to detect witness rotations
+# and threshold changes, which are applied without a restart.
+parameters-refresh-interval: 1m
+
+# How long to wait on shutdown for the round being processed to be finished and
+# checkpointed before aborting. 0 aborts immediately.
+drain-timeout: 30s
+
+# Whether the witness exits once it has processed all rounds up to the latest
+# round instead of tailing new rounds, e.g. when run periodically as a batch job.
+catch-up-only: false
+
+# The witness reprocesses rounds after reorganizations, so there is no
+# confirmation depth to configure.
+
+## Monitoring
+
+# Witness account balance (in base units) below which a warning is emitted.
+# Defaults to the estimated fee of a single witness transaction if empty.
+min-fee-balance: ""
+
+# URL notified about witnessed locks and observed releases. Disabled if empty.
+webhook-url: ""
+
+# Address of the health check server, which also serves Prometheus metrics under
+# /metrics. Disabled if empty.
+health-addr: "127.0.0.1:9100"
+
+# Maximum number of rounds the witness may lag behind the latest round while
+# still being reported as ready.
+health-max-lag: 10
+`
+
+var (
+	initConfigCmd = &cobra.Command{
+		Use:   "init-config",
+		Short: "write a commented witness config skeleton",
+		RunE:  doInitConfig,
+	}
+
+	initConfigFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doInitConfig(cmd *cobra.Command, args []string) error {
+	path := viper.GetString(CfgInitConfigOutput)
+	if path == "-" {
+		_, err := io.WriteString(os.Stdout, configSkeleton)
+		return err
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if viper.GetBool(CfgInitConfigForce) {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, mode, 0o600)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("config file %s already exists (pass --%s to overwrite it)", path, CfgInitConfigForce)
+		}
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+	if _, err = io.WriteString(f, configSkeleton); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	logger.Info("wrote config skeleton",
+		"path", path,
+	)
+	return nil
+}
+
+func init() {
+	initConfigFlags.String(CfgInitConfigOutput, "oasis-bridge.yaml", "path the config skeleton is written to (- for standard output)")
+	initConfigFlags.Bool(CfgInitConfigForce, false, "overwrite an existing config file")
+	_ = viper.BindPFlags(initConfigFlags)
+
+	initConfigCmd.Flags().AddFlagSet(initConfigFlags)
+}
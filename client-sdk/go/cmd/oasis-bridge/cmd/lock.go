@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// CfgLockTarget configures the hex-encoded remote address to lock funds for.
+	CfgLockTarget = "target"
+	// CfgLockAmount configures the amount to lock in base units.
+	CfgLockAmount = "amount"
+	// CfgLockDenomination configures the denomination of the amount to lock.
+	CfgLockDenomination = "denomination"
+	// CfgLockPrecheckBalance configures whether the signer's balance is checked before locking.
+	CfgLockPrecheckBalance = "precheck-balance"
+)
+
+var (
+	lockCmd = &cobra.Command{
+		Use:   "lock",
+		Short: "lock funds to be bridged to the remote chain",
+		RunE:  doLock,
+	}
+
+	lockFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doLock(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	signers, err := loadSigners()
+	if err != nil {
+		return err
+	}
+	if len(signers) != 1 {
+		return fmt.Errorf("exactly one key must be configured")
+	}
+
+	var target bridge.RemoteAddress
+	if err = target.UnmarshalText([]byte(viper.GetString(CfgLockTarget))); err != nil {
+		return fmt.Errorf("malformed target address: %w", err)
+	}
+	var amount quantity.Quantity
+	if err = amount.UnmarshalText([]byte(viper.GetString(CfgLockAmount))); err != nil {
+		return fmt.Errorf("malformed amount: %w", err)
+	}
+	denomination := types.Denomination(viper.GetString(CfgLockDenomination))
+
+	rc, err := connect()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Validate the lock before signing anything.
+	params, err := rc.Bridge.Parameters(ctx, client.RoundLatest)
+	if err != nil {
+		return fmt.Errorf("failed to query bridge parameters: %w", err)
+	}
+	body, err := bridge.NewLockBuilder().
+		Target(target).
+		Amount(amount).
+		Denomination(denomination).
+		Build(params, rc.Bridge.RemoteAddressCodec())
+	if err != nil {
+		return fmt.Errorf("invalid lock: %w", err)
+	}
+	if remote, expected, err := params.ExpectedRemoteAmount(*body); err == nil {
+		logger.Info("expected amount on the remote side",
+			"amount", expected,
+			"remote_denomination", remote,
+		)
+	}
+
+	var opts []bridge.SubmitOption
+	if viper.GetBool(CfgLockPrecheckBalance) {
+		opts = append(opts, bridge.WithBalancePrecheck())
+	}
+	result, err := rc.Bridge.SubmitLock(ctx, signers[0], body, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+	logger.Info("lock submitted",
+		"id", result.ID,
+	)
+	return nil
+}
+
+func init() {
+	lockFlags.String(CfgLockTarget, "", "hex-encoded remote address to lock funds for")
+	lockFlags.String(CfgLockAmount, "0", "amount to lock in base units")
+	lockFlags.String(CfgLockDenomination, "", "denomination of the amount to lock (empty for native)")
+	lockFlags.Bool(CfgLockPrecheckBalance, true, "check that the signer holds the amount before locking")
+	_ = viper.BindPFlags(lockFlags)
+
+	lockCmd.Flags().AddFlagSet(connFlags)
+	lockCmd.Flags().AddFlagSet(signerFlags)
+	lockCmd.Flags().AddFlagSet(lockFlags)
+}
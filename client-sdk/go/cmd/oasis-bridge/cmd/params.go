@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// CfgParamsRound configures the round at which the bridge parameters are queried.
+const CfgParamsRound = "round"
+
+var (
+	paramsCmd = &cobra.Command{
+		Use:   "params",
+		Short: "show the bridge parameters including the addresses of the authorized witnesses",
+		RunE:  doParams,
+	}
+
+	paramsFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doParams(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	rc, err := connect()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	round := client.RoundLatest
+	if cmd.Flags().Changed(CfgParamsRound) {
+		round = viper.GetUint64(CfgParamsRound)
+	}
+	params, err := rc.Bridge.Parameters(ctx, round)
+	if err != nil {
+		return fmt.Errorf("failed to query parameters: %w", err)
+	}
+
+	fmt.Printf("threshold %d\n", params.Threshold)
+	fmt.Printf("%d witness(es)\n", len(params.Witnesses))
+	for _, w := range params.AuthorizedWitnesses() {
+		fmt.Printf("  witness %d: %s (address %s)\n", w.Index, w.PublicKey, w.Address)
+	}
+	for _, local := range params.LocalDenominations {
+		fmt.Printf("local denomination %s\n", local)
+	}
+	for _, m := range params.SortedRemoteDenominations() {
+		fmt.Printf("remote denomination %s -> %s\n", m.Local, m.Remote)
+	}
+	return nil
+}
+
+func init() {
+	paramsFlags.Uint64(CfgParamsRound, 0, "round at which to query the parameters (defaults to the latest round)")
+	_ = viper.BindPFlags(paramsFlags)
+
+	paramsCmd.Flags().AddFlagSet(connFlags)
+	paramsCmd.Flags().AddFlagSet(paramsFlags)
+}
@@ -0,0 +1,85 @@
+// Package cmd implements the commands for the oasis-bridge executable.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// CfgLogLevel configures the log level.
+	CfgLogLevel = "log-level"
+	// CfgConfigFile configures the path of a YAML config file whose keys are flag names.
+	CfgConfigFile = "config"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "oasis-bridge",
+		Short: "Oasis bridge client",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadConfigFile(); err != nil {
+				return err
+			}
+			return initLogging()
+		},
+		SilenceUsage: true,
+	}
+
+	rootFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logLevel = logging.LevelInfo
+
+	logger = logging.GetLogger("cmd")
+)
+
+// loadConfigFile loads the configured config file, if any. Flags that are set explicitly take
+// precedence over its values.
+func loadConfigFile() error {
+	path := viper.GetString(CfgConfigFile)
+	if path == "" {
+		return nil
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to load config file: %w", err)
+	}
+	return nil
+}
+
+func initLogging() error {
+	if err := logLevel.Set(viper.GetString(CfgLogLevel)); err != nil {
+		return fmt.Errorf("malformed log level: %w", err)
+	}
+	if err := logging.Initialize(os.Stdout, logging.FmtLogfmt, logLevel, nil); err != nil {
+		return fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	return nil
+}
+
+// Execute spawns the main entry point after handling the command line arguments.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootFlags.Var(&logLevel, CfgLogLevel, "log level")
+	rootFlags.String(CfgConfigFile, "", "path of a YAML config file (see init-config)")
+	_ = viper.BindPFlags(rootFlags)
+	rootCmd.PersistentFlags().AddFlagSet(rootFlags)
+
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(initConfigCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(paramsCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(witnessCmd)
+}
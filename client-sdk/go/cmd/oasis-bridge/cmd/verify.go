@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// CfgVerifyBundle configures the path of the relay bundle to verify.
+	CfgVerifyBundle = "bundle"
+	// CfgVerifyParameters configures the path of the JSON-encoded bridge parameters to verify
+	// against.
+	CfgVerifyParameters = "parameters"
+	// CfgVerifyChainContext configures the chain context of the bridge runtime.
+	CfgVerifyChainContext = "chain-context"
+	// CfgVerifyEIP712Domain configures the path of the JSON-encoded EIP-712 domain under which
+	// witnesses with secp256k1 keys sign.
+	CfgVerifyEIP712Domain = "eip712-domain"
+)
+
+var (
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify the witness signatures of a relay bundle without submitting anything",
+		RunE:  doVerify,
+	}
+
+	verifyFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doVerify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	data, err := os.ReadFile(viper.GetString(CfgVerifyBundle))
+	if err != nil {
+		return fmt.Errorf("failed to read relay bundle: %w", err)
+	}
+	var ev bridge.WitnessesSignedEvent
+	if err = ev.UnmarshalRelayBundle(data); err != nil {
+		return err
+	}
+
+	var params *bridge.Parameters
+	if path := viper.GetString(CfgVerifyParameters); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read parameters: %w", err)
+		}
+		params = new(bridge.Parameters)
+		if err = json.Unmarshal(raw, params); err != nil {
+			return fmt.Errorf("malformed parameters: %w", err)
+		}
+	}
+	chainContext := signature.Context(viper.GetString(CfgVerifyChainContext))
+
+	var opts []bridge.VerifyOption
+	if path := viper.GetString(CfgVerifyEIP712Domain); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read EIP-712 domain: %w", err)
+		}
+		var domain bridge.EIP712Domain
+		if err = json.Unmarshal(raw, &domain); err != nil {
+			return fmt.Errorf("malformed EIP-712 domain: %w", err)
+		}
+		if err = domain.Validate(); err != nil {
+			return err
+		}
+		opts = append(opts, bridge.WithEIP712Domain(&domain))
+	}
+
+	// Fetch whatever has not been provided from the node.
+	if params == nil || chainContext == "" {
+		rc, err := connect()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		if params == nil {
+			if params, err = rc.Bridge.Parameters(ctx, client.RoundLatest); err != nil {
+				return fmt.Errorf("failed to query parameters: %w", err)
+			}
+		}
+		if chainContext == "" {
+			info, err := rc.GetInfo(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to fetch runtime info: %w", err)
+			}
+			chainContext = info.ChainContext
+		}
+	}
+
+	v := bridge.VerifyWitnessSignatures(chainContext, params, &ev, opts...)
+	fmt.Printf("%s %d\n", ev.Kind(), ev.ID)
+	for _, sig := range v.Signatures {
+		status := "valid"
+		if !sig.Valid {
+			status = "invalid: " + sig.Reason
+		}
+		pk := "-"
+		if sig.PublicKey != nil {
+			pk = sig.PublicKey.String()
+		}
+		fmt.Printf("  witness %d (%s): %s\n", sig.Index, pk, status)
+	}
+	fmt.Printf("%d valid signature(s), threshold %d\n", v.Valid, v.Threshold)
+
+	if !v.MeetsThreshold() {
+		return fmt.Errorf("relay bundle does not meet the threshold")
+	}
+	return nil
+}
+
+func init() {
+	verifyFlags.String(CfgVerifyBundle, "", "path of the relay bundle to verify")
+	verifyFlags.String(CfgVerifyParameters, "", "path of the JSON-encoded bridge parameters (fetched from the node if empty)")
+	verifyFlags.String(CfgVerifyChainContext, "", "chain context of the bridge runtime (fetched from the node if empty)")
+	verifyFlags.String(CfgVerifyEIP712Domain, "", "path of the JSON-encoded EIP-712 domain of witnesses with secp256k1 keys")
+	_ = viper.BindPFlags(verifyFlags)
+
+	verifyCmd.Flags().AddFlagSet(connFlags)
+	verifyCmd.Flags().AddFlagSet(verifyFlags)
+}
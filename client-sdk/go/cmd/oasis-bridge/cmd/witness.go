@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
+)
+
+const (
+	// CfgCheckpoint configures the path of the file used to persist witness progress.
+	CfgCheckpoint = "checkpoint"
+	// CfgDryRun configures whether the witness runs in dry-run mode.
+	CfgDryRun = "dry-run"
+	// CfgSigningHistory configures the path of the file used to record signed operations.
+	CfgSigningHistory = "signing-history"
+	// CfgMinFeeBalance configures the witness account balance below which a warning is emitted.
+	CfgMinFeeBalance = "min-fee-balance"
+	// CfgWebhookURL configures the URL notified about witnessed locks and observed releases.
+	CfgWebhookURL = "webhook-url"
+	// CfgSubmitJitter configures the maximum random delay before submitting witness transactions.
+	CfgSubmitJitter = "submit-jitter"
+	// CfgParametersCacheSize configures the number of rounds whose bridge parameters are cached.
+	CfgParametersCacheSize = "parameters-cache-size"
+	// CfgParametersRefreshInterval configures the interval at which the bridge parameters are
+	// queried to detect changes.
+	CfgParametersRefreshInterval = "parameters-refresh-interval"
+	// CfgDrainTimeout configures how long the witness waits for the round being processed to be
+	// finished on shutdown.
+	CfgDrainTimeout = "drain-timeout"
+	// CfgCatchUpOnly configures whether the witness exits once it has processed all rounds up to
+	// the latest round.
+	CfgCatchUpOnly = "catch-up-only"
+)
+
+var (
+	witnessCmd = &cobra.Command{
+		Use:   "witness",
+		Short: "run a bridge witness",
+		RunE:  doWitness,
+	}
+
+	witnessFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func doWitness(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	signers, err := loadSigners()
+	if err != nil {
+		return err
+	}
+	runtimeID, err := loadRuntimeID()
+	if err != nil {
+		return err
+	}
+
+	addrs := loadNodeAddrs()
+	if len(addrs) == 0 {
+		return bridge.ErrNoNodeAddresses
+	}
+	cfg := bridge.Config{
+		NodeAddress:               addrs[0],
+		FailoverAddresses:         addrs[1:],
+		RuntimeID:                 runtimeID,
+		ExpectedChainContext:      signature.Context(viper.GetString(CfgExpectedChainContext)),
+		ConnectTimeout:            viper.GetDuration(CfgConnectTimeout),
+		Signers:                   signers,
+		CheckpointPath:            viper.GetString(CfgCheckpoint),
+		SigningHistoryPath:        viper.GetString(CfgSigningHistory),
+		DryRun:                    viper.GetBool(CfgDryRun),
+		SubmitJitter:              viper.GetDuration(CfgSubmitJitter),
+		RawEventLogging:           viper.GetBool(CfgLogRawEvents),
+		GetEventsTimeout:          viper.GetDuration(CfgGetEventsTimeout),
+		ParametersCacheSize:       viper.GetInt(CfgParametersCacheSize),
+		ParametersRefreshInterval: viper.GetDuration(CfgParametersRefreshInterval),
+		DrainTimeout:              viper.GetDuration(CfgDrainTimeout),
+		CatchUpOnly:               viper.GetBool(CfgCatchUpOnly),
+	}
+	if minBalance := viper.GetString(CfgMinFeeBalance); minBalance != "" {
+		var q quantity.Quantity
+		if err = q.UnmarshalText([]byte(minBalance)); err != nil {
+			return fmt.Errorf("malformed minimum fee balance: %w", err)
+		}
+		cfg.MinFeeBalance = &q
+	}
+
+	var onStart []func(ctx context.Context, rc *bridge.Client, processor *bridge.EventProcessor) error
+	if addr := viper.GetString(CfgHealthAddr); addr != "" {
+		var hs *healthServer
+		defer func() {
+			if hs != nil {
+				hs.Stop()
+			}
+		}()
+		onStart = append(onStart, func(ctx context.Context, rc *bridge.Client, processor *bridge.EventProcessor) error {
+			var pks []signature.PublicKey
+			for _, signer := range signers {
+				pks = append(pks, signer.Public())
+			}
+			srv := newHealthServer(rc, processor, pks, viper.GetUint64(CfgHealthMaxLag))
+			if err := srv.Start(addr); err != nil {
+				return err
+			}
+			hs = srv
+			return nil
+		})
+	}
+	if url := viper.GetString(CfgWebhookURL); url != "" {
+		notifier := bridge.NewWebhookNotifier(url, bridge.WithWebhookLogger(logger))
+		defer notifier.Close()
+
+		cfg.ProcessorOptions = append(cfg.ProcessorOptions, bridge.WithOnWitnessed(notifier.OnWitnessed))
+		onStart = append(onStart, func(ctx context.Context, rc *bridge.Client, _ *bridge.EventProcessor) error {
+			go func() {
+				if err := notifier.WatchReleases(ctx, rc.Bridge); err != nil && !errors.Is(err, context.Canceled) {
+					logger.Error("stopped notifying webhook about releases",
+						"err", err,
+					)
+				}
+			}()
+			return nil
+		})
+	}
+	cfg.OnStart = func(ctx context.Context, rc *bridge.Client, processor *bridge.EventProcessor) error {
+		for _, fn := range onStart {
+			if err := fn(ctx, rc, processor); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return bridge.RunWitness(ctx, cfg)
+}
+
+func init() {
+	witnessFlags.String(CfgCheckpoint, "", "path of the file used to persist witness progress")
+	witnessFlags.String(CfgSigningHistory, "", "path of the file used to record signed operations")
+	witnessFlags.Bool(CfgDryRun, false, "process and sign events without submitting witness transactions")
+	witnessFlags.String(CfgMinFeeBalance, "", "witness account balance (in base units) below which a warning is emitted")
+	witnessFlags.String(CfgWebhookURL, "", "URL notified about witnessed locks and observed releases (disabled if empty)")
+	witnessFlags.Duration(CfgSubmitJitter, 0, "maximum random delay before submitting witness transactions")
+	witnessFlags.Int(CfgParametersCacheSize, 16, "number of rounds whose bridge parameters are cached (0 disables)")
+	witnessFlags.Duration(CfgParametersRefreshInterval, bridge.DefaultParametersRefreshInterval, "interval at which the bridge parameters are queried to detect changes")
+	witnessFlags.Duration(CfgDrainTimeout, 30*time.Second, "how long to wait for the round being processed to be finished on shutdown (0 aborts immediately)")
+	witnessFlags.Bool(CfgCatchUpOnly, false, "exit once all rounds up to the latest round have been processed instead of tailing new rounds")
+	_ = viper.BindPFlags(witnessFlags)
+
+	witnessCmd.Flags().AddFlagSet(connFlags)
+	witnessCmd.Flags().AddFlagSet(signerFlags)
+	witnessCmd.Flags().AddFlagSet(witnessFlags)
+	witnessCmd.Flags().AddFlagSet(healthFlags)
+}
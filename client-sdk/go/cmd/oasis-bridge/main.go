@@ -0,0 +1,10 @@
+// Oasis bridge command line utility.
+package main
+
+import (
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/cmd/oasis-bridge/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
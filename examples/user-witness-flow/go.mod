@@ -3,7 +3,10 @@ module github.com/oasisprotocol/oasis-bridge/examples/user-witness-flow
 go 1.16
 
 require (
+	github.com/oasisprotocol/oasis-bridge/client-sdk/go v0.0.0-00010101000000-000000000000
 	github.com/oasisprotocol/oasis-core/go v0.2102.1
 	github.com/oasisprotocol/oasis-sdk/client-sdk/go v0.0.0-20210610110548-e22c8bcf9e88
 	google.golang.org/grpc v1.38.0
 )
+
+replace github.com/oasisprotocol/oasis-bridge/client-sdk/go => ../../client-sdk/go
@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"encoding/base64"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -11,17 +11,17 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
-	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
-	sdk "github.com/oasisprotocol/oasis-sdk/client-sdk/go"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+
+	"github.com/oasisprotocol/oasis-bridge/client-sdk/go/bridge"
 )
 
 var logger = logging.GetLogger("user-witness-flow")
@@ -34,6 +34,18 @@ const GrpcAddrEnvVar = "OASIS_NODE_GRPC_ADDR"
 // runtime identifier of the bridge runtime.
 const RuntimeIDEnvVar = "BRIDGE_RUNTIME_ID"
 
+// LogRawEventsEnvVar is the name of the environment variable that enables
+// logging the raw key and value of every event at debug level.
+const LogRawEventsEnvVar = "BRIDGE_LOG_RAW_EVENTS"
+
+// ChainContextEnvVar is the name of the environment variable that specifies
+// the chain context the node is expected to have. If set, the example refuses
+// to run against nodes of any other network.
+const ChainContextEnvVar = "BRIDGE_CHAIN_CONTEXT"
+
+// logRawEvents configures whether the raw bytes of events are logged.
+var logRawEvents bool
+
 // Return the value of the given environment variable or exit if it is
 // empty (or unset).
 func getEnvVarOrExit(name string) string {
@@ -47,128 +59,34 @@ func getEnvVarOrExit(name string) string {
 	return value
 }
 
-// TODO: Move these somewhere bridge-module specific.
-
-const remoteAddressSize = 20
-
-// RemoteAddress is a remote address.
-type RemoteAddress [remoteAddressSize]byte
-
-// String returns a string representation of the remote address.
-func (ra RemoteAddress) String() string {
-	return hex.EncodeToString(ra[:])
-}
-
-// NewRemoteAddressFromHex creates a new remote address from a hex-encoded string or panics.
-func NewRemoteAddressFromHex(text string) RemoteAddress {
-	b, err := hex.DecodeString(text)
-	if err != nil {
-		panic(err)
-	}
-	if len(b) != remoteAddressSize {
-		panic(fmt.Errorf("malformed address"))
-	}
-	var ra RemoteAddress
-	copy(ra[:], b)
-	return ra
-}
-
-// Lock is the body of the Lock call.
-type Lock struct {
-	Target RemoteAddress   `json:"target"`
-	Amount types.BaseUnits `json:"amount"`
-}
-
-// LockResult is the result of a Lock method call.
-type LockResult struct {
-	ID uint64 `json:"id"`
-}
-
-// Witness is the body of a Witness call.
-type Witness struct {
-	ID        uint64 `json:"id"`
-	Signature []byte `json:"sig"`
-}
-
-// Release is the body of a Release call.
-type Release struct {
-	ID     uint64          `json:"id"`
-	Target types.Address   `json:"target"`
-	Amount types.BaseUnits `json:"amount"`
-}
-
-// LockEvent is a lock event.
-type LockEvent struct {
-	ID     uint64          `json:"id"`
-	Owner  types.Address   `json:"owner"`
-	Target RemoteAddress   `json:"target"`
-	Amount types.BaseUnits `json:"amount"`
-}
-
-// LockEventKey is the key used for lock events.
-var LockEventKey = sdk.NewEventKey("bridge", 1)
-
-// ReleaseEvent is the release event.
-type ReleaseEvent struct {
-	ID     uint64          `json:"id"`
-	Target types.Address   `json:"target"`
-	Amount types.BaseUnits `json:"amount"`
-}
-
-// ReleaseEventKey is the key used for release events.
-var ReleaseEventKey = sdk.NewEventKey("bridge", 2)
-
-// Operation is a bridge operation.
-type Operation struct {
-	Lock    *Lock    `json:"lock,omitempty"`
-	Release *Release `json:"release,omitempty"`
-}
-
-// WitnessesSignedEvent is the witnesses signed event.
-type WitnessesSignedEvent struct {
-	ID         uint64    `json:"id"`
-	Op         Operation `json:"op"`
-	Witnesses  []uint16  `json:"wits,omitempty"`
-	Signatures [][]byte  `json:"sigs,omitempty"`
-}
-
-// WitnessesSignedEventKey is the key used for witnesses signed events.
-var WitnessesSignedEventKey = sdk.NewEventKey("bridge", 3)
-
-// NextSequenceNumbers are the next sequence numbers.
-type NextSequenceNumbers struct {
-	Incoming uint64 `json:"in"`
-	Outgoing uint64 `json:"out"`
-}
-
-// RemoteDenomination is a remote denomination.
-type RemoteDenomination []byte
-
-// String returns a string representation of a remote denomination.
-func (rd RemoteDenomination) String() string {
-	return hex.EncodeToString([]byte(rd))
-}
-
-// Parameters are the bridge module parameters.
-type Parameters struct {
-	// Witnesses is a list of authorized witness public keys.
-	Witnesses []types.PublicKey `json:"witnesses"`
-
-	// Threshold is the number of witnesses that needs to sign off.
-	Threshold uint64 `json:"threshold"`
-
-	// LocalDenominations are the denominations local to this side of the bridge.
-	LocalDenominations []types.Denomination `json:"local_denominations"`
-
-	// RemoteDenominations are the denominations that exist on the remote side of the bridge.
-	RemoteDenominations map[types.Denomination]RemoteDenomination `json:"remote_denominations"`
-}
-
 // Client is a bridge runtime client.
 type Client struct {
 	client.RuntimeClient
 
 	Accounts accounts.V1
+	Bridge   bridge.V1
+}
+
+// submitLocks submits the given locks in order and returns the lock results together with the
+// errors encountered for each of the locks. For each lock exactly one of the result and the error
+// is non-nil.
+func submitLocks(
+	ctx context.Context,
+	rc *Client,
+	signer signature.Signer,
+	locks []*bridge.Lock,
+) ([]*bridge.LockResult, []error) {
+	results := make([]*bridge.LockResult, len(locks))
+	errs := make([]error, len(locks))
+	for i, lock := range locks {
+		logger.Info("submitting lock transaction",
+			"side", "user",
+			"target", lock.Target,
+			"amount", lock.Amount,
+		)
+		results[i], errs[i] = rc.Bridge.SubmitLock(ctx, signer, lock)
+	}
+	return results, errs
 }
 
 // user is an example user flow.
@@ -176,8 +94,8 @@ func user(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	rc *Client,
-	chainContext signature.Context,
 	signer signature.Signer,
+	locks []*bridge.Lock,
 ) {
 	logger := logger.With("side", "user")
 
@@ -187,7 +105,7 @@ func user(
 	}()
 
 	// Subscribe to blocks.
-	blkCh, blkSub, err := rc.WatchBlocks(ctx)
+	blkCh, blkSub, err := rc.Bridge.WatchBlocks(ctx)
 	if err != nil {
 		logger.Error("failed to subscribe to runtime blocks",
 			"err", err,
@@ -196,54 +114,50 @@ func user(
 	}
 	defer blkSub.Close()
 
-	// Get nonce.
-	nonce, err := rc.Accounts.Nonce(ctx, client.RoundLatest, types.NewAddress(signer.Public()))
-	if err != nil {
-		logger.Error("failed to fetch account nonce",
-			"err", err,
-		)
-		return
-	}
-
-	// Submit Lock.
-	logger.Info("submitting lock transaction")
-	tx := types.NewTransaction(nil, "bridge.Lock", Lock{
-		Target: NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
-		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
-	})
-	tx.AppendAuthSignature(signer.Public(), nonce)
-	tb := tx.PrepareForSigning()
-	if err = tb.AppendSign(chainContext, signer); err != nil {
-		logger.Error("failed to sign lock transaction",
-			"err", err,
-		)
-		return
-	}
-	raw, err := rc.SubmitTx(ctx, tb.UnverifiedTransaction())
-	if err != nil {
-		logger.Error("failed to submit lock transaction",
-			"err", err,
-		)
-		return
+	// Submit Locks.
+	results, errs := submitLocks(ctx, rc, signer, locks)
+	pending := make(map[uint64]struct{})
+	for i, result := range results {
+		if errs[i] != nil {
+			logger.Error("failed to submit lock transaction",
+				"err", errs[i],
+				"target", locks[i].Target,
+				"amount", locks[i].Amount,
+			)
+			continue
+		}
+		pending[result.ID] = struct{}{}
 	}
 
-	// Deserialize call result and extract id.
-	var lockResult LockResult
-	if err = cbor.Unmarshal(raw, &lockResult); err != nil {
-		logger.Error("failed to unmarshal lock result",
-			"err", err,
-		)
-		return
+	// Locks may have been processed before their events are seen (e.g., in case of fast
+	// witnesses), so avoid waiting for locks that have already been released.
+	for id := range pending {
+		released, err := rc.Bridge.IsLockReleased(ctx, client.RoundLatest, id)
+		if err != nil {
+			logger.Error("failed to check whether lock has been released",
+				"err", err,
+				"id", id,
+			)
+			continue
+		}
+		if released {
+			logger.Info("lock already released",
+				"id", id,
+			)
+			delete(pending, id)
+		}
 	}
-	lockID := lockResult.ID
 
-	// Wait for a WitnessesSigned event.
-	for {
+	// Wait for a WitnessesSigned event for each of the submitted locks.
+	for len(pending) > 0 {
 		select {
 		case <-ctx.Done():
 			return
 		case blk, ok := <-blkCh:
 			if !ok {
+				logger.Error("block subscription terminated",
+					"err", blkSub.Err(),
+				)
 				return
 			}
 			logger.Debug("seen new block",
@@ -260,33 +174,38 @@ func user(
 			}
 
 			for _, ev := range events {
-				// TODO: Have wrappers for converting events.
-				logger.Debug("got event",
-					"key", base64.StdEncoding.EncodeToString(ev.Key),
-					"value", base64.StdEncoding.EncodeToString(ev.Value),
-				)
+				if logRawEvents {
+					logger.Debug("got event",
+						"key", base64.StdEncoding.EncodeToString(ev.Key),
+						"value", base64.StdEncoding.EncodeToString(ev.Value),
+					)
+				}
+
+				bev, err := bridge.DecodeEvent(ev)
+				if err != nil {
+					logger.Error("failed to decode event",
+						"err", err,
+						"value", base64.StdEncoding.EncodeToString(ev.Value),
+					)
+					continue
+				}
 
 				switch {
-				case WitnessesSignedEventKey.IsEqual(ev.Key):
-					var witnessEv WitnessesSignedEvent
-					if err = cbor.Unmarshal(ev.Value, &witnessEv); err != nil {
-						logger.Error("failed to unmarshal witnesses signed event",
-							"err", err,
-						)
-						continue
-					}
+				case bev != nil && bev.WitnessesSigned != nil:
+					witnessEv := bev.WitnessesSigned
 
 					logger.Debug("got witnesses signed event",
 						"id", witnessEv.ID,
 					)
 
-					if witnessEv.ID == lockID {
-						// Our lock has been witnessed.
+					if _, ok := pending[witnessEv.ID]; witnessEv.IsLock() && ok {
+						// One of our locks has been witnessed.
 						// TODO: Take the signatures and submit to the other side.
 						logger.Info("got witness signatures",
+							"id", witnessEv.ID,
 							"sigs", witnessEv.Signatures,
 						)
-						return
+						delete(pending, witnessEv.ID)
 					}
 				default:
 				}
@@ -312,9 +231,7 @@ func showBalances(ctx context.Context, rc *Client, address types.Address) {
 }
 
 func showParameters(ctx context.Context, rc *Client) {
-	// TODO: Move these to bridge-specific helpers.
-	var params Parameters
-	err := rc.Query(ctx, client.RoundLatest, "bridge.Parameters", nil, &params)
+	params, err := rc.Bridge.Parameters(ctx, client.RoundLatest)
 	if err != nil {
 		logger.Error("failed to query bridge parameters",
 			"err", err,
@@ -333,8 +250,8 @@ func showParameters(ctx context.Context, rc *Client) {
 		fmt.Printf("  - %s\n", d)
 	}
 	fmt.Printf("Remote denominations:\n")
-	for local, remote := range params.RemoteDenominations {
-		fmt.Printf("  - %s (%s)\n", local, remote)
+	for _, m := range params.SortedRemoteDenominations() {
+		fmt.Printf("  - %s (%s)\n", m.Local, m.Remote)
 	}
 }
 
@@ -342,10 +259,11 @@ func showParameters(ctx context.Context, rc *Client) {
 func witness(
 	ctx context.Context,
 	wg *sync.WaitGroup,
-	releaseWg *sync.WaitGroup,
+	barrier *bridge.ReleaseBarrier,
 	rc *Client,
 	chainContext signature.Context,
 	signer signature.Signer,
+	numLocks int,
 ) {
 	logger := logger.With("side", "witness")
 
@@ -355,7 +273,7 @@ func witness(
 	}()
 
 	// Subscribe to blocks.
-	blkCh, blkSub, err := rc.WatchBlocks(ctx)
+	blkCh, blkSub, err := rc.Bridge.WatchBlocks(ctx)
 	if err != nil {
 		logger.Error("failed to subscribe to runtime blocks",
 			"err", err,
@@ -364,16 +282,23 @@ func witness(
 	}
 	defer blkSub.Close()
 
-	var lastUser types.Address
+	var (
+		lastUser  types.Address
+		witnessed int
+	)
+	witnessSigner := bridge.NewWitnessSigner(signer, chainContext)
 
 	// TODO: Logic for persisting at which block we left off and back-processing any missed events.
-WitnessAnEvent:
+WitnessLocks:
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case blk, ok := <-blkCh:
 			if !ok {
+				logger.Error("block subscription terminated",
+					"err", blkSub.Err(),
+				)
 				return
 			}
 			logger.Debug("seen new block",
@@ -390,23 +315,27 @@ WitnessAnEvent:
 			}
 
 			// Collect lock events.
-			var lockEvents []*LockEvent
+			var lockEvents []*bridge.LockEvent
 			for _, ev := range events {
-				// TODO: Have wrappers for converting events.
-				logger.Debug("got event",
-					"key", base64.StdEncoding.EncodeToString(ev.Key),
-					"value", base64.StdEncoding.EncodeToString(ev.Value),
-				)
+				if logRawEvents {
+					logger.Debug("got event",
+						"key", base64.StdEncoding.EncodeToString(ev.Key),
+						"value", base64.StdEncoding.EncodeToString(ev.Value),
+					)
+				}
+
+				bev, err := bridge.DecodeEvent(ev)
+				if err != nil {
+					logger.Error("failed to decode event",
+						"err", err,
+						"value", base64.StdEncoding.EncodeToString(ev.Value),
+					)
+					continue
+				}
 
 				switch {
-				case LockEventKey.IsEqual(ev.Key):
-					var lockEv LockEvent
-					if err = cbor.Unmarshal(ev.Value, &lockEv); err != nil {
-						logger.Error("failed to unmarshal lock event",
-							"err", err,
-						)
-						continue
-					}
+				case bev != nil && bev.Lock != nil:
+					lockEv := bev.Lock
 
 					logger.Debug("got lock event",
 						"id", lockEv.ID,
@@ -415,7 +344,7 @@ WitnessAnEvent:
 						"amount", lockEv.Amount,
 					)
 
-					lockEvents = append(lockEvents, &lockEv)
+					lockEvents = append(lockEvents, lockEv)
 				default:
 				}
 			}
@@ -426,15 +355,25 @@ WitnessAnEvent:
 
 			// Submit bridge.Witness transactions.
 			for _, ev := range lockEvents {
-				// TODO: Sign the event using witness key.
-				evSignature := []byte("signature:" + signer.Public().String())
+				op := bridge.NewLockOperation(bridge.Lock{
+					Target: ev.Target,
+					Amount: ev.Amount,
+				})
+				sig, err := witnessSigner.SignOperation(blk.Block.Header.Round, ev.ID, &op)
+				if err != nil {
+					logger.Error("failed to sign lock",
+						"err", err,
+						"id", ev.ID,
+					)
+					return
+				}
 
 				logger.Info("submitting witness transaction",
 					"id", ev.ID,
 				)
 
 				// Get nonce.
-				nonce, err := rc.Accounts.Nonce(ctx, client.RoundLatest, types.NewAddress(signer.Public()))
+				nonce, err := rc.Accounts.Nonce(ctx, client.RoundLatest, bridge.AddressOf(signer))
 				if err != nil {
 					logger.Error("failed to fetch account nonce",
 						"err", err,
@@ -442,9 +381,9 @@ WitnessAnEvent:
 					return
 				}
 
-				tx := types.NewTransaction(nil, "bridge.Witness", Witness{
+				tx := types.NewTransaction(nil, "bridge.Witness", bridge.Witness{
 					ID:        ev.ID,
-					Signature: evSignature,
+					Signature: bridge.EncodeWitnessSignature(sig),
 				})
 				tx.AppendAuthSignature(signer.Public(), nonce)
 				tb := tx.PrepareForSigning()
@@ -454,21 +393,34 @@ WitnessAnEvent:
 					)
 					return
 				}
-				_, err = rc.SubmitTx(ctx, tb.UnverifiedTransaction())
-				if err != nil {
+				receipt, err := bridge.SubmitWithReceipt(ctx, rc, tb.UnverifiedTransaction())
+				switch {
+				case errors.Is(err, bridge.ErrReceiptNotFound):
+					// Executed nonetheless, so it must not be submitted again.
+					logger.Warn("failed to determine round of witness transaction",
+						"err", err,
+					)
+				case err != nil:
 					logger.Error("failed to submit witness transaction",
 						"err", err,
 					)
 					return
 				}
+				logger.Debug("witness transaction executed",
+					"round", receipt.Round,
+					"tx_hash", receipt.TxHash,
+				)
 
 				lastUser = ev.Owner
+				witnessed++
 			}
 
 			logger.Info("successfully witnessed events")
 
-			// We only witness a single event.
-			break WitnessAnEvent
+			// We only witness the locks made by the user.
+			if witnessed >= numLocks {
+				break WitnessLocks
+			}
 		}
 	}
 
@@ -476,7 +428,7 @@ WitnessAnEvent:
 	logger.Info("simulating release")
 
 	// Get nonce.
-	nonce, err := rc.Accounts.Nonce(ctx, client.RoundLatest, types.NewAddress(signer.Public()))
+	nonce, err := rc.Accounts.Nonce(ctx, client.RoundLatest, bridge.AddressOf(signer))
 	if err != nil {
 		logger.Error("failed to fetch account nonce",
 			"err", err,
@@ -485,9 +437,7 @@ WitnessAnEvent:
 	}
 
 	// Get remote sequence number.
-	// TODO: Move these to bridge-specific helpers.
-	var sequences NextSequenceNumbers
-	err = rc.Query(ctx, client.RoundLatest, "bridge.NextSequenceNumbers", nil, &sequences)
+	sequences, err := rc.Bridge.NextSequenceNumbers(ctx, client.RoundLatest)
 	if err != nil {
 		logger.Error("failed to query next sequence numbers",
 			"err", err,
@@ -495,7 +445,7 @@ WitnessAnEvent:
 		return
 	}
 
-	tx := types.NewTransaction(nil, "bridge.Release", Release{
+	tx := types.NewTransaction(nil, "bridge.Release", bridge.Release{
 		ID:     sequences.Incoming,
 		Target: lastUser,
 		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
@@ -508,26 +458,39 @@ WitnessAnEvent:
 		)
 		return
 	}
-	_, err = rc.SubmitTx(ctx, tb.UnverifiedTransaction())
-	if err != nil {
+	receipt, err := bridge.SubmitWithReceipt(ctx, rc, tb.UnverifiedTransaction())
+	switch {
+	case errors.Is(err, bridge.ErrReceiptNotFound):
+		// Executed nonetheless, so it must not be submitted again.
+		logger.Warn("failed to determine round of release transaction",
+			"err", err,
+		)
+	case err != nil:
 		logger.Error("failed to submit release transaction",
 			"err", err,
 		)
 		return
 	}
 
-	logger.Info("release successful")
+	logger.Info("release successful",
+		"round", receipt.Round,
+	)
 
 	// Make sure all witnesses release before proceeding to make sure the bridge is ready for the
 	// next release (e.g., the sequence number is incremented).
-	releaseWg.Done()
-	releaseWg.Wait()
+	barrier.Done()
+	if err = barrier.Wait(ctx); err != nil {
+		logger.Error("failed to wait for other witnesses to release",
+			"err", err,
+		)
+		return
+	}
 
 	// Simulating remote release.
 	logger.Info("simulating remote release")
 
 	// Get nonce.
-	nonce, err = rc.Accounts.Nonce(ctx, client.RoundLatest, types.NewAddress(signer.Public()))
+	nonce, err = rc.Accounts.Nonce(ctx, client.RoundLatest, bridge.AddressOf(signer))
 	if err != nil {
 		logger.Error("failed to fetch account nonce",
 			"err", err,
@@ -535,7 +498,7 @@ WitnessAnEvent:
 		return
 	}
 
-	tx = types.NewTransaction(nil, "bridge.Release", Release{
+	tx = types.NewTransaction(nil, "bridge.Release", bridge.Release{
 		ID:     sequences.Incoming + 1,
 		Target: lastUser,
 		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.Denomination("oETH")),
@@ -548,15 +511,23 @@ WitnessAnEvent:
 		)
 		return
 	}
-	_, err = rc.SubmitTx(ctx, tb.UnverifiedTransaction())
-	if err != nil {
+	receipt, err = bridge.SubmitWithReceipt(ctx, rc, tb.UnverifiedTransaction())
+	switch {
+	case errors.Is(err, bridge.ErrReceiptNotFound):
+		// Executed nonetheless, so it must not be submitted again.
+		logger.Warn("failed to determine round of release transaction",
+			"err", err,
+		)
+	case err != nil:
 		logger.Error("failed to submit release transaction",
 			"err", err,
 		)
 		return
 	}
 
-	logger.Info("remote release successful")
+	logger.Info("remote release successful",
+		"round", receipt.Round,
+	)
 }
 
 func main() {
@@ -568,6 +539,8 @@ func main() {
 
 	// Load node address.
 	addr := getEnvVarOrExit(GrpcAddrEnvVar)
+	// Raw event logging is very noisy, so it is only enabled on request.
+	logRawEvents = os.Getenv(LogRawEventsEnvVar) != ""
 	// Load bridge runtime ID.
 	var runtimeID common.Namespace
 	if err := runtimeID.UnmarshalHex(getEnvVarOrExit(RuntimeIDEnvVar)); err != nil {
@@ -595,6 +568,7 @@ func main() {
 	rc := &Client{
 		RuntimeClient: c,
 		Accounts:      accounts.NewV1(c),
+		Bridge:        bridge.NewV1(c),
 	}
 
 	ctx := context.Background()
@@ -605,17 +579,37 @@ func main() {
 		)
 		os.Exit(1)
 	}
+	if expected := os.Getenv(ChainContextEnvVar); expected != "" {
+		if err = bridge.VerifyChainContext(ctx, rc, signature.Context(expected)); err != nil {
+			logger.Error("connected to the wrong network",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	// Locks made by the user.
+	locks := []*bridge.Lock{
+		{
+			Target: bridge.NewRemoteAddressFromHex("0000000000000000000000000000000000000000"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+		},
+		{
+			Target: bridge.NewRemoteAddressFromHex("0000000000000000000000000000000000000001"),
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(5), types.NativeDenomination),
+		},
+	}
 
 	// Start witness and user.
-	var wg, releaseWg sync.WaitGroup
-	wg.Add(3)        // 2 witnesses, 1 user
-	releaseWg.Add(2) // 2 witnesses
+	var wg sync.WaitGroup
+	wg.Add(3) // 2 witnesses, 1 user
+	barrier := bridge.NewReleaseBarrier(2)
 
 	// Start two witnesses.
-	go witness(ctx, &wg, &releaseWg, rc, info.ChainContext, testing.Bob.Signer)
-	go witness(ctx, &wg, &releaseWg, rc, info.ChainContext, testing.Dave.Signer)
+	go witness(ctx, &wg, barrier, rc, info.ChainContext, testing.Bob.Signer, len(locks))
+	go witness(ctx, &wg, barrier, rc, info.ChainContext, testing.Dave.Signer, len(locks))
 	// Start one user.
-	go user(ctx, &wg, rc, info.ChainContext, testing.Alice.Signer)
+	go user(ctx, &wg, rc, testing.Alice.Signer, locks)
 
 	wg.Wait()
 